@@ -4,8 +4,6 @@ import (
 	"context"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/joho/godotenv"
 	_ "github.com/viniciuscluna/tc-fiap-50/docs"
@@ -24,30 +22,28 @@ func main() {
 		log.Println("Warning: .env file not found, using environment variables or defaults")
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Capture system signals for graceful shutdown
-	go func() {
-		c := make(chan os.Signal, 1)
-		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-		<-c
-		cancel()
-	}()
-
 	// Initialize the application using Uber FX
 	app := app.InitializeApp()
 
 	// Start the Uber FX lifecycle
-	if err := app.Start(ctx); err != nil {
+	startCtx, cancelStart := context.WithTimeout(context.Background(), app.StartTimeout())
+	err := app.Start(startCtx)
+	cancelStart()
+	if err != nil {
 		log.Fatalf("Error while starting app: %v", err)
 	}
 
-	// Wait until the context is canceled
-	<-ctx.Done()
+	// Wait for SIGINT/SIGTERM, or for the app to ask to shut down
+	signal := <-app.Wait()
 
-	// Stop the Uber FX lifecycle
-	if err := app.Stop(ctx); err != nil {
+	// Stop the Uber FX lifecycle, with a fresh context so the stop hooks get
+	// their whole timeout to drain traffic and finish the requests in flight
+	stopCtx, cancelStop := context.WithTimeout(context.Background(), app.StopTimeout())
+	err = app.Stop(stopCtx)
+	cancelStop()
+	if err != nil {
 		log.Fatalf("Error while stopping app: %v", err)
 	}
+
+	os.Exit(signal.ExitCode)
 }
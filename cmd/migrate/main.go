@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/logging"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/migrate"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/postgres"
+)
+
+const usage = `Usage: migrate <command>
+
+Commands:
+  up              Apply every pending migration
+  down [N]        Revert the last N applied migrations (default 1)
+  goto VERSION    Apply or revert migrations up to VERSION (0 reverts all)
+  status          List the migrations and whether they are applied
+
+The database is configured like the API (DB_* settings, CONFIG_FILE, .env).
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Load .env file
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using environment variables or defaults")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	logger, err := logging.New(cfg.LogLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = logger.Sync() }()
+
+	db, err := postgres.Open(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	migrator, err := postgres.NewMigrator(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	// Stop waiting for the lock or the migration on Ctrl+C; the transaction
+	// of the migration in progress is rolled back
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, migrator, os.Args[1], os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, migrator *migrate.Migrator, command string, args []string) error {
+	switch command {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			var err error
+			if steps, err = strconv.Atoi(args[0]); err != nil || steps < 1 {
+				return fmt.Errorf("down: invalid number of migrations %q", args[0])
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case "goto":
+		if len(args) == 0 {
+			return fmt.Errorf("goto: missing version")
+		}
+		version, err := strconv.ParseUint(args[0], 10, 0)
+		if err != nil {
+			return fmt.Errorf("goto: invalid version %q", args[0])
+		}
+		return migrator.Goto(ctx, uint(version))
+	case "status":
+		return printStatus(ctx, migrator)
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+}
+
+func printStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	return w.Flush()
+}
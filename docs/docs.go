@@ -111,6 +111,12 @@ const docTemplate = `{
                 ],
                 "summary": "Add order",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original order",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Body",
                         "name": "body",
@@ -127,6 +133,36 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.GetOrderResponseDto"
                         }
+                    },
+                    "400": {
+                        "description": "Malformed JSON or unknown fields",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "409": {
+                        "description": "Idempotency key reused with a different body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid fields, unknown customer, unknown product or price mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Customer or product service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
                     }
                 }
             }
@@ -159,6 +195,86 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.GetOrderResponseDto"
                         }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/order/{orderId}/cancel": {
+            "post": {
+                "description": "Cancel an order that is not yet Pronto or Finalizado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Cancel order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancellation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelOrderRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "409": {
+                        "description": "Order can no longer be cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown reason code or missing author",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
                     }
                 }
             }
@@ -191,6 +307,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.GetOrderStatusResponseDto"
                         }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
                     }
                 }
             },
@@ -227,6 +355,36 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status transition",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
+                    },
+                    "422": {
+                        "description": "Missing or unknown status",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetailsDto"
+                        }
                     }
                 }
             }
@@ -443,12 +601,17 @@ const docTemplate = `{
             }
         },
         "dto.AddOrderDto": {
+            "description": "AddOrderDto is the order creation request. Omit customerId for a guest order; guestName is an optional display name for the pickup panel.",
             "type": "object",
             "properties": {
                 "customerId": {
                     "type": "integer",
                     "example": 1
                 },
+                "guestName": {
+                    "type": "string",
+                    "example": "Maria"
+                },
                 "products": {
                     "type": "array",
                     "items": {
@@ -517,6 +680,33 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.CancelOrderRequestDto": {
+            "description": "CancelOrderRequestDto is the cancellation request. ReasonCode is one of CUSTOMER_REQUEST, PAYMENT_FAILED, OUT_OF_STOCK or OTHER.",
+            "type": "object",
+            "properties": {
+                "cancelledBy": {
+                    "type": "string",
+                    "example": "kiosk-01"
+                },
+                "reasonCode": {
+                    "type": "string",
+                    "example": "CUSTOMER_REQUEST"
+                }
+            }
+        },
+        "dto.FieldErrorDto": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "products[0].quantity"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be between 1 and 99"
+                }
+            }
+        },
         "dto.GetCustomerResponseDto": {
             "type": "object",
             "properties": {
@@ -538,6 +728,7 @@ const docTemplate = `{
             }
         },
         "dto.GetOrderResponseDto": {
+            "description": "GetOrderResponseDto is the presented order. Guest orders have no customer_id or customer and may carry a guest_name.",
             "type": "object",
             "properties": {
                 "created_at": {
@@ -546,6 +737,12 @@ const docTemplate = `{
                 "customer": {
                     "$ref": "#/definitions/dto.GetCustomerResponseDto"
                 },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "guest_name": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -569,6 +766,9 @@ const docTemplate = `{
         "dto.GetOrderStatusResponseDto": {
             "type": "object",
             "properties": {
+                "changed_by": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -583,6 +783,9 @@ const docTemplate = `{
                 },
                 "order_id": {
                     "type": "integer"
+                },
+                "reason_code": {
+                    "type": "string"
                 }
             }
         },
@@ -649,6 +852,37 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.ProblemDetailsDto": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "order not found"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FieldErrorDto"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/v1/order/42"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Resource not found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "/problems/not-found"
+                }
+            }
+        },
         "dto.UpdateOrderStatusRequestDto": {
             "type": "object",
             "properties": {
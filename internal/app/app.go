@@ -2,24 +2,41 @@ package app
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"time"
 
-	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
 
 	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/cache"
 	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/health"
 	"github.com/viniciuscluna/tc-fiap-50/internal/shared/httpclient"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/logging"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/metrics"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
 
 	orderController "github.com/viniciuscluna/tc-fiap-50/internal/order/controller"
 	orderRepositories "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
 	orderApiController "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/controller"
+	orderMetrics "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/metrics"
 	orderPersistence "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence"
+	orderMemory "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence/memory"
 	orderPresenter "github.com/viniciuscluna/tc-fiap-50/internal/order/presenter"
+	orderUseCases "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase"
 	orderUseCasesAdd "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/addOrder"
+	orderUseCasesCancel "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/cancelOrder"
 	orderUseCasesGet "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/getOrder"
 	orderUseCasesGetOrderStatus "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/getOrderStatus"
 	orderUseCasesGetOrders "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/getOrders"
@@ -27,65 +44,281 @@ import (
 
 	"github.com/viniciuscluna/tc-fiap-50/pkg/rest"
 	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/postgres"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/sqlite"
 )
 
+// retryBudgetBurst is how many retries the HTTP client can make in a row
+// before the retry budget limits them to a share of the requests.
+const retryBudgetBurst = 10
+
+// stopTimeout bounds the whole shutdown. It must leave room for the readiness
+// drain delay plus the HTTP server shutdown timeout.
+const stopTimeout = time.Minute
+
 func InitializeApp() *fx.App {
 	return fx.New(
+		fx.StopTimeout(stopTimeout),
+		fx.WithLogger(func(logger *zap.Logger) fxevent.Logger {
+			fxLogger := &fxevent.ZapLogger{Logger: logger}
+			fxLogger.UseLogLevel(zapcore.DebugLevel)
+			return fxLogger
+		}),
 		fx.Provide(
 			// Configuration
 			config.Load,
 
-			// Database
-			postgres.NewPostgresDB,
+			// Logging
+			newLogger,
 
-			// HTTP Client
-			func(cfg *config.Config) httpclient.HTTPClient {
-				return httpclient.NewHTTPClient(cfg.HTTPClientTimeout, cfg.HTTPClientRetryCount, cfg.HTTPClientRetryBackoff)
-			},
+			// Metrics
+			fx.Annotate(metrics.NewRegistry, fx.As(new(prometheus.Registerer)), fx.As(new(prometheus.Gatherer))),
+			metrics.NewHTTPMetrics,
+			fx.Annotate(orderMetrics.NewOrderMetrics, fx.As(new(orderUseCases.OrderRecorder))),
 
-			// External Service Clients
-			fx.Annotate(
-				func(httpClient httpclient.HTTPClient, cfg *config.Config) clients.CustomerClient {
-					return clients.NewCustomerClientImpl(httpClient, cfg.CustomerServiceURL)
-				},
-				fx.As(new(clients.CustomerClient)),
-			),
-			fx.Annotate(
-				func(httpClient httpclient.HTTPClient, cfg *config.Config) clients.ProductClient {
-					return clients.NewProductClientImpl(httpClient, cfg.ProductServiceURL)
-				},
-				fx.As(new(clients.ProductClient)),
-			),
+			// Storage (order repositories of the configured driver)
+			newStorage,
 
-			// Order Repositories
-			fx.Annotate(orderPersistence.NewOrderRepositoryImpl, fx.As(new(orderRepositories.OrderRepository))),
-			fx.Annotate(orderPersistence.NewOrderProductRepositoryImpl, fx.As(new(orderRepositories.OrderProductRepository))),
-			fx.Annotate(orderPersistence.NewOrderStatusRepositoryImpl, fx.As(new(orderRepositories.OrderStatusRepository))),
+			// Health Checks
+			newReadiness,
+
+			// HTTP Client (with a circuit breaker per downstream host)
+			func(cfg *config.Config, registerer prometheus.Registerer) httpclient.HTTPClient {
+				breaker := httpclient.NewCircuitBreakerClient(
+					httpclient.NewHTTPClient(
+						cfg.HTTPClientTimeout,
+						httpclient.NewExponentialBackoffPolicy(cfg.HTTPClientRetryCount, cfg.HTTPClientRetryBackoff, cfg.HTTPClientRetryMaxWait),
+						httpclient.NewRetryBudget(cfg.HTTPClientRetryBudget, retryBudgetBurst),
+					),
+					httpclient.CircuitBreakerSettings{
+						ConsecutiveFailures: cfg.CircuitBreakerConsecutiveFailures,
+						FailureRate:         cfg.CircuitBreakerFailureRate,
+						WindowSize:          cfg.CircuitBreakerWindowSize,
+						MinRequests:         cfg.CircuitBreakerMinRequests,
+						Cooldown:            cfg.CircuitBreakerCooldown,
+					},
+				)
+				return httpclient.NewInstrumentedClient(breaker, registerer)
+			},
 
 			// Order Use Cases (now with client dependencies)
+			newAddOrderSettings,
 			fx.Annotate(orderUseCasesAdd.NewAddOrderUseCaseImpl, fx.As(new(orderUseCasesAdd.AddOrderUseCase))),
 			fx.Annotate(orderUseCasesGet.NewGetOrderUseCaseImpl, fx.As(new(orderUseCasesGet.GetOrderUseCase))),
 			fx.Annotate(orderUseCasesGetOrders.NewGetOrdersUseCaseImpl, fx.As(new(orderUseCasesGetOrders.GetOrdersUseCase))),
 			fx.Annotate(orderUseCasesGetOrderStatus.NewGetOrderStatusUseCaseImpl, fx.As(new(orderUseCasesGetOrderStatus.GetOrderStatusUseCase))),
 			fx.Annotate(orderUseCasesUpdateOrderStatus.NewUpdateOrderStatusUseCaseImpl, fx.As(new(orderUseCasesUpdateOrderStatus.UpdateOrderStatusUseCase))),
+			fx.Annotate(orderUseCasesCancel.NewCancelOrderUseCaseImpl, fx.As(new(orderUseCasesCancel.CancelOrderUseCase))),
 
 			// Order Controller and Presenter (with client dependencies)
 			fx.Annotate(orderController.NewOrderControllerImpl, fx.As(new(orderController.OrderController))),
-			fx.Annotate(orderPresenter.NewOrderPresenterImpl, fx.As(new(orderPresenter.OrderPresenter))),
+			fx.Annotate(
+				orderPresenter.NewOrderPresenterImpl,
+				fx.ParamTags(`name:"cached"`, `name:"cached"`),
+				fx.As(new(orderPresenter.OrderPresenter)),
+			),
 			chi.NewRouter,
+			newHTTPServer,
 			func(orderController orderController.OrderController) []rest.Controller {
 				return []rest.Controller{
 					orderApiController.NewOrderController(orderController),
 				}
 			},
 		),
+		externalClients,
+		fx.Invoke(setupTracing),
+		fx.Invoke(registerActiveOrdersCollector),
+		fx.Invoke(registerClientCacheCollector),
 		fx.Invoke(registerRoutes),
 		fx.Invoke(startHTTPServer),
+		// Invoked last so its hook is the first to run on stop
+		fx.Invoke(drainOnShutdown),
 	)
 }
 
-func registerRoutes(r *chi.Mux, controllers []rest.Controller) {
-	r.Use(middleware.Logger)
+// externalClients provides the customer and product clients. Orders are
+// verified and priced from the services themselves, so the use cases get the
+// uncached clients; the presenter, which only displays customer and product
+// details, gets the cached ones, named "cached".
+var externalClients = fx.Options(
+	fx.Provide(
+		func(httpClient httpclient.HTTPClient, cfg *config.Config) clients.CustomerClient {
+			return clients.NewCustomerClientImpl(httpClient, cfg.CustomerServiceURL)
+		},
+		func(httpClient httpclient.HTTPClient, cfg *config.Config) clients.ProductClient {
+			return clients.NewProductClientImpl(httpClient, cfg.ProductServiceURL, cfg.ProductServiceBatchPath, cfg.ProductClientMaxConcurrency)
+		},
+		func(client clients.CustomerClient, cfg *config.Config) *clients.CachedCustomerClient {
+			return clients.NewCachedCustomerClient(client, cfg.CustomerCacheTTL, cfg.ClientCacheNegativeTTL, cfg.ClientCacheMaxSize)
+		},
+		func(client clients.ProductClient, cfg *config.Config) *clients.CachedProductClient {
+			return clients.NewCachedProductClient(client, cfg.ProductCacheTTL, cfg.ClientCacheNegativeTTL, cfg.ClientCacheMaxSize)
+		},
+		fx.Annotate(
+			func(client *clients.CachedCustomerClient) clients.CustomerClient { return client },
+			fx.ResultTags(`name:"cached"`),
+		),
+		fx.Annotate(
+			func(client *clients.CachedProductClient) clients.ProductClient { return client },
+			fx.ResultTags(`name:"cached"`),
+		),
+	),
+)
+
+// newAddOrderSettings takes the rules of order creation from the configuration.
+func newAddOrderSettings(cfg *config.Config) orderUseCasesAdd.Settings {
+	return orderUseCasesAdd.Settings{
+		IdempotencyKeyTTL:         cfg.IdempotencyKeyTTL,
+		RejectPriceMismatch:       cfg.OrderPriceMismatchPolicy == config.PriceMismatchPolicyReject,
+		AcceptUnverifiedCustomers: cfg.CustomerServiceUnavailablePolicy == config.CustomerUnavailablePolicyAccept,
+	}
+}
+
+// newLogger creates the application logger, also used by code running outside
+// a request, and logs the configuration in use (secrets redacted).
+func newLogger(lc fx.Lifecycle, cfg *config.Config) (*zap.Logger, error) {
+	logger, err := logging.New(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	zap.ReplaceGlobals(logger)
+
+	logger.Debug("Configuration loaded", zap.Any("config", cfg))
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			_ = logger.Sync()
+			return nil
+		},
+	})
+
+	return logger, nil
+}
+
+// setupTracing installs the configured tracer provider and the W3C trace
+// context propagator as the global ones, used by every instrumented component,
+// and flushes the buffered spans on stop.
+func setupTracing(lc fx.Lifecycle, cfg *config.Config, logger *zap.Logger) error {
+	provider, err := tracing.NewTracerProvider(context.Background(), tracing.Settings{
+		Exporter:     cfg.TracingExporter,
+		ServiceName:  cfg.TracingServiceName,
+		OTLPEndpoint: cfg.TracingOTLPEndpoint,
+		SampleRatio:  cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(tracing.Propagator())
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("tracing error", zap.Error(err))
+	}))
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return provider.Shutdown(ctx)
+		},
+	})
+
+	return nil
+}
+
+func registerActiveOrdersCollector(registerer prometheus.Registerer, repository orderRepositories.OrderStatusRepository, logger *zap.Logger) {
+	registerer.MustRegister(orderMetrics.NewActiveOrdersCollector(repository, logger))
+}
+
+// registerClientCacheCollector exposes the hit, miss, stale and eviction
+// counters of the client caches.
+func registerClientCacheCollector(registerer prometheus.Registerer, customers *clients.CachedCustomerClient, products *clients.CachedProductClient) {
+	registerer.MustRegister(cache.NewCollector(map[string]cache.StatsSource{
+		"customer": customers,
+		"product":  products,
+	}))
+}
+
+// storage is the persistence of orders, provided by the configured storage
+// driver.
+type storage struct {
+	fx.Out
+
+	Orders          orderRepositories.OrderRepository
+	OrderProducts   orderRepositories.OrderProductRepository
+	OrderStatus     orderRepositories.OrderStatusRepository
+	UnitOfWork      orderRepositories.UnitOfWork
+	IdempotencyKeys orderRepositories.IdempotencyKeyRepository
+	// Database is pinged for readiness, nil when there is no database
+	Database health.Pinger
+}
+
+// newStorage creates the order repositories of the configured storage driver:
+// Postgres, SQLite or memory, to run the service without a database.
+func newStorage(cfg *config.Config, logger *zap.Logger, registerer prometheus.Registerer) (storage, error) {
+	var db *gorm.DB
+	switch cfg.StorageDriver {
+	case config.StorageDriverMemory:
+		store := orderMemory.NewStore()
+		return storage{
+			Orders:          orderMemory.NewOrderRepositoryImpl(store),
+			OrderProducts:   orderMemory.NewOrderProductRepositoryImpl(store),
+			OrderStatus:     orderMemory.NewOrderStatusRepositoryImpl(store),
+			UnitOfWork:      orderMemory.NewUnitOfWorkImpl(store),
+			IdempotencyKeys: orderMemory.NewIdempotencyKeyRepositoryImpl(store),
+		}, nil
+	case config.StorageDriverSQLite:
+		db = sqlite.NewSQLiteDB(cfg, logger, registerer)
+	default:
+		db = postgres.NewPostgresDB(cfg, logger, registerer)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return storage{}, err
+	}
+	return storage{
+		Orders:          orderPersistence.NewOrderRepositoryImpl(db),
+		OrderProducts:   orderPersistence.NewOrderProductRepositoryImpl(db),
+		OrderStatus:     orderPersistence.NewOrderStatusRepositoryImpl(db),
+		UnitOfWork:      orderPersistence.NewUnitOfWorkImpl(db),
+		IdempotencyKeys: orderPersistence.NewIdempotencyKeyRepositoryImpl(db),
+		Database:        sqlDB,
+	}, nil
+}
+
+// newReadiness creates the readiness of the service, which needs the database
+// if any. The customer and product services are checked when their health
+// path is set, but only reported: orders degrade gracefully without them.
+func newReadiness(cfg *config.Config, database health.Pinger) *health.Readiness {
+	var checks []health.Check
+	if database != nil {
+		checks = append(checks, health.Check{Name: "database", Critical: true, Run: health.PingCheck(database)})
+	}
+	downstream := &http.Client{Timeout: cfg.ReadinessCheckTimeout}
+	if cfg.CustomerServiceHealthPath != "" {
+		checks = append(checks, health.Check{
+			Name: "customer_service",
+			Run:  health.HTTPCheck(downstream, cfg.CustomerServiceURL+cfg.CustomerServiceHealthPath),
+		})
+	}
+	if cfg.ProductServiceHealthPath != "" {
+		checks = append(checks, health.Check{
+			Name: "product_service",
+			Run:  health.HTTPCheck(downstream, cfg.ProductServiceURL+cfg.ProductServiceHealthPath),
+		})
+	}
+
+	return health.NewReadiness(checks, cfg.ReadinessCheckTimeout, cfg.ReadinessCacheTTL)
+}
+
+func registerRoutes(r *chi.Mux, controllers []rest.Controller, logger *zap.Logger, httpMetrics *metrics.HTTPMetrics, gatherer prometheus.Gatherer, readiness *health.Readiness) {
+	r.Use(tracing.Middleware)
+	r.Use(logging.Middleware(logger))
+	r.Use(httpMetrics.Middleware)
+
+	// Prometheus metrics
+	r.Handle("/metrics", metrics.Handler(gatherer))
+
+	// Kubernetes probes
+	r.Handle("/healthz", health.LivenessHandler())
+	r.Handle("/readyz", readiness)
 
 	// Swagger UI
 	r.Get("/swagger/*", httpSwagger.Handler(
@@ -97,19 +330,70 @@ func registerRoutes(r *chi.Mux, controllers []rest.Controller) {
 	}
 }
 
-func startHTTPServer(lc fx.Lifecycle, r *chi.Mux) {
+// newHTTPServer creates the API server from the configuration. The timeouts
+// keep slow or idle clients from holding connections forever.
+func newHTTPServer(cfg *config.Config, r *chi.Mux, logger *zap.Logger) *http.Server {
+	return &http.Server{
+		Addr:              ":" + cfg.ServerPort,
+		Handler:           r,
+		ReadTimeout:       cfg.ServerReadTimeout,
+		ReadHeaderTimeout: cfg.ServerReadHeaderTimeout,
+		WriteTimeout:      cfg.ServerWriteTimeout,
+		IdleTimeout:       cfg.ServerIdleTimeout,
+		MaxHeaderBytes:    cfg.ServerMaxHeaderBytes,
+		ErrorLog:          zap.NewStdLog(logger.Named("http")),
+	}
+}
+
+// startHTTPServer binds the server port when the app starts, failing the start
+// if it is taken, and on stop lets the requests in flight finish within the
+// shutdown timeout before closing the remaining connections.
+func startHTTPServer(lc fx.Lifecycle, shutdowner fx.Shutdowner, server *http.Server, cfg *config.Config, logger *zap.Logger) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+			}
+
+			logger.Info("Starting HTTP server", zap.String("addr", listener.Addr().String()))
 			go func() {
-				log.Println("Starting HTTP server on :8080")
-				if err := http.ListenAndServe(":8080", r); err != nil {
-					log.Fatalf("Failed to start HTTP server: %v", err)
+				if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Error("HTTP server failed", zap.Error(err))
+					_ = shutdowner.Shutdown(fx.ExitCode(1))
 				}
 			}()
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			log.Println("Shutting down HTTP server gracefully")
+			logger.Info("Shutting down HTTP server gracefully", zap.Duration("timeout", cfg.ServerShutdownTimeout))
+			ctx, cancel := context.WithTimeout(ctx, cfg.ServerShutdownTimeout)
+			defer cancel()
+
+			if err := server.Shutdown(ctx); err != nil {
+				_ = server.Close()
+				return fmt.Errorf("HTTP server did not shut down in time: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+// drainOnShutdown makes the service report itself as not ready as soon as it
+// is asked to stop, then gives the load balancer time to notice before the
+// HTTP server shuts down.
+func drainOnShutdown(lc fx.Lifecycle, readiness *health.Readiness, cfg *config.Config, logger *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			readiness.Drain()
+			logger.Info("Draining before shutdown", zap.Duration("delay", cfg.ShutdownDrainDelay))
+
+			timer := time.NewTimer(cfg.ShutdownDrainDelay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+			}
 			return nil
 		},
 	})
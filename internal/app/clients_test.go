@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/httpclient"
+	mockHTTPClient "github.com/viniciuscluna/tc-fiap-50/mocks/shared/httpclient"
+)
+
+type externalClientsParams struct {
+	fx.In
+
+	CustomerClient       clients.CustomerClient
+	ProductClient        clients.ProductClient
+	CachedCustomerClient clients.CustomerClient `name:"cached"`
+	CachedProductClient  clients.ProductClient  `name:"cached"`
+}
+
+// Feature: External service clients
+// Scenario: Create orders from the services, display them from the cache
+func Test_ExternalClients_ShouldCacheOnlyTheNamedClients(t *testing.T) {
+	// GIVEN the configuration of the clients
+	cfg := &config.Config{
+		CustomerServiceURL: "http://customer-service",
+		ProductServiceURL:  "http://product-service",
+		CustomerCacheTTL:   time.Minute,
+		ProductCacheTTL:    time.Minute,
+		ClientCacheMaxSize: 10,
+	}
+
+	// WHEN the clients are provided
+	var params externalClientsParams
+	fxtest.New(t,
+		externalClients,
+		fx.Supply(cfg, fx.Annotate(mockHTTPClient.NewMockHTTPClient(t), fx.As(new(httpclient.HTTPClient)))),
+		fx.Populate(&params),
+	).RequireStart().RequireStop()
+
+	// THEN the unnamed clients, used by the use cases, should not be cached
+	_, customerCached := params.CustomerClient.(*clients.CachedCustomerClient)
+	_, productCached := params.ProductClient.(*clients.CachedProductClient)
+	assert.False(t, customerCached)
+	assert.False(t, productCached)
+	// AND the named ones, used by the presenter, should be
+	assert.IsType(t, &clients.CachedCustomerClient{}, params.CachedCustomerClient)
+	assert.IsType(t, &clients.CachedProductClient{}, params.CachedProductClient)
+}
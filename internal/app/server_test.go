@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+)
+
+// fakeShutdowner records the shutdown requested by the server.
+type fakeShutdowner struct {
+	requested bool
+}
+
+func (s *fakeShutdowner) Shutdown(...fx.ShutdownOption) error {
+	s.requested = true
+	return nil
+}
+
+func newTestServerConfig(t *testing.T) *config.Config {
+	// Take a free port for the server
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, listener.Close())
+
+	return &config.Config{
+		ServerPort:              port,
+		ServerReadTimeout:       time.Second,
+		ServerReadHeaderTimeout: time.Second,
+		ServerWriteTimeout:      5 * time.Second,
+		ServerIdleTimeout:       time.Second,
+		ServerMaxHeaderBytes:    64 * 1024,
+		ServerShutdownTimeout:   5 * time.Second,
+	}
+}
+
+// Feature: HTTP server lifecycle
+// Scenario: Fail to start when the port is taken
+func Test_StartHTTPServer_WithPortInUse_ShouldFailStart(t *testing.T) {
+	// GIVEN a port already taken by another process
+	cfg := newTestServerConfig(t)
+	taken, err := net.Listen("tcp", ":"+cfg.ServerPort)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = taken.Close() })
+
+	lc := fxtest.NewLifecycle(t)
+	startHTTPServer(lc, &fakeShutdowner{}, newHTTPServer(cfg, chi.NewRouter(), zap.NewNop()), cfg, zap.NewNop())
+
+	// WHEN the app starts
+	err = lc.Start(context.Background())
+
+	// THEN the start should fail instead of the process dying later
+	assert.ErrorContains(t, err, "failed to listen on :"+cfg.ServerPort)
+}
+
+// Scenario: Finish the requests in flight on shutdown
+func Test_StartHTTPServer_OnStop_ShouldFinishRequestsInFlight(t *testing.T) {
+	// GIVEN a server handling a slow request
+	cfg := newTestServerConfig(t)
+	started := make(chan struct{})
+	router := chi.NewRouter()
+	router.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+	})
+
+	lc := fxtest.NewLifecycle(t)
+	shutdowner := &fakeShutdowner{}
+	startHTTPServer(lc, shutdowner, newHTTPServer(cfg, router, zap.NewNop()), cfg, zap.NewNop())
+	require.NoError(t, lc.Start(context.Background()))
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://127.0.0.1:" + cfg.ServerPort + "/slow")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	// WHEN the app stops
+	require.NoError(t, lc.Stop(context.Background()))
+
+	// THEN the request in flight should have been answered
+	got := <-response
+	require.NoError(t, got.err)
+	assert.Equal(t, "done", got.body)
+	// AND new connections should be refused
+	_, err := http.Get("http://127.0.0.1:" + cfg.ServerPort + "/slow")
+	assert.Error(t, err)
+	assert.False(t, shutdowner.requested)
+}
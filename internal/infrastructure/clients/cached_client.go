@@ -0,0 +1,25 @@
+package clients
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// shareCall runs fn once for all the concurrent callers using the same key.
+// fn runs detached from the caller's cancellation, so one caller giving up
+// doesn't fail the others, but each caller stops waiting when its ctx is done.
+func shareCall[V any](ctx context.Context, group *singleflight.Group, key string, fn func(ctx context.Context) (V, error)) (V, error) {
+	results := group.DoChan(key, func() (interface{}, error) {
+		return fn(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	case result := <-results:
+		value, _ := result.Val.(V)
+		return value, result.Err
+	}
+}
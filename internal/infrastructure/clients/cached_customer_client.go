@@ -0,0 +1,61 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/cache"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	_ CustomerClient = (*CachedCustomerClient)(nil)
+)
+
+// CachedCustomerClient caches the customers fetched by another CustomerClient.
+// Unknown customers are cached for the negative TTL, and the last known
+// customer is served while the customer service is unavailable.
+type CachedCustomerClient struct {
+	next  CustomerClient
+	cache *cache.Cache[uint, *CustomerDTO]
+	group singleflight.Group
+}
+
+func NewCachedCustomerClient(next CustomerClient, ttl time.Duration, negativeTTL time.Duration, maxSize int) *CachedCustomerClient {
+	return &CachedCustomerClient{
+		next:  next,
+		cache: cache.New[uint, *CustomerDTO](ttl, negativeTTL, maxSize),
+	}
+}
+
+func (c *CachedCustomerClient) GetCustomer(ctx context.Context, customerID uint) (*CustomerDTO, error) {
+	if entry, found := c.cache.Get(customerID); found {
+		return entry.Value, entry.Err
+	}
+
+	customer, err := shareCall(ctx, &c.group, strconv.FormatUint(uint64(customerID), 10), func(ctx context.Context) (*CustomerDTO, error) {
+		customer, err := c.next.GetCustomer(ctx, customerID)
+		switch {
+		case err == nil:
+			c.cache.Set(customerID, customer)
+		case errors.Is(err, ErrCustomerNotFound):
+			c.cache.SetError(customerID, err)
+		}
+		return customer, err
+	})
+
+	if err != nil && !errors.Is(err, ErrCustomerNotFound) && ctx.Err() == nil {
+		if stale, found := c.cache.Stale(customerID); found {
+			return stale, nil
+		}
+	}
+
+	return customer, err
+}
+
+// Stats returns the cache hit, miss, stale and eviction counters.
+func (c *CachedCustomerClient) Stats() cache.Stats {
+	return c.cache.Stats()
+}
@@ -0,0 +1,160 @@
+package clients_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/cache"
+	mockClients "github.com/viniciuscluna/tc-fiap-50/mocks/infrastructure/clients"
+)
+
+// Feature: Cached Customer Client
+// Scenario: Avoid fetching the same customer from the customer service again
+type CachedCustomerClientTestSuite struct {
+	suite.Suite
+	mockCustomerClient *mockClients.MockCustomerClient
+	client             *clients.CachedCustomerClient
+}
+
+func (suite *CachedCustomerClientTestSuite) SetupTest() {
+	suite.mockCustomerClient = mockClients.NewMockCustomerClient(suite.T())
+	suite.client = clients.NewCachedCustomerClient(suite.mockCustomerClient, time.Minute, time.Minute, 100)
+}
+
+func TestCachedCustomerClientTestSuite(t *testing.T) {
+	suite.Run(t, new(CachedCustomerClientTestSuite))
+}
+
+func (suite *CachedCustomerClientTestSuite) Test_GetCustomer_CalledTwice_ShouldFetchOnce() {
+	// GIVEN the customer service knows the customer
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(1)).
+		Return(&clients.CustomerDTO{ID: 1, Name: "John Doe"}, nil).
+		Once()
+
+	// WHEN the customer is fetched twice
+	_, firstErr := suite.client.GetCustomer(context.Background(), 1)
+	customer, secondErr := suite.client.GetCustomer(context.Background(), 1)
+
+	// THEN the second call should be served from the cache
+	assert.NoError(suite.T(), firstErr)
+	assert.NoError(suite.T(), secondErr)
+	assert.Equal(suite.T(), "John Doe", customer.Name)
+	assert.Equal(suite.T(), cache.Stats{Hits: 1, Misses: 1}, suite.client.Stats())
+}
+
+func (suite *CachedCustomerClientTestSuite) Test_GetCustomer_WithUnknownCustomer_ShouldCacheNotFound() {
+	// GIVEN the customer service doesn't know the customer
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(42)).
+		Return(nil, fmt.Errorf("failed to fetch customer 42: %w", clients.ErrCustomerNotFound)).
+		Once()
+
+	// WHEN the customer is fetched twice
+	_, firstErr := suite.client.GetCustomer(context.Background(), 42)
+	_, secondErr := suite.client.GetCustomer(context.Background(), 42)
+
+	// THEN both calls should report the customer as not found
+	assert.ErrorIs(suite.T(), firstErr, clients.ErrCustomerNotFound)
+	assert.ErrorIs(suite.T(), secondErr, clients.ErrCustomerNotFound)
+}
+
+func (suite *CachedCustomerClientTestSuite) Test_GetCustomer_WithServiceUnavailable_ShouldServeStaleCustomer() {
+	// GIVEN a client whose entries expire immediately
+	client := clients.NewCachedCustomerClient(suite.mockCustomerClient, 0, 0, 100)
+
+	// AND a customer fetched before the customer service went down
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(1)).
+		Return(&clients.CustomerDTO{ID: 1, Name: "John Doe"}, nil).
+		Once()
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(1)).
+		Return(nil, errors.New("connection refused")).
+		Once()
+	_, err := client.GetCustomer(context.Background(), 1)
+	assert.NoError(suite.T(), err)
+
+	// WHEN the customer is fetched again
+	customer, err := client.GetCustomer(context.Background(), 1)
+
+	// THEN the last known customer should be served
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "John Doe", customer.Name)
+	assert.Equal(suite.T(), int64(1), client.Stats().Stale)
+}
+
+func (suite *CachedCustomerClientTestSuite) Test_GetCustomer_WithServiceUnavailableAndNothingCached_ShouldReturnError() {
+	// GIVEN the customer service is down
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(1)).
+		Return(nil, errors.New("connection refused")).
+		Once()
+
+	// WHEN a customer never fetched before is requested
+	customer, err := suite.client.GetCustomer(context.Background(), 1)
+
+	// THEN the error should be returned
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), customer)
+}
+
+func (suite *CachedCustomerClientTestSuite) Test_GetCustomer_WithConcurrentMisses_ShouldFetchOnce() {
+	// GIVEN a slow customer service
+	release := make(chan struct{})
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(1)).
+		RunAndReturn(func(ctx context.Context, customerID uint) (*clients.CustomerDTO, error) {
+			<-release
+			return &clients.CustomerDTO{ID: customerID}, nil
+		}).
+		Once()
+
+	// WHEN the same customer is requested concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			customer, err := suite.client.GetCustomer(context.Background(), 1)
+			assert.NoError(suite.T(), err)
+			assert.Equal(suite.T(), uint(1), customer.ID)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// THEN the customer service should be called once
+	suite.mockCustomerClient.AssertNumberOfCalls(suite.T(), "GetCustomer", 1)
+}
+
+func (suite *CachedCustomerClientTestSuite) Test_GetCustomer_WithCancelledCaller_ShouldStopWaiting() {
+	// GIVEN a customer service that never answers
+	release := make(chan struct{})
+	defer close(release)
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(1)).
+		RunAndReturn(func(ctx context.Context, customerID uint) (*clients.CustomerDTO, error) {
+			<-release
+			return nil, errors.New("too late")
+		}).
+		Maybe()
+
+	// WHEN the caller gives up
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	customer, err := suite.client.GetCustomer(ctx, 1)
+
+	// THEN the call should return the caller's error
+	assert.ErrorIs(suite.T(), err, context.DeadlineExceeded)
+	assert.Nil(suite.T(), customer)
+}
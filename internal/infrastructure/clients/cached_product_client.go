@@ -0,0 +1,197 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/cache"
+)
+
+var (
+	_ ProductClient = (*CachedProductClient)(nil)
+)
+
+// CachedProductClient caches the products fetched by another ProductClient.
+// Unknown products are cached for the negative TTL, and the last known
+// product is served while the product service is unavailable.
+type CachedProductClient struct {
+	next  ProductClient
+	cache *cache.Cache[uint, *ProductDTO]
+
+	mu       sync.Mutex
+	inflight map[string]*productCall
+}
+
+// productCall is the lookup of one product, shared by every caller missing
+// that product while it is in flight.
+type productCall struct {
+	done    chan struct{}
+	product *ProductDTO
+	err     error
+}
+
+func NewCachedProductClient(next ProductClient, ttl time.Duration, negativeTTL time.Duration, maxSize int) *CachedProductClient {
+	return &CachedProductClient{
+		next:     next,
+		cache:    cache.New[uint, *ProductDTO](ttl, negativeTTL, maxSize),
+		inflight: make(map[string]*productCall),
+	}
+}
+
+func (c *CachedProductClient) GetProduct(ctx context.Context, productID uint) (*ProductDTO, error) {
+	if entry, found := c.cache.Get(productID); found {
+		return entry.Value, entry.Err
+	}
+
+	calls, claimed := c.claim([]uint{productID})
+	if len(claimed) > 0 {
+		go func(ctx context.Context) {
+			product, err := c.next.GetProduct(ctx, productID)
+			c.finish(productID, calls[productID], product, err)
+		}(context.WithoutCancel(ctx))
+	}
+
+	return c.wait(ctx, productID, calls[productID])
+}
+
+// GetProducts serves the cached products and fetches the others in a single
+// call to the wrapped client. Products already being fetched by a concurrent
+// call are awaited instead of being fetched again.
+func (c *CachedProductClient) GetProducts(ctx context.Context, productIDs []uint) ([]*ProductDTO, error) {
+	found := make(map[uint]*ProductDTO, len(productIDs))
+	failed := make(map[uint]error)
+
+	missing := make([]uint, 0, len(productIDs))
+	for _, productID := range productIDs {
+		entry, cached := c.cache.Get(productID)
+		switch {
+		case !cached:
+			missing = append(missing, productID)
+		case entry.Err != nil:
+			failed[productID] = entry.Err
+		default:
+			found[productID] = entry.Value
+		}
+	}
+
+	if len(missing) > 0 {
+		c.fetchMissing(ctx, missing, found, failed)
+	}
+
+	return collectProducts(productIDs, found, failed)
+}
+
+func (c *CachedProductClient) fetchMissing(ctx context.Context, missing []uint, found map[uint]*ProductDTO, failed map[uint]error) {
+	calls, claimed := c.claim(missing)
+	if len(claimed) > 0 {
+		go c.fetchClaimed(context.WithoutCancel(ctx), claimed, calls)
+	}
+
+	for _, productID := range missing {
+		product, err := c.wait(ctx, productID, calls[productID])
+		switch {
+		case err != nil:
+			failed[productID] = err
+		case product != nil:
+			found[productID] = product
+		}
+	}
+}
+
+// fetchClaimed fetches the claimed products in a single call and hands each
+// product, or its error, to the callers waiting for it.
+func (c *CachedProductClient) fetchClaimed(ctx context.Context, claimed []uint, calls map[uint]*productCall) {
+	products, err := c.next.GetProducts(ctx, claimed)
+
+	fetched := make(map[uint]*ProductDTO, len(products))
+	for _, product := range products {
+		fetched[product.ID] = product
+	}
+
+	// Without per product errors the whole call failed
+	var productsErr *ProductsError
+	errors.As(err, &productsErr)
+
+	for _, productID := range claimed {
+		var productErr error
+		switch {
+		case fetched[productID] != nil:
+		case productsErr != nil:
+			productErr = productsErr.Errors[productID]
+		default:
+			productErr = err
+		}
+		c.finish(productID, calls[productID], fetched[productID], productErr)
+	}
+}
+
+// claim returns the in flight lookup of each product, starting one for the
+// products nobody else is fetching. The started ones are returned as claimed
+// and must be completed by the caller with finish.
+func (c *CachedProductClient) claim(productIDs []uint) (map[uint]*productCall, []uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	calls := make(map[uint]*productCall, len(productIDs))
+	var claimed []uint
+	for _, productID := range productIDs {
+		if _, seen := calls[productID]; seen {
+			continue
+		}
+
+		call, inflight := c.inflight[productKey(productID)]
+		if !inflight {
+			call = &productCall{done: make(chan struct{})}
+			c.inflight[productKey(productID)] = call
+			claimed = append(claimed, productID)
+		}
+		calls[productID] = call
+	}
+	return calls, claimed
+}
+
+// finish caches the result of a claimed lookup and releases its waiters.
+func (c *CachedProductClient) finish(productID uint, call *productCall, product *ProductDTO, err error) {
+	switch {
+	case err == nil && product != nil:
+		c.cache.Set(productID, product)
+	case errors.Is(err, ErrProductNotFound):
+		c.cache.SetError(productID, err)
+	}
+
+	c.mu.Lock()
+	delete(c.inflight, productKey(productID))
+	c.mu.Unlock()
+
+	call.product, call.err = product, err
+	close(call.done)
+}
+
+// wait returns the result of the lookup, or the last known product when the
+// product service failed. The caller stops waiting when its ctx is done.
+func (c *CachedProductClient) wait(ctx context.Context, productID uint, call *productCall) (*ProductDTO, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-call.done:
+	}
+
+	if call.err != nil && !errors.Is(call.err, ErrProductNotFound) {
+		if stale, found := c.cache.Stale(productID); found {
+			return stale, nil
+		}
+	}
+	return call.product, call.err
+}
+
+func productKey(productID uint) string {
+	return "product:" + strconv.FormatUint(uint64(productID), 10)
+}
+
+// Stats returns the cache hit, miss, stale and eviction counters.
+func (c *CachedProductClient) Stats() cache.Stats {
+	return c.cache.Stats()
+}
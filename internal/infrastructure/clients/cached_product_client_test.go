@@ -0,0 +1,240 @@
+package clients_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
+	mockClients "github.com/viniciuscluna/tc-fiap-50/mocks/infrastructure/clients"
+)
+
+// Feature: Cached Product Client
+// Scenario: Avoid fetching the same products from the product service again
+type CachedProductClientTestSuite struct {
+	suite.Suite
+	mockProductClient *mockClients.MockProductClient
+	client            *clients.CachedProductClient
+}
+
+func (suite *CachedProductClientTestSuite) SetupTest() {
+	suite.mockProductClient = mockClients.NewMockProductClient(suite.T())
+	suite.client = clients.NewCachedProductClient(suite.mockProductClient, time.Minute, time.Minute, 100)
+}
+
+func TestCachedProductClientTestSuite(t *testing.T) {
+	suite.Run(t, new(CachedProductClientTestSuite))
+}
+
+func (suite *CachedProductClientTestSuite) Test_GetProducts_WithCachedProducts_ShouldOnlyFetchMissingOnes() {
+	// GIVEN products already fetched
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1, 2}).
+		Return([]*clients.ProductDTO{{ID: 1}, {ID: 2}}, nil).
+		Once()
+	_, err := suite.client.GetProducts(context.Background(), []uint{1, 2})
+	assert.NoError(suite.T(), err)
+
+	// AND a product that was not fetched yet
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{3}).
+		Return([]*clients.ProductDTO{{ID: 3}}, nil).
+		Once()
+
+	// WHEN the products are requested together
+	products, err := suite.client.GetProducts(context.Background(), []uint{2, 3, 1})
+
+	// THEN only the missing product should be fetched
+	assert.NoError(suite.T(), err)
+	// AND the products should be returned in the requested order
+	assert.Len(suite.T(), products, 3)
+	assert.Equal(suite.T(), uint(2), products[0].ID)
+	assert.Equal(suite.T(), uint(3), products[1].ID)
+	assert.Equal(suite.T(), uint(1), products[2].ID)
+	assert.Equal(suite.T(), int64(2), suite.client.Stats().Hits)
+}
+
+func (suite *CachedProductClientTestSuite) Test_GetProducts_WithUnknownProduct_ShouldCacheNotFound() {
+	// GIVEN the product service doesn't know one of the products
+	notFound := fmt.Errorf("failed to fetch product 99: %w", clients.ErrProductNotFound)
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1, 99}).
+		Return([]*clients.ProductDTO{{ID: 1}}, &clients.ProductsError{Errors: map[uint]error{99: notFound}}).
+		Once()
+	_, err := suite.client.GetProducts(context.Background(), []uint{1, 99})
+	assert.ErrorIs(suite.T(), err, clients.ErrProductNotFound)
+
+	// WHEN the products are requested again
+	products, err := suite.client.GetProducts(context.Background(), []uint{1, 99})
+
+	// THEN the unknown product should still be reported without calling the service
+	var productsErr *clients.ProductsError
+	assert.ErrorAs(suite.T(), err, &productsErr)
+	assert.True(suite.T(), productsErr.NotFound())
+	assert.Len(suite.T(), products, 1)
+	// AND a single product lookup should use the same cache
+	_, err = suite.client.GetProduct(context.Background(), 99)
+	assert.ErrorIs(suite.T(), err, clients.ErrProductNotFound)
+}
+
+func (suite *CachedProductClientTestSuite) Test_GetProducts_WithServiceUnavailable_ShouldServeStaleProducts() {
+	// GIVEN a client whose entries expire immediately
+	client := clients.NewCachedProductClient(suite.mockProductClient, 0, 0, 100)
+
+	// AND a product fetched before the product service went down
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1}).
+		Return([]*clients.ProductDTO{{ID: 1, Name: "Burger"}}, nil).
+		Once()
+	_, err := client.GetProducts(context.Background(), []uint{1})
+	assert.NoError(suite.T(), err)
+
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1, 2}).
+		Return(nil, errors.New("connection refused")).
+		Once()
+
+	// WHEN it is requested along with a product never fetched before
+	products, err := client.GetProducts(context.Background(), []uint{1, 2})
+
+	// THEN the last known product should be served
+	assert.Len(suite.T(), products, 1)
+	assert.Equal(suite.T(), "Burger", products[0].Name)
+	// AND only the other product should be reported as failed
+	var productsErr *clients.ProductsError
+	assert.ErrorAs(suite.T(), err, &productsErr)
+	assert.Len(suite.T(), productsErr.Errors, 1)
+	assert.Contains(suite.T(), productsErr.Errors, uint(2))
+	assert.Equal(suite.T(), int64(1), client.Stats().Stale)
+}
+
+func (suite *CachedProductClientTestSuite) Test_GetProduct_CalledTwice_ShouldFetchOnce() {
+	// GIVEN the product service knows the product
+	suite.mockProductClient.EXPECT().
+		GetProduct(mock.Anything, uint(1)).
+		Return(&clients.ProductDTO{ID: 1, Name: "Burger"}, nil).
+		Once()
+
+	// WHEN the product is fetched twice
+	_, firstErr := suite.client.GetProduct(context.Background(), 1)
+	product, secondErr := suite.client.GetProduct(context.Background(), 1)
+
+	// THEN the second call should be served from the cache
+	assert.NoError(suite.T(), firstErr)
+	assert.NoError(suite.T(), secondErr)
+	assert.Equal(suite.T(), "Burger", product.Name)
+	// AND the product should also be served to batch lookups
+	products, err := suite.client.GetProducts(context.Background(), []uint{1})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), products, 1)
+}
+
+func (suite *CachedProductClientTestSuite) Test_GetProducts_WithConcurrentMisses_ShouldFetchOnce() {
+	// GIVEN a slow product service
+	release := make(chan struct{})
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1, 2}).
+		RunAndReturn(func(ctx context.Context, productIDs []uint) ([]*clients.ProductDTO, error) {
+			<-release
+			return []*clients.ProductDTO{{ID: 1}, {ID: 2}}, nil
+		}).
+		Once()
+
+	// WHEN the same products are requested concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			products, err := suite.client.GetProducts(context.Background(), []uint{1, 2})
+			assert.NoError(suite.T(), err)
+			assert.Len(suite.T(), products, 2)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// THEN the product service should be called once
+	suite.mockProductClient.AssertNumberOfCalls(suite.T(), "GetProducts", 1)
+}
+
+func (suite *CachedProductClientTestSuite) Test_GetProducts_WithOverlappingMisses_ShouldFetchEachProductOnce() {
+	// GIVEN a slow product service already fetching products 1 and 2
+	started := make(chan struct{})
+	release := make(chan struct{})
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1, 2}).
+		RunAndReturn(func(ctx context.Context, productIDs []uint) ([]*clients.ProductDTO, error) {
+			close(started)
+			<-release
+			return []*clients.ProductDTO{{ID: 1}, {ID: 2, Name: "Fries"}}, nil
+		}).
+		Once()
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{3}).
+		Return([]*clients.ProductDTO{{ID: 3}}, nil).
+		Once()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		products, err := suite.client.GetProducts(context.Background(), []uint{1, 2})
+		assert.NoError(suite.T(), err)
+		assert.Len(suite.T(), products, 2)
+	}()
+	<-started
+
+	// WHEN products 2 and 3 are requested meanwhile
+	var products []*clients.ProductDTO
+	var err error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		products, err = suite.client.GetProducts(context.Background(), []uint{2, 3})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// THEN only product 3 should be fetched again
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), products, 2)
+	assert.Equal(suite.T(), "Fries", products[0].Name)
+	suite.mockProductClient.AssertNumberOfCalls(suite.T(), "GetProducts", 2)
+}
+
+func (suite *CachedProductClientTestSuite) Test_GetProduct_WhileBatchFetchesIt_ShouldShareTheBatch() {
+	// GIVEN a slow product service already fetching product 1
+	started := make(chan struct{})
+	release := make(chan struct{})
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1}).
+		RunAndReturn(func(ctx context.Context, productIDs []uint) ([]*clients.ProductDTO, error) {
+			close(started)
+			<-release
+			return []*clients.ProductDTO{{ID: 1, Name: "Burger"}}, nil
+		}).
+		Once()
+
+	go func() {
+		_, _ = suite.client.GetProducts(context.Background(), []uint{1})
+	}()
+	<-started
+
+	// WHEN the product is fetched on its own meanwhile
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+	product, err := suite.client.GetProduct(context.Background(), 1)
+
+	// THEN it should be served by the batch call
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Burger", product.Name)
+	suite.mockProductClient.AssertNotCalled(suite.T(), "GetProduct", mock.Anything, mock.Anything)
+}
@@ -1,6 +1,13 @@
 package clients
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrCustomerNotFound = errors.New("customer not found")
+)
 
 type CustomerDTO struct {
 	ID    uint   `json:"id"`
@@ -24,6 +24,9 @@ func (c *customerClientImpl) GetCustomer(ctx context.Context, customerID uint) (
 	var customer CustomerDTO
 
 	if err := c.httpClient.Get(ctx, url, &customer); err != nil {
+		if httpclient.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to fetch customer %d: %w: %w", customerID, ErrCustomerNotFound, err)
+		}
 		return nil, fmt.Errorf("failed to fetch customer %d: %w", customerID, err)
 	}
 
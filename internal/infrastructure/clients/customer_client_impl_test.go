@@ -3,10 +3,12 @@ package clients
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/httpclient"
 	mockHTTPClient "github.com/viniciuscluna/tc-fiap-50/mocks/shared/httpclient"
 )
 
@@ -185,3 +187,45 @@ func (suite *CustomerClientTestSuite) Test_GetCustomer_WithZeroId_ShouldAttemptF
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), result)
 }
+
+// Scenario: Get customer answered with 404 should return ErrCustomerNotFound
+func (suite *CustomerClientTestSuite) Test_GetCustomer_WithNotFoundStatus_ShouldReturnErrCustomerNotFound() {
+	// GIVEN a customer ID unknown to the customer service
+	customerID := uint(404)
+	expectedURL := "http://customer-service/v1/customer/404"
+	ctx := context.Background()
+
+	// AND the HTTP client returns a 404 status error
+	suite.mockHTTPClient.EXPECT().
+		Get(ctx, expectedURL, &CustomerDTO{}).
+		Return(fmt.Errorf("HTTP request failed with %w", &httpclient.StatusError{StatusCode: 404})).
+		Once()
+
+	// WHEN GetCustomer is called
+	result, err := suite.client.GetCustomer(ctx, customerID)
+
+	// THEN ErrCustomerNotFound should be returned
+	assert.ErrorIs(suite.T(), err, ErrCustomerNotFound)
+	assert.Nil(suite.T(), result)
+}
+
+// Scenario: Get customer with a server error should not be reported as not found
+func (suite *CustomerClientTestSuite) Test_GetCustomer_WithServerErrorStatus_ShouldNotReturnErrCustomerNotFound() {
+	// GIVEN a customer ID
+	customerID := uint(500)
+	expectedURL := "http://customer-service/v1/customer/500"
+	ctx := context.Background()
+
+	// AND the HTTP client returns a 500 status error
+	suite.mockHTTPClient.EXPECT().
+		Get(ctx, expectedURL, &CustomerDTO{}).
+		Return(&httpclient.StatusError{StatusCode: 500}).
+		Once()
+
+	// WHEN GetCustomer is called
+	_, err := suite.client.GetCustomer(ctx, customerID)
+
+	// THEN the error should not be a not found error
+	assert.Error(suite.T(), err)
+	assert.NotErrorIs(suite.T(), err, ErrCustomerNotFound)
+}
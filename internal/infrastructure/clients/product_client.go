@@ -1,17 +1,70 @@
 package clients
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+)
 
 type ProductDTO struct {
-	ID          uint    `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float32 `json:"price"`
-	Category    int     `json:"category"`
-	ImageLink   string  `json:"image_link"`
+	ID          uint        `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	Category    int         `json:"category"`
+	ImageLink   string      `json:"image_link"`
 }
 
 type ProductClient interface {
 	GetProduct(ctx context.Context, productID uint) (*ProductDTO, error)
+	// GetProducts returns the products that could be fetched, in the order
+	// they were requested. When some of them fail it also returns a
+	// *ProductsError holding the error of each missing product.
 	GetProducts(ctx context.Context, productIDs []uint) ([]*ProductDTO, error)
 }
+
+// ProductsError is returned by GetProducts when one or more products could not be fetched.
+type ProductsError struct {
+	Errors map[uint]error
+}
+
+func (e *ProductsError) Error() string {
+	productIDs := make([]uint, 0, len(e.Errors))
+	for productID := range e.Errors {
+		productIDs = append(productIDs, productID)
+	}
+	slices.Sort(productIDs)
+
+	messages := make([]string, len(productIDs))
+	for i, productID := range productIDs {
+		messages[i] = e.Errors[productID].Error()
+	}
+	return fmt.Sprintf("failed to fetch %d product(s): %s", len(productIDs), strings.Join(messages, "; "))
+}
+
+func (e *ProductsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// NotFound reports whether every failed product was missing from the catalog,
+// as opposed to the product service being unavailable.
+func (e *ProductsError) NotFound() bool {
+	for _, err := range e.Errors {
+		if !errors.Is(err, ErrProductNotFound) {
+			return false
+		}
+	}
+	return true
+}
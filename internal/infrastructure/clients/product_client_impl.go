@@ -2,20 +2,43 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/viniciuscluna/tc-fiap-50/internal/shared/httpclient"
+	"golang.org/x/sync/errgroup"
 )
 
 type productClientImpl struct {
-	httpClient httpclient.HTTPClient
-	baseURL    string
+	httpClient     httpclient.HTTPClient
+	baseURL        string
+	batchPath      string
+	maxConcurrency int
+
+	// batchUnsupported is set once the product service answers that it has no
+	// batch endpoint, so later calls go straight to single fetches.
+	batchUnsupported atomic.Bool
 }
 
-func NewProductClientImpl(httpClient httpclient.HTTPClient, baseURL string) ProductClient {
+// NewProductClientImpl creates a product client. When batchPath is set (e.g.
+// "/v1/product"), GetProducts first tries GET {baseURL}{batchPath}?ids=1,2,3 and
+// falls back to fetching products one by one, with at most maxConcurrency
+// requests in flight.
+func NewProductClientImpl(httpClient httpclient.HTTPClient, baseURL string, batchPath string, maxConcurrency int) ProductClient {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+
 	return &productClientImpl{
-		httpClient: httpClient,
-		baseURL:    baseURL,
+		httpClient:     httpClient,
+		baseURL:        baseURL,
+		batchPath:      batchPath,
+		maxConcurrency: maxConcurrency,
 	}
 }
 
@@ -24,6 +47,9 @@ func (c *productClientImpl) GetProduct(ctx context.Context, productID uint) (*Pr
 	var product ProductDTO
 
 	if err := c.httpClient.Get(ctx, url, &product); err != nil {
+		if httpclient.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to fetch product %d: %w: %w", productID, ErrProductNotFound, err)
+		}
 		return nil, fmt.Errorf("failed to fetch product %d: %w", productID, err)
 	}
 
@@ -35,15 +61,114 @@ func (c *productClientImpl) GetProducts(ctx context.Context, productIDs []uint)
 		return []*ProductDTO{}, nil
 	}
 
-	// Fetch products individually (can be optimized later with batch endpoint)
+	found := make(map[uint]*ProductDTO, len(productIDs))
+	failed := make(map[uint]error)
+
+	if c.batchPath != "" && !c.batchUnsupported.Load() {
+		err := c.getBatch(ctx, productIDs, found, failed)
+		if err == nil {
+			return collectProducts(productIDs, found, failed)
+		}
+		if isBatchUnsupported(err) {
+			c.batchUnsupported.Store(true)
+		}
+		// Any batch failure falls back to single fetches
+	}
+
+	c.getEach(ctx, productIDs, found, failed)
+
+	return collectProducts(productIDs, found, failed)
+}
+
+// getBatch fetches every product with a single request. Products missing from
+// the answer are reported as not found.
+func (c *productClientImpl) getBatch(ctx context.Context, productIDs []uint, found map[uint]*ProductDTO, failed map[uint]error) error {
+	ids := make([]string, len(productIDs))
+	for i, productID := range productIDs {
+		ids[i] = strconv.FormatUint(uint64(productID), 10)
+	}
+
+	url := fmt.Sprintf("%s%s?ids=%s", c.baseURL, c.batchPath, strings.Join(ids, ","))
+	var products []*ProductDTO
+
+	if err := c.httpClient.Get(ctx, url, &products); err != nil {
+		return fmt.Errorf("failed to fetch products in batch: %w", err)
+	}
+
+	for _, product := range products {
+		if product != nil {
+			found[product.ID] = product
+		}
+	}
+	for _, productID := range productIDs {
+		if _, exists := found[productID]; !exists {
+			failed[productID] = fmt.Errorf("failed to fetch product %d: %w", productID, ErrProductNotFound)
+		}
+	}
+
+	return nil
+}
+
+// getEach fetches the products one by one, with at most maxConcurrency requests in flight.
+func (c *productClientImpl) getEach(ctx context.Context, productIDs []uint, found map[uint]*ProductDTO, failed map[uint]error) {
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(c.maxConcurrency)
+
+	for _, productID := range productIDs {
+		g.Go(func() error {
+			// Don't start new requests once the caller has given up
+			var product *ProductDTO
+			err := ctx.Err()
+			if err != nil {
+				err = fmt.Errorf("failed to fetch product %d: %w", productID, err)
+			} else {
+				product, err = c.GetProduct(ctx, productID)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[productID] = err
+			} else {
+				found[productID] = product
+			}
+			return nil
+		})
+	}
+
+	// Failures are collected per product, so there is no error to report
+	_ = g.Wait()
+}
+
+// collectProducts returns the fetched products in the requested order, along
+// with a *ProductsError when some of them failed.
+func collectProducts(productIDs []uint, found map[uint]*ProductDTO, failed map[uint]error) ([]*ProductDTO, error) {
 	products := make([]*ProductDTO, 0, len(productIDs))
 	for _, productID := range productIDs {
-		product, err := c.GetProduct(ctx, productID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch product %d: %w", productID, err)
+		if product, exists := found[productID]; exists {
+			products = append(products, product)
 		}
-		products = append(products, product)
+	}
+
+	if len(failed) > 0 {
+		return products, &ProductsError{Errors: failed}
 	}
 
 	return products, nil
 }
+
+// isBatchUnsupported reports whether the product service answered that it has no batch endpoint.
+func isBatchUnsupported(err error) bool {
+	var statusErr *httpclient.StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	switch statusErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	default:
+		return false
+	}
+}
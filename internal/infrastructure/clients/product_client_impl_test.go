@@ -3,10 +3,14 @@ package clients
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/httpclient"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 	mockHTTPClient "github.com/viniciuscluna/tc-fiap-50/mocks/shared/httpclient"
 )
 
@@ -22,7 +26,7 @@ type ProductClientTestSuite struct {
 func (suite *ProductClientTestSuite) SetupTest() {
 	suite.mockHTTPClient = mockHTTPClient.NewMockHTTPClient(suite.T())
 	suite.baseURL = "http://product-service"
-	suite.client = NewProductClientImpl(suite.mockHTTPClient, suite.baseURL)
+	suite.client = NewProductClientImpl(suite.mockHTTPClient, suite.baseURL, "", 4)
 }
 
 func TestProductClientTestSuite(t *testing.T) {
@@ -40,7 +44,7 @@ func (suite *ProductClientTestSuite) Test_GetProduct_WithValidId_ShouldReturnPro
 		ID:          456,
 		Name:        "Hamburger",
 		Description: "Delicious hamburger",
-		Price:       25.90,
+		Price:       money.MustParse("25.90"),
 		Category:    1,
 		ImageLink:   "http://example.com/hamburger.jpg",
 	}
@@ -120,7 +124,7 @@ func (suite *ProductClientTestSuite) Test_GetProducts_WithSingleId_ShouldReturnS
 		ID:          100,
 		Name:        "Pizza",
 		Description: "Margherita pizza",
-		Price:       35.00,
+		Price:       money.MustParse("35.00"),
 		Category:    1,
 		ImageLink:   "http://example.com/pizza.jpg",
 	}
@@ -153,9 +157,9 @@ func (suite *ProductClientTestSuite) Test_GetProducts_WithMultipleIds_ShouldRetu
 	productIDs := []uint{1, 2, 3}
 	ctx := context.Background()
 
-	product1 := &ProductDTO{ID: 1, Name: "Product 1", Price: 10.00, Category: 1}
-	product2 := &ProductDTO{ID: 2, Name: "Product 2", Price: 20.00, Category: 2}
-	product3 := &ProductDTO{ID: 3, Name: "Product 3", Price: 30.00, Category: 3}
+	product1 := &ProductDTO{ID: 1, Name: "Product 1", Price: money.MustParse("10.00"), Category: 1}
+	product2 := &ProductDTO{ID: 2, Name: "Product 2", Price: money.MustParse("20.00"), Category: 2}
+	product3 := &ProductDTO{ID: 3, Name: "Product 3", Price: money.MustParse("30.00"), Category: 3}
 
 	// AND the HTTP client returns data for each product
 	suite.mockHTTPClient.EXPECT().
@@ -200,13 +204,13 @@ func (suite *ProductClientTestSuite) Test_GetProducts_WithMultipleIds_ShouldRetu
 	assert.Equal(suite.T(), uint(3), result[2].ID)
 }
 
-// Scenario: Get products with one failing should return error
-func (suite *ProductClientTestSuite) Test_GetProducts_WithOneFailingRequest_ShouldReturnError() {
+// Scenario: Get products with one failing should return the others with the failure
+func (suite *ProductClientTestSuite) Test_GetProducts_WithOneFailingRequest_ShouldReturnPartialResults() {
 	// GIVEN multiple product IDs
 	productIDs := []uint{10, 20, 30}
 	ctx := context.Background()
 
-	product1 := &ProductDTO{ID: 10, Name: "Product 10", Price: 15.00}
+	product1 := &ProductDTO{ID: 10, Name: "Product 10", Price: money.MustParse("15.00")}
 
 	// AND the first product succeeds
 	suite.mockHTTPClient.EXPECT().
@@ -222,16 +226,54 @@ func (suite *ProductClientTestSuite) Test_GetProducts_WithOneFailingRequest_Shou
 	// AND the second product fails
 	suite.mockHTTPClient.EXPECT().
 		Get(ctx, "http://product-service/v1/product/20", &ProductDTO{}).
-		Return(errors.New("product not found")).
+		Return(errors.New("network error")).
+		Once()
+
+	// AND the third product succeeds
+	suite.mockHTTPClient.EXPECT().
+		Get(ctx, "http://product-service/v1/product/30", &ProductDTO{}).
+		RunAndReturn(func(ctx context.Context, url string, target interface{}) error {
+			if dto, ok := target.(*ProductDTO); ok {
+				dto.ID = 30
+			}
+			return nil
+		}).
 		Once()
 
 	// WHEN GetProducts is called
 	result, err := suite.client.GetProducts(ctx, productIDs)
 
-	// THEN the operation should return an error
-	assert.Error(suite.T(), err)
-	assert.Nil(suite.T(), result)
-	assert.Contains(suite.T(), err.Error(), "failed to fetch product 20")
+	// THEN the fetched products should be returned
+	assert.Len(suite.T(), result, 2)
+	assert.Equal(suite.T(), uint(10), result[0].ID)
+	assert.Equal(suite.T(), uint(30), result[1].ID)
+	// AND the failed product should be reported
+	var productsErr *ProductsError
+	assert.ErrorAs(suite.T(), err, &productsErr)
+	assert.Len(suite.T(), productsErr.Errors, 1)
+	assert.Contains(suite.T(), productsErr.Errors[20].Error(), "failed to fetch product 20")
+	assert.False(suite.T(), productsErr.NotFound())
+}
+
+// Scenario: Get products with an unknown product should report it as not found
+func (suite *ProductClientTestSuite) Test_GetProducts_WithUnknownProduct_ShouldReportNotFound() {
+	// GIVEN a product unknown to the product service
+	ctx := context.Background()
+	suite.mockHTTPClient.EXPECT().
+		Get(ctx, "http://product-service/v1/product/99", &ProductDTO{}).
+		Return(&httpclient.StatusError{StatusCode: http.StatusNotFound, Body: "not found"}).
+		Once()
+
+	// WHEN GetProducts is called
+	result, err := suite.client.GetProducts(ctx, []uint{99})
+
+	// THEN no product should be returned
+	assert.Empty(suite.T(), result)
+	// AND the product should be reported as not found
+	var productsErr *ProductsError
+	assert.ErrorAs(suite.T(), err, &productsErr)
+	assert.True(suite.T(), productsErr.NotFound())
+	assert.ErrorIs(suite.T(), err, ErrProductNotFound)
 }
 
 // Scenario: Get products should maintain order of requested IDs
@@ -339,19 +381,115 @@ func (suite *ProductClientTestSuite) Test_GetProducts_WithCancelledContext_Shoul
 	cancel()
 
 	productIDs := []uint{1, 2}
-	expectedURL := "http://product-service/v1/product/1"
 
-	// AND the HTTP client returns context cancelled error on first request
+	// WHEN GetProducts is called with cancelled context
+	result, err := suite.client.GetProducts(ctx, productIDs)
+
+	// THEN the operation should return the cancellation for every product
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+	assert.Contains(suite.T(), err.Error(), "failed to fetch product 1")
+	assert.Contains(suite.T(), err.Error(), "failed to fetch product 2")
+	assert.Empty(suite.T(), result)
+	// AND no request should be sent
+	suite.mockHTTPClient.AssertNotCalled(suite.T(), "Get", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Scenario: Get products with a batch endpoint should fetch them in one request
+func (suite *ProductClientTestSuite) Test_GetProducts_WithBatchEndpoint_ShouldFetchInOneRequest() {
+	// GIVEN a client configured with a batch endpoint
+	client := NewProductClientImpl(suite.mockHTTPClient, suite.baseURL, "/v1/product", 4)
+	ctx := context.Background()
+
+	// AND the batch endpoint knows all but one of the products
 	suite.mockHTTPClient.EXPECT().
-		Get(ctx, expectedURL, &ProductDTO{}).
-		Return(context.Canceled).
+		Get(ctx, "http://product-service/v1/product?ids=3,1,2", mock.Anything).
+		RunAndReturn(func(ctx context.Context, url string, target interface{}) error {
+			*target.(*[]*ProductDTO) = []*ProductDTO{{ID: 1, Name: "Product 1"}, {ID: 3, Name: "Product 3"}}
+			return nil
+		}).
 		Once()
 
-	// WHEN GetProducts is called with cancelled context
-	result, err := suite.client.GetProducts(ctx, productIDs)
+	// WHEN GetProducts is called
+	result, err := client.GetProducts(ctx, []uint{3, 1, 2})
+
+	// THEN the known products should be returned in the requested order
+	assert.Len(suite.T(), result, 2)
+	assert.Equal(suite.T(), uint(3), result[0].ID)
+	assert.Equal(suite.T(), uint(1), result[1].ID)
+	// AND the missing product should be reported as not found
+	var productsErr *ProductsError
+	assert.ErrorAs(suite.T(), err, &productsErr)
+	assert.True(suite.T(), productsErr.NotFound())
+	assert.Contains(suite.T(), productsErr.Errors, uint(2))
+}
 
-	// THEN the operation should return an error
-	assert.Error(suite.T(), err)
-	assert.Nil(suite.T(), result)
-	assert.Contains(suite.T(), err.Error(), "failed to fetch product")
+// Scenario: Get products without batch support should fall back to single fetches
+func (suite *ProductClientTestSuite) Test_GetProducts_WithUnsupportedBatchEndpoint_ShouldFallBackAndStopTrying() {
+	// GIVEN a client configured with a batch endpoint the service doesn't have
+	client := NewProductClientImpl(suite.mockHTTPClient, suite.baseURL, "/v1/product", 4)
+	ctx := context.Background()
+
+	suite.mockHTTPClient.EXPECT().
+		Get(ctx, "http://product-service/v1/product?ids=1", mock.Anything).
+		Return(&httpclient.StatusError{StatusCode: http.StatusMethodNotAllowed}).
+		Once()
+
+	// AND the single product endpoint works
+	suite.mockHTTPClient.EXPECT().
+		Get(ctx, "http://product-service/v1/product/1", &ProductDTO{}).
+		RunAndReturn(func(ctx context.Context, url string, target interface{}) error {
+			target.(*ProductDTO).ID = 1
+			return nil
+		}).
+		Twice()
+
+	// WHEN GetProducts is called twice
+	first, firstErr := client.GetProducts(ctx, []uint{1})
+	second, secondErr := client.GetProducts(ctx, []uint{1})
+
+	// THEN both calls should return the product
+	assert.NoError(suite.T(), firstErr)
+	assert.NoError(suite.T(), secondErr)
+	assert.Len(suite.T(), first, 1)
+	assert.Len(suite.T(), second, 1)
+	// AND the batch endpoint should only be tried once
+	suite.mockHTTPClient.AssertNumberOfCalls(suite.T(), "Get", 3)
+}
+
+// Scenario: Get products with a failing batch endpoint should fall back for that call only
+func (suite *ProductClientTestSuite) Test_GetProducts_WithFailingBatchEndpoint_ShouldFallBackAndRetryLater() {
+	// GIVEN a client configured with a batch endpoint that is temporarily failing
+	client := NewProductClientImpl(suite.mockHTTPClient, suite.baseURL, "/v1/product", 4)
+	ctx := context.Background()
+
+	suite.mockHTTPClient.EXPECT().
+		Get(ctx, "http://product-service/v1/product?ids=1", mock.Anything).
+		Return(&httpclient.StatusError{StatusCode: http.StatusServiceUnavailable}).
+		Once()
+	suite.mockHTTPClient.EXPECT().
+		Get(ctx, "http://product-service/v1/product/1", &ProductDTO{}).
+		RunAndReturn(func(ctx context.Context, url string, target interface{}) error {
+			target.(*ProductDTO).ID = 1
+			return nil
+		}).
+		Once()
+
+	// WHEN GetProducts is called
+	result, err := client.GetProducts(ctx, []uint{1})
+
+	// THEN the product should be fetched on its own
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 1)
+
+	// AND the batch endpoint should be tried again on the next call
+	suite.mockHTTPClient.EXPECT().
+		Get(ctx, "http://product-service/v1/product?ids=1", mock.Anything).
+		RunAndReturn(func(ctx context.Context, url string, target interface{}) error {
+			*target.(*[]*ProductDTO) = []*ProductDTO{{ID: 1}}
+			return nil
+		}).
+		Once()
+	result, err = client.GetProducts(ctx, []uint{1})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 1)
 }
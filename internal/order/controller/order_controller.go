@@ -1,13 +1,16 @@
 package controller
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
 )
 
 type OrderController interface {
-	Add(addOrderRequest *dto.AddOrderDto) (string, error)
-	GetOrder(orderId uint) (*dto.GetOrderResponseDto, error)
-	GetOrders() (*dto.GetOrdersResponseDto, error)
-	GetOrderStatus(orderId uint) (*dto.GetOrderStatusResponseDto, error)
-	UpdateOrderStatus(orderId uint, updateOrderStatusRequest *dto.UpdateOrderStatusRequestDto) error
+	Add(ctx context.Context, addOrderRequest *dto.AddOrderDto, idempotencyKey string, requestHash string) (string, error)
+	GetOrder(ctx context.Context, orderId uint) (*dto.GetOrderResponseDto, error)
+	GetOrders(ctx context.Context) (*dto.GetOrdersResponseDto, error)
+	GetOrderStatus(ctx context.Context, orderId uint) (*dto.GetOrderStatusResponseDto, error)
+	UpdateOrderStatus(ctx context.Context, orderId uint, updateOrderStatusRequest *dto.UpdateOrderStatusRequestDto) error
+	CancelOrder(ctx context.Context, orderId uint, cancelOrderRequest *dto.CancelOrderRequestDto) error
 }
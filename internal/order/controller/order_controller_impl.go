@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/presenter"
 	addorder "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/addOrder"
+	cancelorder "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/cancelOrder"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 	getorder "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/getOrder"
 	getorderstatus "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/getOrderStatus"
@@ -22,6 +25,7 @@ type OrderControllerImpl struct {
 	getOrdersUseCase         getorders.GetOrdersUseCase
 	getOrderStatusUseCase    getorderstatus.GetOrderStatusUseCase
 	updateOrderStatusUseCase updateorderstatus.UpdateOrderStatusUseCase
+	cancelOrderUseCase       cancelorder.CancelOrderUseCase
 }
 
 func NewOrderControllerImpl(
@@ -30,7 +34,8 @@ func NewOrderControllerImpl(
 	getOrderUseCase getorder.GetOrderUseCase,
 	getOrdersUseCase getorders.GetOrdersUseCase,
 	getOrderStatusUseCase getorderstatus.GetOrderStatusUseCase,
-	updateOrderStatusUseCase updateorderstatus.UpdateOrderStatusUseCase) *OrderControllerImpl {
+	updateOrderStatusUseCase updateorderstatus.UpdateOrderStatusUseCase,
+	cancelOrderUseCase cancelorder.CancelOrderUseCase) *OrderControllerImpl {
 	return &OrderControllerImpl{
 		presenter:                presenter,
 		addOrderUseCase:          addOrderUseCase,
@@ -38,14 +43,20 @@ func NewOrderControllerImpl(
 		getOrdersUseCase:         getOrdersUseCase,
 		getOrderStatusUseCase:    getOrderStatusUseCase,
 		updateOrderStatusUseCase: updateOrderStatusUseCase,
+		cancelOrderUseCase:       cancelOrderUseCase,
 	}
 }
 
-func (c *OrderControllerImpl) Add(addOrderRequest *dto.AddOrderDto) (string, error) {
-	orderId, err := c.addOrderUseCase.Execute(commands.NewAddOrderCommand(
-		*addOrderRequest.CustomerId,
+func (c *OrderControllerImpl) Add(ctx context.Context, addOrderRequest *dto.AddOrderDto, idempotencyKey string, requestHash string) (string, error) {
+	command := commands.NewAddOrderCommand(
+		addOrderRequest.CustomerId,
+		addOrderRequest.GuestName,
 		addOrderRequest.TotalAmount,
-		addOrderRequest.Products))
+		addOrderRequest.Products)
+	command.IdempotencyKey = idempotencyKey
+	command.RequestHash = requestHash
+
+	orderId, err := c.addOrderUseCase.Execute(ctx, command)
 	if err != nil {
 		return "", err
 	}
@@ -53,26 +64,26 @@ func (c *OrderControllerImpl) Add(addOrderRequest *dto.AddOrderDto) (string, err
 	return orderId, nil
 }
 
-func (c *OrderControllerImpl) GetOrder(orderId uint) (*dto.GetOrderResponseDto, error) {
-	order, err := c.getOrderUseCase.Execute(commands.NewGetOrderCommand(orderId))
+func (c *OrderControllerImpl) GetOrder(ctx context.Context, orderId uint) (*dto.GetOrderResponseDto, error) {
+	order, err := c.getOrderUseCase.Execute(ctx, commands.NewGetOrderCommand(orderId))
 	if err != nil {
 		return nil, err
 	}
 
-	return c.presenter.Present(order), nil
+	return c.presenter.Present(ctx, order), nil
 }
 
-func (c *OrderControllerImpl) GetOrders() (*dto.GetOrdersResponseDto, error) {
-	orders, err := c.getOrdersUseCase.Execute(commands.NewGetOrdersCommand())
+func (c *OrderControllerImpl) GetOrders(ctx context.Context) (*dto.GetOrdersResponseDto, error) {
+	orders, err := c.getOrdersUseCase.Execute(ctx, commands.NewGetOrdersCommand())
 	if err != nil {
 		return nil, err
 	}
 
-	return c.presenter.PresentOrders(orders), nil
+	return c.presenter.PresentOrders(ctx, orders), nil
 }
 
-func (c *OrderControllerImpl) GetOrderStatus(orderId uint) (*dto.GetOrderStatusResponseDto, error) {
-	orderStatus, err := c.getOrderStatusUseCase.Execute(commands.NewGetOrderStatusCommand(orderId))
+func (c *OrderControllerImpl) GetOrderStatus(ctx context.Context, orderId uint) (*dto.GetOrderStatusResponseDto, error) {
+	orderStatus, err := c.getOrderStatusUseCase.Execute(ctx, commands.NewGetOrderStatusCommand(orderId))
 	if err != nil {
 		return nil, err
 	}
@@ -80,11 +91,18 @@ func (c *OrderControllerImpl) GetOrderStatus(orderId uint) (*dto.GetOrderStatusR
 	return c.presenter.PresentStatus(orderStatus), nil
 }
 
-func (c *OrderControllerImpl) UpdateOrderStatus(orderId uint, updateOrderStatusRequest *dto.UpdateOrderStatusRequestDto) error {
-	err := c.updateOrderStatusUseCase.Execute(commands.NewUpdateOrderStatusCommand(orderId, updateOrderStatusRequest.Status))
+func (c *OrderControllerImpl) UpdateOrderStatus(ctx context.Context, orderId uint, updateOrderStatusRequest *dto.UpdateOrderStatusRequestDto) error {
+	err := c.updateOrderStatusUseCase.Execute(ctx, commands.NewUpdateOrderStatusCommand(orderId, updateOrderStatusRequest.Status))
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (c *OrderControllerImpl) CancelOrder(ctx context.Context, orderId uint, cancelOrderRequest *dto.CancelOrderRequestDto) error {
+	return c.cancelOrderUseCase.Execute(ctx, commands.NewCancelOrderCommand(
+		orderId,
+		cancelOrderRequest.ReasonCode,
+		cancelOrderRequest.CancelledBy))
+}
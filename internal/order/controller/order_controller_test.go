@@ -1,6 +1,7 @@
 package controller_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -11,8 +12,11 @@ import (
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/controller"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 	mockPresenter "github.com/viniciuscluna/tc-fiap-50/mocks/order/presenter"
 	mockAddOrder "github.com/viniciuscluna/tc-fiap-50/mocks/order/usecase/addOrder"
+	mockCancelOrder "github.com/viniciuscluna/tc-fiap-50/mocks/order/usecase/cancelOrder"
 	mockGetOrder "github.com/viniciuscluna/tc-fiap-50/mocks/order/usecase/getOrder"
 	mockGetOrderStatus "github.com/viniciuscluna/tc-fiap-50/mocks/order/usecase/getOrderStatus"
 	mockGetOrders "github.com/viniciuscluna/tc-fiap-50/mocks/order/usecase/getOrders"
@@ -27,6 +31,7 @@ type OrderControllerTestSuite struct {
 	mockGetOrdersUseCase         *mockGetOrders.MockGetOrdersUseCase
 	mockGetOrderStatusUseCase    *mockGetOrderStatus.MockGetOrderStatusUseCase
 	mockUpdateOrderStatusUseCase *mockUpdateOrderStatus.MockUpdateOrderStatusUseCase
+	mockCancelOrderUseCase       *mockCancelOrder.MockCancelOrderUseCase
 	controller                   controller.OrderController
 }
 
@@ -37,6 +42,7 @@ func (suite *OrderControllerTestSuite) SetupTest() {
 	suite.mockGetOrdersUseCase = mockGetOrders.NewMockGetOrdersUseCase(suite.T())
 	suite.mockGetOrderStatusUseCase = mockGetOrderStatus.NewMockGetOrderStatusUseCase(suite.T())
 	suite.mockUpdateOrderStatusUseCase = mockUpdateOrderStatus.NewMockUpdateOrderStatusUseCase(suite.T())
+	suite.mockCancelOrderUseCase = mockCancelOrder.NewMockCancelOrderUseCase(suite.T())
 
 	suite.controller = controller.NewOrderControllerImpl(
 		suite.mockPresenter,
@@ -45,6 +51,7 @@ func (suite *OrderControllerTestSuite) SetupTest() {
 		suite.mockGetOrdersUseCase,
 		suite.mockGetOrderStatusUseCase,
 		suite.mockUpdateOrderStatusUseCase,
+		suite.mockCancelOrderUseCase,
 	)
 }
 
@@ -52,6 +59,10 @@ func TestOrderControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(OrderControllerTestSuite))
 }
 
+func uintPtr(v uint) *uint {
+	return &v
+}
+
 // Feature: Order Controller - Add Order
 // Scenario: Create a new order successfully
 
@@ -60,21 +71,21 @@ func (suite *OrderControllerTestSuite) Test_Add_WithValidDto_ShouldCreateOrderSu
 	customerId := uint(1)
 	addOrderDto := &dto.AddOrderDto{
 		CustomerId:  &customerId,
-		TotalAmount: 100.00,
+		TotalAmount: money.MustParse("100.00"),
 		Products: []*dto.AddOrderProductDto{
-			{ProductId: 10, Quantity: 2, Price: 50.00},
+			{ProductId: 10, Quantity: 2, Price: money.MustParse("50.00")},
 		},
 	}
 
 	expectedOrderId := "123"
 
 	suite.mockAddOrderUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return(expectedOrderId, nil).
 		Once()
 
 	// WHEN the order is added
-	orderId, err := suite.controller.Add(addOrderDto)
+	orderId, err := suite.controller.Add(context.Background(), addOrderDto, "", "")
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -88,7 +99,7 @@ func (suite *OrderControllerTestSuite) Test_Add_WithUseCaseError_ShouldReturnErr
 	customerId := uint(1)
 	addOrderDto := &dto.AddOrderDto{
 		CustomerId:  &customerId,
-		TotalAmount: 50.00,
+		TotalAmount: money.MustParse("50.00"),
 		Products:    []*dto.AddOrderProductDto{},
 	}
 
@@ -96,12 +107,12 @@ func (suite *OrderControllerTestSuite) Test_Add_WithUseCaseError_ShouldReturnErr
 	expectedError := errors.New("database error")
 
 	suite.mockAddOrderUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return("", expectedError).
 		Once()
 
 	// WHEN attempting to add the order
-	orderId, err := suite.controller.Add(addOrderDto)
+	orderId, err := suite.controller.Add(context.Background(), addOrderDto, "", "")
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -111,6 +122,53 @@ func (suite *OrderControllerTestSuite) Test_Add_WithUseCaseError_ShouldReturnErr
 	suite.mockAddOrderUseCase.AssertExpectations(suite.T())
 }
 
+func (suite *OrderControllerTestSuite) Test_Add_WithGuestOrder_ShouldPassNilCustomer() {
+	// GIVEN a guest order DTO without customer ID
+	addOrderDto := &dto.AddOrderDto{
+		GuestName: "Maria",
+		Products: []*dto.AddOrderProductDto{
+			{ProductId: 10, Quantity: 1},
+		},
+	}
+
+	suite.mockAddOrderUseCase.EXPECT().
+		Execute(mock.Anything, mock.MatchedBy(func(command *commands.AddOrderCommand) bool {
+			return command.CustomerId == nil && command.GuestName == "Maria"
+		})).
+		Return("124", nil).
+		Once()
+
+	// WHEN the order is added
+	orderId, err := suite.controller.Add(context.Background(), addOrderDto, "", "")
+
+	// THEN the order should be created as a guest order
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "124", orderId)
+	suite.mockAddOrderUseCase.AssertExpectations(suite.T())
+}
+
+func (suite *OrderControllerTestSuite) Test_Add_WithIdempotencyKey_ShouldPassKeyToCommand() {
+	// GIVEN an add order DTO sent with an idempotency key
+	addOrderDto := &dto.AddOrderDto{
+		GuestName: "Maria",
+		Products:  []*dto.AddOrderProductDto{{ProductId: 10, Quantity: 1}},
+	}
+
+	suite.mockAddOrderUseCase.EXPECT().
+		Execute(mock.Anything, mock.MatchedBy(func(command *commands.AddOrderCommand) bool {
+			return command.IdempotencyKey == "kiosk-1" && command.RequestHash == "hash-a"
+		})).
+		Return("124", nil).
+		Once()
+
+	// WHEN the order is added
+	orderId, err := suite.controller.Add(context.Background(), addOrderDto, "kiosk-1", "hash-a")
+
+	// THEN the key and request hash should reach the use case
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "124", orderId)
+}
+
 // Feature: Order Controller - Get Order
 // Scenario: Retrieve and present order data
 
@@ -120,29 +178,29 @@ func (suite *OrderControllerTestSuite) Test_GetOrder_WithValidId_ShouldReturnPre
 
 	orderEntity := &entities.OrderEntity{
 		ID:          123,
-		CustomerId:  1,
-		TotalAmount: 150.00,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("150.00"),
 		CreatedAt:   time.Now(),
 	}
 
 	expectedDto := &dto.GetOrderResponseDto{
 		ID:          123,
-		CustomerId:  1,
-		TotalAmount: 150.00,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("150.00"),
 	}
 
 	suite.mockGetOrderUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return(orderEntity, nil).
 		Once()
 
 	suite.mockPresenter.EXPECT().
-		Present(orderEntity).
+		Present(mock.Anything, orderEntity).
 		Return(expectedDto).
 		Once()
 
 	// WHEN the order is retrieved
-	result, err := suite.controller.GetOrder(orderId)
+	result, err := suite.controller.GetOrder(context.Background(), orderId)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -161,12 +219,12 @@ func (suite *OrderControllerTestSuite) Test_GetOrder_WithNonExistentId_ShouldRet
 	expectedError := errors.New("order not found")
 
 	suite.mockGetOrderUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return(nil, expectedError).
 		Once()
 
 	// WHEN attempting to retrieve the order
-	result, err := suite.controller.GetOrder(orderId)
+	result, err := suite.controller.GetOrder(context.Background(), orderId)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -183,29 +241,29 @@ func (suite *OrderControllerTestSuite) Test_GetOrder_WithNonExistentId_ShouldRet
 func (suite *OrderControllerTestSuite) Test_GetOrders_ShouldReturnPresentedOrdersList() {
 	// GIVEN multiple active orders
 	orders := []*entities.OrderEntity{
-		{ID: 1, CustomerId: 1, TotalAmount: 50.00},
-		{ID: 2, CustomerId: 2, TotalAmount: 75.00},
+		{ID: 1, CustomerId: uintPtr(1), TotalAmount: money.MustParse("50.00")},
+		{ID: 2, CustomerId: uintPtr(2), TotalAmount: money.MustParse("75.00")},
 	}
 
 	expectedDto := &dto.GetOrdersResponseDto{
 		Orders: []*dto.GetOrderResponseDto{
-			{ID: 1, CustomerId: 1, TotalAmount: 50.00},
-			{ID: 2, CustomerId: 2, TotalAmount: 75.00},
+			{ID: 1, CustomerId: uintPtr(1), TotalAmount: money.MustParse("50.00")},
+			{ID: 2, CustomerId: uintPtr(2), TotalAmount: money.MustParse("75.00")},
 		},
 	}
 
 	suite.mockGetOrdersUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return(orders, nil).
 		Once()
 
 	suite.mockPresenter.EXPECT().
-		PresentOrders(orders).
+		PresentOrders(mock.Anything, orders).
 		Return(expectedDto).
 		Once()
 
 	// WHEN all orders are retrieved
-	result, err := suite.controller.GetOrders()
+	result, err := suite.controller.GetOrders(context.Background())
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -221,12 +279,12 @@ func (suite *OrderControllerTestSuite) Test_GetOrders_WithUseCaseError_ShouldRet
 	expectedError := errors.New("database connection error")
 
 	suite.mockGetOrdersUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return(nil, expectedError).
 		Once()
 
 	// WHEN attempting to retrieve orders
-	result, err := suite.controller.GetOrders()
+	result, err := suite.controller.GetOrders(context.Background())
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -258,7 +316,7 @@ func (suite *OrderControllerTestSuite) Test_GetOrderStatus_WithValidId_ShouldRet
 	}
 
 	suite.mockGetOrderStatusUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return(statusEntity, nil).
 		Once()
 
@@ -268,7 +326,7 @@ func (suite *OrderControllerTestSuite) Test_GetOrderStatus_WithValidId_ShouldRet
 		Once()
 
 	// WHEN the order status is retrieved
-	result, err := suite.controller.GetOrderStatus(orderId)
+	result, err := suite.controller.GetOrderStatus(context.Background(), orderId)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -285,12 +343,12 @@ func (suite *OrderControllerTestSuite) Test_GetOrderStatus_WithError_ShouldRetur
 	expectedError := errors.New("status not found")
 
 	suite.mockGetOrderStatusUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return(nil, expectedError).
 		Once()
 
 	// WHEN attempting to retrieve the status
-	result, err := suite.controller.GetOrderStatus(orderId)
+	result, err := suite.controller.GetOrderStatus(context.Background(), orderId)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -310,12 +368,12 @@ func (suite *OrderControllerTestSuite) Test_UpdateOrderStatus_WithValidRequest_S
 	}
 
 	suite.mockUpdateOrderStatusUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return(nil).
 		Once()
 
 	// WHEN the order status is updated
-	err := suite.controller.UpdateOrderStatus(orderId, updateRequest)
+	err := suite.controller.UpdateOrderStatus(context.Background(), orderId, updateRequest)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -333,15 +391,59 @@ func (suite *OrderControllerTestSuite) Test_UpdateOrderStatus_WithUseCaseError_S
 	expectedError := errors.New("update failed")
 
 	suite.mockUpdateOrderStatusUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.Anything, mock.Anything).
 		Return(expectedError).
 		Once()
 
 	// WHEN attempting to update the status
-	err := suite.controller.UpdateOrderStatus(orderId, updateRequest)
+	err := suite.controller.UpdateOrderStatus(context.Background(), orderId, updateRequest)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), expectedError, err)
 	suite.mockUpdateOrderStatusUseCase.AssertExpectations(suite.T())
 }
+
+// Feature: Order Controller - Cancel Order
+// Scenario: Cancel an order with a reason and author
+
+func (suite *OrderControllerTestSuite) Test_CancelOrder_WithValidRequest_ShouldExecuteUseCase() {
+	// GIVEN a valid cancellation request
+	orderId := uint(789)
+	cancelRequest := &dto.CancelOrderRequestDto{
+		ReasonCode:  "CUSTOMER_REQUEST",
+		CancelledBy: "kiosk-01",
+	}
+
+	suite.mockCancelOrderUseCase.EXPECT().
+		Execute(mock.Anything, mock.MatchedBy(func(command *commands.CancelOrderCommand) bool {
+			return command.OrderId == orderId &&
+				command.ReasonCode == "CUSTOMER_REQUEST" &&
+				command.CancelledBy == "kiosk-01"
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN the order is cancelled
+	err := suite.controller.CancelOrder(context.Background(), orderId, cancelRequest)
+
+	// THEN the operation should complete without errors
+	assert.NoError(suite.T(), err)
+	suite.mockCancelOrderUseCase.AssertExpectations(suite.T())
+}
+
+func (suite *OrderControllerTestSuite) Test_CancelOrder_WithUseCaseError_ShouldReturnError() {
+	// GIVEN the use case refuses the cancellation
+	expectedError := errors.New("cancel failed")
+
+	suite.mockCancelOrderUseCase.EXPECT().
+		Execute(mock.Anything, mock.Anything).
+		Return(expectedError).
+		Once()
+
+	// WHEN attempting to cancel the order
+	err := suite.controller.CancelOrder(context.Background(), 1, &dto.CancelOrderRequestDto{ReasonCode: "OTHER", CancelledBy: "staff"})
+
+	// THEN the use case error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+}
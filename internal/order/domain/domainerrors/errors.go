@@ -0,0 +1,192 @@
+package domainerrors
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+)
+
+// Error categories. Every domain error matches exactly one of them through
+// errors.Is, so adapters can map errors without knowing each concrete type.
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrInvalidTransition   = errors.New("invalid transition")
+	ErrConflict            = errors.New("conflict")
+	ErrValidationFailed    = errors.New("validation failed")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+)
+
+var (
+	ErrOrderNotFound = &NotFoundError{Resource: "order"}
+
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+	ErrIdempotencyKeyConflict = errors.New("idempotency key already in use")
+)
+
+// NotFoundError is returned when a requested resource does not exist.
+type NotFoundError struct {
+	Resource string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.Resource)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// InvalidStatusTransitionError is returned when an order cannot move from its
+// current status to the requested one.
+type InvalidStatusTransitionError struct {
+	From uint
+	To   uint
+}
+
+func (e *InvalidStatusTransitionError) Error() string {
+	return fmt.Sprintf("invalid order status transition from %d to %d", e.From, e.To)
+}
+
+func (e *InvalidStatusTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// StatusChangedError is returned when the status of an order changed after it
+// was read, by a concurrent request, so the requested change was not made.
+type StatusChangedError struct {
+	From uint
+	To   uint
+}
+
+func (e *StatusChangedError) Error() string {
+	return fmt.Sprintf("order status changed concurrently, it is no longer %d and cannot move to %d", e.From, e.To)
+}
+
+func (e *StatusChangedError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// UnknownStatusError is returned when a status value is not part of the order lifecycle.
+type UnknownStatusError struct {
+	Status uint
+}
+
+func (e *UnknownStatusError) Error() string {
+	return fmt.Sprintf("unknown order status %d", e.Status)
+}
+
+func (e *UnknownStatusError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// InvalidCancellationRequestError is returned when a cancellation is missing
+// its author or carries an unknown reason code.
+type InvalidCancellationRequestError struct {
+	Field  string
+	Reason string
+}
+
+func (e *InvalidCancellationRequestError) Error() string {
+	return fmt.Sprintf("invalid cancellation request: %s %s", e.Field, e.Reason)
+}
+
+func (e *InvalidCancellationRequestError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// IdempotencyKeyReusedError is returned when an idempotency key is replayed
+// with a request body different from the one it was first used with.
+type IdempotencyKeyReusedError struct {
+	Key string
+}
+
+func (e *IdempotencyKeyReusedError) Error() string {
+	return fmt.Sprintf("idempotency key %q was already used with a different request", e.Key)
+}
+
+func (e *IdempotencyKeyReusedError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// CustomerNotFoundError is returned when the order customer is unknown to the customer service.
+type CustomerNotFoundError struct {
+	CustomerId uint
+}
+
+func (e *CustomerNotFoundError) Error() string {
+	return fmt.Sprintf("customer %d not found", e.CustomerId)
+}
+
+func (e *CustomerNotFoundError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// ProductNotFoundError is returned when an ordered product does not exist in the catalog.
+type ProductNotFoundError struct {
+	ProductId uint
+}
+
+func (e *ProductNotFoundError) Error() string {
+	return fmt.Sprintf("product %d not found", e.ProductId)
+}
+
+func (e *ProductNotFoundError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// PriceMismatchError is returned when a client supplied price disagrees with the catalog.
+// ProductId is zero when the mismatch is on the order total.
+type PriceMismatchError struct {
+	ProductId uint
+	Expected  money.Money
+	Actual    money.Money
+}
+
+func (e *PriceMismatchError) Error() string {
+	if e.ProductId == 0 {
+		return fmt.Sprintf("total amount mismatch: expected %s, got %s", e.Expected, e.Actual)
+	}
+	return fmt.Sprintf("price mismatch for product %d: expected %s, got %s", e.ProductId, e.Expected, e.Actual)
+}
+
+func (e *PriceMismatchError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// OrderTotalTooLargeError is returned when the total of an order does not fit
+// in an amount of money.
+type OrderTotalTooLargeError struct {
+	Err error
+}
+
+func (e *OrderTotalTooLargeError) Error() string {
+	return fmt.Sprintf("order total too large: %v", e.Err)
+}
+
+func (e *OrderTotalTooLargeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *OrderTotalTooLargeError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// UpstreamUnavailableError is returned when a downstream service needed to
+// complete the operation could not be reached.
+type UpstreamUnavailableError struct {
+	Service string
+	Err     error
+}
+
+func (e *UpstreamUnavailableError) Error() string {
+	return fmt.Sprintf("%s service unavailable: %v", e.Service, e.Err)
+}
+
+func (e *UpstreamUnavailableError) Is(target error) bool {
+	return target == ErrUpstreamUnavailable
+}
+
+func (e *UpstreamUnavailableError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,60 @@
+package domainerrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+)
+
+// Feature: Domain error categories
+// Scenario: Every domain error matches exactly one category
+
+func Test_DomainErrors_ShouldMatchExactlyOneCategory(t *testing.T) {
+	categories := []error{
+		domainerrors.ErrNotFound,
+		domainerrors.ErrInvalidTransition,
+		domainerrors.ErrConflict,
+		domainerrors.ErrValidationFailed,
+		domainerrors.ErrUpstreamUnavailable,
+	}
+
+	testCases := []struct {
+		name     string
+		err      error
+		category error
+	}{
+		{"order not found", domainerrors.ErrOrderNotFound, domainerrors.ErrNotFound},
+		{"wrapped order not found", fmt.Errorf("%w: record not found", domainerrors.ErrOrderNotFound), domainerrors.ErrNotFound},
+		{"invalid status transition", &domainerrors.InvalidStatusTransitionError{From: 4, To: 1}, domainerrors.ErrInvalidTransition},
+		{"status changed concurrently", &domainerrors.StatusChangedError{From: 1, To: 2}, domainerrors.ErrInvalidTransition},
+		{"idempotency key reused", &domainerrors.IdempotencyKeyReusedError{Key: "k"}, domainerrors.ErrConflict},
+		{"unknown status", &domainerrors.UnknownStatusError{Status: 99}, domainerrors.ErrValidationFailed},
+		{"invalid cancellation", &domainerrors.InvalidCancellationRequestError{Field: "cancelledBy", Reason: "is required"}, domainerrors.ErrValidationFailed},
+		{"customer not found", &domainerrors.CustomerNotFoundError{CustomerId: 1}, domainerrors.ErrValidationFailed},
+		{"product not found", &domainerrors.ProductNotFoundError{ProductId: 1}, domainerrors.ErrValidationFailed},
+		{"price mismatch", &domainerrors.PriceMismatchError{ProductId: 1}, domainerrors.ErrValidationFailed},
+		{"order total too large", &domainerrors.OrderTotalTooLargeError{Err: money.ErrOverflow}, domainerrors.ErrValidationFailed},
+		{"upstream unavailable", &domainerrors.UpstreamUnavailableError{Service: "product", Err: errors.New("timeout")}, domainerrors.ErrUpstreamUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, category := range categories {
+				assert.Equal(t, category == tc.category, errors.Is(tc.err, category), "category %q", category)
+			}
+		})
+	}
+}
+
+func Test_NotFoundError_ShouldOnlyMatchItsOwnSentinel(t *testing.T) {
+	// GIVEN a not found error for another resource
+	err := &domainerrors.NotFoundError{Resource: "customer"}
+
+	// THEN it should be a not found error but not a missing order
+	assert.ErrorIs(t, err, domainerrors.ErrNotFound)
+	assert.NotErrorIs(t, err, domainerrors.ErrOrderNotFound)
+}
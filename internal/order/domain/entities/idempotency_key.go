@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+)
+
+// IdempotencyKeyEntity remembers the order created for a client supplied
+// Idempotency-Key, so retried requests replay the original response instead
+// of creating duplicates. RequestHash is the SHA-256 of the request body.
+type IdempotencyKeyEntity struct {
+	Key         string    `gorm:"primaryKey;size:255"`
+	RequestHash string    `gorm:"size:64;not null"`
+	OrderId     uint      `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"default:current_timestamp"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+func (IdempotencyKeyEntity) TableName() string {
+	return "idempotency_key"
+}
@@ -2,15 +2,29 @@ package entities
 
 import (
 	"time"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 )
 
+// OrderEntity is a customer order. Guest orders have no CustomerId and may carry
+// a GuestName for the pickup panel. CustomerReconciliationPending flags orders
+// accepted while the customer service was unavailable, so the customer can be
+// verified later.
+//
+// CurrentStatus and StatusUpdatedAt copy the latest entry of the status
+// history; they are kept up to date by OrderStatusEntity.AfterCreate so the
+// active orders can be found without reading the history.
 type OrderEntity struct {
-	ID          uint                  `gorm:"primaryKey"`
-	CreatedAt   time.Time             `gorm:"default:current_timestamp"`
-	TotalAmount float32               `gorm:"default:0"`
-	CustomerId  uint                  `gorm:"index"`
-	Products    []*OrderProductEntity `gorm:"foreignKey:OrderId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Status      []*OrderStatusEntity  `gorm:"foreignKey:OrderId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ID                            uint        `gorm:"primaryKey"`
+	CreatedAt                     time.Time   `gorm:"default:current_timestamp;index:idx_order_current_status,priority:2"`
+	TotalAmount                   money.Money `gorm:"default:0"`
+	CustomerId                    *uint       `gorm:"index"`
+	GuestName                     string      `gorm:"size:100"`
+	CustomerReconciliationPending bool        `gorm:"default:false;index"`
+	CurrentStatus                 uint        `gorm:"not null;default:0;index:idx_order_current_status,priority:1"`
+	StatusUpdatedAt               *time.Time
+	Products                      []*OrderProductEntity `gorm:"foreignKey:OrderId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Status                        []*OrderStatusEntity  `gorm:"foreignKey:OrderId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 }
 
 func (OrderEntity) TableName() string {
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"fmt"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+)
+
+const (
+	CancellationReasonCustomerRequest = "CUSTOMER_REQUEST"
+	CancellationReasonPaymentFailed   = "PAYMENT_FAILED"
+	CancellationReasonOutOfStock      = "OUT_OF_STOCK"
+	CancellationReasonOther           = "OTHER"
+)
+
+var knownCancellationReasons = map[string]bool{
+	CancellationReasonCustomerRequest: true,
+	CancellationReasonPaymentFailed:   true,
+	CancellationReasonOutOfStock:      true,
+	CancellationReasonOther:           true,
+}
+
+// Orders can be cancelled until they are ready for pickup
+var cancellableStatuses = map[uint]bool{
+	OrderStatusRecebido:     true,
+	OrderStatusEmPreparacao: true,
+}
+
+func IsKnownCancellationReason(reasonCode string) bool {
+	return knownCancellationReasons[reasonCode]
+}
+
+// ValidateCancellation checks whether an order in the given status may be
+// cancelled with the given reason code and author.
+func ValidateCancellation(from uint, reasonCode string, cancelledBy string) error {
+	if reasonCode == "" {
+		return &domainerrors.InvalidCancellationRequestError{Field: "reasonCode", Reason: "is required"}
+	}
+
+	if !IsKnownCancellationReason(reasonCode) {
+		return &domainerrors.InvalidCancellationRequestError{Field: "reasonCode", Reason: fmt.Sprintf("%q is not a known reason code", reasonCode)}
+	}
+
+	if cancelledBy == "" {
+		return &domainerrors.InvalidCancellationRequestError{Field: "cancelledBy", Reason: "is required"}
+	}
+
+	if !cancellableStatuses[from] {
+		return &domainerrors.InvalidStatusTransitionError{From: from, To: OrderStatusCancelado}
+	}
+
+	return nil
+}
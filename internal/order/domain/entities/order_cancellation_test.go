@@ -0,0 +1,66 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+)
+
+// Feature: Order Cancellation
+// Scenario: Orders can be cancelled until they are ready for pickup
+func Test_ValidateCancellation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		from    uint
+		allowed bool
+	}{
+		{"Recebido", entities.OrderStatusRecebido, true},
+		{"Em preparação", entities.OrderStatusEmPreparacao, true},
+		{"Pronto", entities.OrderStatusPronto, false},
+		{"Finalizado", entities.OrderStatusFinalizado, false},
+		{"Cancelado", entities.OrderStatusCancelado, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// WHEN the cancellation is validated
+			err := entities.ValidateCancellation(tc.from, entities.CancellationReasonCustomerRequest, "kiosk-01")
+
+			// THEN it should be accepted or rejected as an invalid transition
+			if tc.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			var transitionErr *domainerrors.InvalidStatusTransitionError
+			assert.ErrorAs(t, err, &transitionErr)
+		})
+	}
+}
+
+// Scenario: A known reason code and an author are required
+func Test_ValidateCancellation_WithInvalidRequest_ShouldReturnInvalidCancellationRequest(t *testing.T) {
+	testCases := []struct {
+		name        string
+		reasonCode  string
+		cancelledBy string
+		field       string
+	}{
+		{"missing reason", "", "kiosk-01", "reasonCode"},
+		{"unknown reason", "CHANGED_MIND", "kiosk-01", "reasonCode"},
+		{"missing author", entities.CancellationReasonOther, "", "cancelledBy"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// WHEN the cancellation is validated
+			err := entities.ValidateCancellation(entities.OrderStatusRecebido, tc.reasonCode, tc.cancelledBy)
+
+			// THEN the offending field should be reported
+			var requestErr *domainerrors.InvalidCancellationRequestError
+			assert.ErrorAs(t, err, &requestErr)
+			assert.Equal(t, tc.field, requestErr.Field)
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package entities
 
+import "github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+
+// OrderProductEntity is an order line. Price is the catalog unit price
+// snapshotted at creation time, so later catalog changes don't rewrite history.
 type OrderProductEntity struct {
 	ID        uint        `gorm:"primaryKey"`
 	OrderId   uint        `gorm:"index"`
 	ProductId uint        `gorm:"index"`
-	Price     float32     `gorm:"not null"`
+	Price     money.Money `gorm:"not null"`
 	Quantity  uint        `gorm:"not null"`
 	Order     OrderEntity `gorm:"foreignKey:OrderId;references:ID"`
 }
@@ -2,12 +2,18 @@ package entities
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// OrderStatusEntity is an entry of the order status history. ReasonCode and
+// ChangedBy record why and by whom the order was cancelled.
 type OrderStatusEntity struct {
 	ID            uint        `gorm:"primaryKey"`
 	CreatedAt     time.Time   `gorm:"default:current_timestamp"`
 	CurrentStatus uint        `gorm:"not null"`
+	ReasonCode    string      `gorm:"size:50"`
+	ChangedBy     string      `gorm:"size:100"`
 	OrderId       uint        `gorm:"index"`
 	Order         OrderEntity `gorm:"foreignKey:OrderId;references:ID"`
 }
@@ -15,3 +21,14 @@ type OrderStatusEntity struct {
 func (OrderStatusEntity) TableName() string {
 	return "order_status"
 }
+
+// AfterCreate makes the new entry the current status of its order, in the
+// transaction of the insert.
+func (s *OrderStatusEntity) AfterCreate(tx *gorm.DB) error {
+	return tx.Model(&OrderEntity{}).
+		Where("id = ?", s.OrderId).
+		UpdateColumns(map[string]any{
+			"current_status":    s.CurrentStatus,
+			"status_updated_at": s.CreatedAt,
+		}).Error
+}
@@ -0,0 +1,49 @@
+package entities
+
+import "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+
+const (
+	OrderStatusRecebido     uint = 1
+	OrderStatusEmPreparacao uint = 2
+	OrderStatusPronto       uint = 3
+	OrderStatusFinalizado   uint = 4
+	OrderStatusCancelado    uint = 5
+)
+
+// Allowed transitions of the order lifecycle:
+// Recebido -> Em preparação -> Pronto -> Finalizado
+// Cancelado is terminal and only reachable through ValidateCancellation, so a
+// cancellation always records its reason and author.
+var allowedStatusTransitions = map[uint][]uint{
+	OrderStatusRecebido:     {OrderStatusEmPreparacao},
+	OrderStatusEmPreparacao: {OrderStatusPronto},
+	OrderStatusPronto:       {OrderStatusFinalizado},
+	OrderStatusFinalizado:   {},
+	OrderStatusCancelado:    {},
+}
+
+// ActiveOrderStatuses returns the statuses of an order still in progress,
+// neither finished nor cancelled.
+func ActiveOrderStatuses() []uint {
+	return []uint{OrderStatusRecebido, OrderStatusEmPreparacao, OrderStatusPronto}
+}
+
+func IsKnownOrderStatus(status uint) bool {
+	_, exists := allowedStatusTransitions[status]
+	return exists
+}
+
+// ValidateStatusTransition checks whether an order may move from one status to another.
+func ValidateStatusTransition(from uint, to uint) error {
+	if !IsKnownOrderStatus(to) {
+		return &domainerrors.UnknownStatusError{Status: to}
+	}
+
+	for _, allowed := range allowedStatusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return &domainerrors.InvalidStatusTransitionError{From: from, To: to}
+}
@@ -0,0 +1,71 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+)
+
+// Feature: Order Status State Machine
+// Scenario: Only forward, single-step transitions are allowed
+func Test_ValidateStatusTransition(t *testing.T) {
+	testCases := []struct {
+		name    string
+		from    uint
+		to      uint
+		allowed bool
+	}{
+		{"Recebido to Em preparação", entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao, true},
+		{"Em preparação to Pronto", entities.OrderStatusEmPreparacao, entities.OrderStatusPronto, true},
+		{"Pronto to Finalizado", entities.OrderStatusPronto, entities.OrderStatusFinalizado, true},
+		{"Recebido to Pronto", entities.OrderStatusRecebido, entities.OrderStatusPronto, false},
+		{"Finalizado to Recebido", entities.OrderStatusFinalizado, entities.OrderStatusRecebido, false},
+		{"Pronto to Em preparação", entities.OrderStatusPronto, entities.OrderStatusEmPreparacao, false},
+		{"Recebido to Recebido", entities.OrderStatusRecebido, entities.OrderStatusRecebido, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// WHEN the transition is validated
+			err := entities.ValidateStatusTransition(tc.from, tc.to)
+
+			// THEN it should be accepted or rejected as an invalid transition
+			if tc.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			var transitionErr *domainerrors.InvalidStatusTransitionError
+			assert.ErrorAs(t, err, &transitionErr)
+		})
+	}
+}
+
+// Scenario: Unknown target statuses are rejected
+func Test_ValidateStatusTransition_WithUnknownStatus_ShouldReturnUnknownStatusError(t *testing.T) {
+	// WHEN a transition to a status outside the lifecycle is validated
+	err := entities.ValidateStatusTransition(entities.OrderStatusRecebido, 9)
+
+	// THEN an unknown status error should be returned
+	var unknownErr *domainerrors.UnknownStatusError
+	assert.ErrorAs(t, err, &unknownErr)
+	assert.Equal(t, uint(9), unknownErr.Status)
+}
+
+// Scenario: Cancelado is terminal and cannot be reached through a status update
+func Test_ValidateStatusTransition_ToCancelado_ShouldReturnInvalidTransition(t *testing.T) {
+	for _, from := range []uint{entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao} {
+		// WHEN a plain status update to "Cancelado" is validated
+		err := entities.ValidateStatusTransition(from, entities.OrderStatusCancelado)
+
+		// THEN it should be rejected, cancellation goes through ValidateCancellation
+		var transitionErr *domainerrors.InvalidStatusTransitionError
+		assert.ErrorAs(t, err, &transitionErr)
+	}
+
+	// AND no transition should leave "Cancelado"
+	err := entities.ValidateStatusTransition(entities.OrderStatusCancelado, entities.OrderStatusRecebido)
+	var transitionErr *domainerrors.InvalidStatusTransitionError
+	assert.ErrorAs(t, err, &transitionErr)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"time"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+)
+
+type IdempotencyKeyRepository interface {
+	// GetIdempotencyKey returns the key if it has not expired at now,
+	// otherwise domainerrors.ErrIdempotencyKeyNotFound.
+	GetIdempotencyKey(ctx context.Context, key string, now time.Time) (*entities.IdempotencyKeyEntity, error)
+	// AddIdempotencyKey stores the key, replacing one that expired before its
+	// CreatedAt. It returns domainerrors.ErrIdempotencyKeyConflict when the key
+	// is still in use.
+	AddIdempotencyKey(ctx context.Context, idempotencyKey *entities.IdempotencyKeyEntity) error
+}
@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 )
 
 type OrderProductRepository interface {
-	AddOrderProduct(orderProduct *entities.OrderProductEntity) error
+	AddOrderProduct(ctx context.Context, orderProduct *entities.OrderProductEntity) error
 }
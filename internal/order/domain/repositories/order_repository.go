@@ -1,9 +1,13 @@
 package repositories
 
-import "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+import (
+	"context"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+)
 
 type OrderRepository interface {
-	AddOrder(order *entities.OrderEntity) (*entities.OrderEntity, error)
-	GetOrder(orderId uint) (*entities.OrderEntity, error)
-	GetOrders() ([]*entities.OrderEntity, error)
+	AddOrder(ctx context.Context, order *entities.OrderEntity) (*entities.OrderEntity, error)
+	GetOrder(ctx context.Context, orderId uint) (*entities.OrderEntity, error)
+	GetOrders(ctx context.Context) ([]*entities.OrderEntity, error)
 }
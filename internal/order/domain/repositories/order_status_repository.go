@@ -1,10 +1,19 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 )
 
 type OrderStatusRepository interface {
-	AddOrderStatus(orderStatus *entities.OrderStatusEntity) error
-	GetOrderStatus(orderId uint) (*entities.OrderStatusEntity, error)
+	AddOrderStatus(ctx context.Context, orderStatus *entities.OrderStatusEntity) error
+	// ChangeOrderStatus adds orderStatus as the new status of its order only if
+	// the latest entry of its status history is still from, atomically.
+	// Otherwise it returns a domainerrors.StatusChangedError and adds nothing.
+	ChangeOrderStatus(ctx context.Context, orderStatus *entities.OrderStatusEntity, from uint) error
+	GetOrderStatus(ctx context.Context, orderId uint) (*entities.OrderStatusEntity, error)
+	// CountOrdersByStatus returns how many orders are currently in each active
+	// status. Finished and cancelled orders are not counted.
+	CountOrdersByStatus(ctx context.Context) (map[uint]int64, error)
 }
@@ -0,0 +1,18 @@
+package repositories
+
+import "context"
+
+// Repositories groups the repositories bound to a single unit of work.
+type Repositories struct {
+	Orders          OrderRepository
+	OrderProducts   OrderProductRepository
+	OrderStatus     OrderStatusRepository
+	IdempotencyKeys IdempotencyKeyRepository
+}
+
+// UnitOfWork runs multi-repository writes atomically. When fn returns an error
+// (or panics), or ctx is cancelled, every write made through the given
+// repositories is rolled back.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos *Repositories) error) error
+}
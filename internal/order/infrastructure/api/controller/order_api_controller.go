@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -10,6 +12,11 @@ import (
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
 )
 
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
 type orderApiController struct {
 	controller orderController.OrderController
 }
@@ -27,6 +34,7 @@ func (c *orderApiController) RegisterRoutes(r chi.Router) {
 	r.Get(prefix, c.GetOrders)
 	r.Get(prefix+"/{orderId}/status", c.GetOrderStatus)
 	r.Put(prefix+"/{orderId}/status", c.UpdateOrderStatus)
+	r.Post(prefix+"/{orderId}/cancel", c.CancelOrder)
 }
 
 // @Summary     Add order
@@ -34,20 +42,45 @@ func (c *orderApiController) RegisterRoutes(r chi.Router) {
 // @Tags        Order
 // @Accept      json
 // @Produce     json
+// @Param       Idempotency-Key header string false "Retries with the same key and body return the original order"
 // @Param       body body dto.AddOrderDto true "Body"
 // @Success     201  {object} dto.GetOrderResponseDto
+// @Failure     400 {object} dto.ProblemDetailsDto "Malformed JSON or unknown fields"
+// @Failure     409 {object} dto.ProblemDetailsDto "Idempotency key reused with a different body"
+// @Failure     413 {object} dto.ProblemDetailsDto "Request body too large"
+// @Failure     422 {object} dto.ProblemDetailsDto "Invalid fields, unknown customer, unknown product or price mismatch"
+// @Failure     503 {object} dto.ProblemDetailsDto "Customer or product service unavailable"
 // @Router      /v1/order [post]
 func (c *orderApiController) Add(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		writeInvalidRequest(w, r, "Idempotency-Key must be at most 255 characters")
+		return
+	}
+
+	body, err := readBody(w, r)
+	if err != nil {
+		writeBodyError(w, r, err)
+		return
+	}
+
 	var orderRequest dto.AddOrderDto
 
-	if err := json.NewDecoder(r.Body).Decode(&orderRequest); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+	if !decodeRequest(w, r, body, &orderRequest) {
+		return
 	}
 
-	orderId, err := c.controller.Add(&orderRequest)
+	if fieldErrors := validateAddOrder(&orderRequest); len(fieldErrors) > 0 {
+		writeValidationProblem(w, r, fieldErrors)
+		return
+	}
+
+	requestHash := sha256.Sum256(body)
+	orderId, err := c.controller.Add(r.Context(), &orderRequest, idempotencyKey, hex.EncodeToString(requestHash[:]))
 
 	if err != nil {
-		http.Error(w, "Error processing request", http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
@@ -61,18 +94,21 @@ func (c *orderApiController) Add(w http.ResponseWriter, r *http.Request) {
 // @Produce     json
 // @Param       orderId path uint true "Order ID"
 // @Success     200  {object} dto.GetOrderResponseDto
+// @Failure     400 {object} dto.ProblemDetailsDto "Invalid request"
+// @Failure     404 {object} dto.ProblemDetailsDto "Order not found"
 // @Router      /v1/order/{orderId} [get]
 func (c *orderApiController) GetOrder(w http.ResponseWriter, r *http.Request) {
 	orderId, err := getOrderIDFromPath(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeInvalidRequest(w, r, "Invalid order ID")
 		return
 	}
 
-	order, err := c.controller.GetOrder(orderId)
+	order, err := c.controller.GetOrder(r.Context(), orderId)
 
 	if err != nil {
-		http.Error(w, "Error processing request", http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -87,10 +123,11 @@ func (c *orderApiController) GetOrder(w http.ResponseWriter, r *http.Request) {
 // @Success     200  {object} dto.GetOrdersResponseDto
 // @Router      /v1/order [get]
 func (c *orderApiController) GetOrders(w http.ResponseWriter, r *http.Request) {
-	orders, err := c.controller.GetOrders()
+	orders, err := c.controller.GetOrders(r.Context())
 
 	if err != nil {
-		http.Error(w, "Error processing request", http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -104,18 +141,21 @@ func (c *orderApiController) GetOrders(w http.ResponseWriter, r *http.Request) {
 // @Produce     json
 // @Param       orderId path uint true "Order ID"
 // @Success     200  {object} dto.GetOrderStatusResponseDto
+// @Failure     400 {object} dto.ProblemDetailsDto "Invalid request"
+// @Failure     404 {object} dto.ProblemDetailsDto "Order not found"
 // @Router      /v1/order/{orderId}/status [get]
 func (c *orderApiController) GetOrderStatus(w http.ResponseWriter, r *http.Request) {
 	orderId, err := getOrderIDFromPath(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeInvalidRequest(w, r, "Invalid order ID")
 		return
 	}
 
-	status, err := c.controller.GetOrderStatus(orderId)
+	status, err := c.controller.GetOrderStatus(r.Context(), orderId)
 
 	if err != nil {
-		http.Error(w, "Error processing request", http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -130,24 +170,71 @@ func (c *orderApiController) GetOrderStatus(w http.ResponseWriter, r *http.Reque
 // @Param       orderId path uint true "Order ID"
 // @Param       status body dto.UpdateOrderStatusRequestDto true "Status"
 // @Success     200
+// @Failure     400 {object} dto.ProblemDetailsDto "Invalid request"
+// @Failure     404 {object} dto.ProblemDetailsDto "Order not found"
+// @Failure     409 {object} dto.ProblemDetailsDto "Invalid status transition"
+// @Failure     413 {object} dto.ProblemDetailsDto "Request body too large"
+// @Failure     422 {object} dto.ProblemDetailsDto "Missing or unknown status"
 // @Router      /v1/order/{orderId}/status [put]
 func (c *orderApiController) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
 	orderId, err := getOrderIDFromPath(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeInvalidRequest(w, r, "Invalid order ID")
 		return
 	}
 
 	var statusRequest dto.UpdateOrderStatusRequestDto
 
-	if err := json.NewDecoder(r.Body).Decode(&statusRequest); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+	if !readRequest(w, r, &statusRequest, func() []dto.FieldErrorDto {
+		return validateUpdateOrderStatus(&statusRequest)
+	}) {
+		return
 	}
 
-	err = c.controller.UpdateOrderStatus(orderId, &statusRequest)
+	err = c.controller.UpdateOrderStatus(r.Context(), orderId, &statusRequest)
 
 	if err != nil {
-		http.Error(w, "Error processing request", http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary     Cancel order
+// @Description Cancel an order that is not yet Pronto or Finalizado
+// @Tags        Order
+// @Accept      json
+// @Produce     json
+// @Param       orderId path uint true "Order ID"
+// @Param       body body dto.CancelOrderRequestDto true "Cancellation"
+// @Success     200
+// @Failure     400 {object} dto.ProblemDetailsDto "Invalid request"
+// @Failure     404 {object} dto.ProblemDetailsDto "Order not found"
+// @Failure     409 {object} dto.ProblemDetailsDto "Order can no longer be cancelled"
+// @Failure     413 {object} dto.ProblemDetailsDto "Request body too large"
+// @Failure     422 {object} dto.ProblemDetailsDto "Unknown reason code or missing author"
+// @Router      /v1/order/{orderId}/cancel [post]
+func (c *orderApiController) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	orderId, err := getOrderIDFromPath(r)
+	if err != nil {
+		writeInvalidRequest(w, r, "Invalid order ID")
+		return
+	}
+
+	var cancelRequest dto.CancelOrderRequestDto
+
+	if !readRequest(w, r, &cancelRequest, func() []dto.FieldErrorDto {
+		return validateCancelOrderRequest(&cancelRequest)
+	}) {
+		return
+	}
+
+	err = c.controller.CancelOrder(r.Context(), orderId, &cancelRequest)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -2,19 +2,26 @@ package controller_test
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/controller"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 	mockController "github.com/viniciuscluna/tc-fiap-50/mocks/order/controller"
+	"gorm.io/gorm"
 )
 
 type OrderApiControllerTestSuite struct {
@@ -34,6 +41,49 @@ func TestOrderApiControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(OrderApiControllerTestSuite))
 }
 
+// statusRecorder counts the status codes written by a handler
+type statusRecorder struct {
+	*httptest.ResponseRecorder
+	statusWrites int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.statusWrites++
+	r.ResponseRecorder.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.statusWrites == 0 {
+		r.statusWrites++
+	}
+	return r.ResponseRecorder.Write(b)
+}
+
+// serve runs the request and asserts that exactly one status code was written
+func (suite *OrderApiControllerTestSuite) serve(req *http.Request) *httptest.ResponseRecorder {
+	w := &statusRecorder{ResponseRecorder: httptest.NewRecorder()}
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(suite.T(), 1, w.statusWrites, "handler must write exactly one status code")
+	return w.ResponseRecorder
+}
+
+// assertProblem checks that the response is a problem details document with the given status
+func (suite *OrderApiControllerTestSuite) assertProblem(w *httptest.ResponseRecorder, status int, problemType string) dto.ProblemDetailsDto {
+	assert.Equal(suite.T(), status, w.Code)
+	assert.Equal(suite.T(), "application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem dto.ProblemDetailsDto
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(suite.T(), status, problem.Status)
+	assert.Equal(suite.T(), problemType, problem.Type)
+	assert.NotEmpty(suite.T(), problem.Title)
+	return problem
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
 // Feature: Order API Controller - Add Order
 // Scenario: Create a new order via HTTP POST
 
@@ -42,25 +92,23 @@ func (suite *OrderApiControllerTestSuite) Test_Add_WithValidRequest_ShouldReturn
 	customerId := uint(1)
 	requestDto := dto.AddOrderDto{
 		CustomerId:  &customerId,
-		TotalAmount: 100.00,
+		TotalAmount: money.MustParse("100.00"),
 		Products: []*dto.AddOrderProductDto{
-			{ProductId: 10, Quantity: 2, Price: 50.00},
+			{ProductId: 10, Quantity: 2, Price: money.MustParse("50.00")},
 		},
 	}
 
 	requestBody, _ := json.Marshal(requestDto)
 
 	suite.mockController.EXPECT().
-		Add(mock.Anything).
+		Add(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return("123", nil).
 		Once()
 
 	// WHEN a POST request is made to /v1/order
 	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
 	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 201
 	assert.Equal(suite.T(), http.StatusCreated, w.Code)
@@ -69,25 +117,41 @@ func (suite *OrderApiControllerTestSuite) Test_Add_WithValidRequest_ShouldReturn
 	suite.mockController.AssertExpectations(suite.T())
 }
 
+func (suite *OrderApiControllerTestSuite) Test_Add_WithoutCustomerId_ShouldCreateGuestOrder() {
+	// GIVEN a guest order request without customer ID
+	requestBody := []byte(`{"guestName":"Maria","products":[{"productId":10,"quantity":1}]}`)
+
+	suite.mockController.EXPECT().
+		Add(mock.Anything, mock.MatchedBy(func(request *dto.AddOrderDto) bool {
+			return request.CustomerId == nil && request.GuestName == "Maria"
+		}), mock.Anything, mock.Anything).
+		Return("124", nil).
+		Once()
+
+	// WHEN a POST request is made to /v1/order
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := suite.serve(req)
+
+	// THEN the response should have status 201
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "124")
+	suite.mockController.AssertExpectations(suite.T())
+}
+
 func (suite *OrderApiControllerTestSuite) Test_Add_WithInvalidJson_ShouldReturn400() {
 	// GIVEN an invalid JSON payload
 	invalidJson := []byte(`{"invalid": json}`)
 
-	// NOTE: Due to missing return statement in implementation, controller still gets called
-	suite.mockController.EXPECT().
-		Add(mock.Anything).
-		Return("", errors.New("ignored")).
-		Maybe()
-
 	// WHEN a POST request is made with invalid JSON
 	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(invalidJson))
 	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+	w := suite.serve(req)
 
-	suite.router.ServeHTTP(w, req)
-
-	// THEN the response should have status 400 (first error wins)
-	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	// THEN the response should be a 400 problem
+	suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
+	// AND the controller should not be called
+	suite.mockController.AssertNotCalled(suite.T(), "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func (suite *OrderApiControllerTestSuite) Test_Add_WithControllerError_ShouldReturn500() {
@@ -95,30 +159,79 @@ func (suite *OrderApiControllerTestSuite) Test_Add_WithControllerError_ShouldRet
 	customerId := uint(1)
 	requestDto := dto.AddOrderDto{
 		CustomerId:  &customerId,
-		TotalAmount: 50.00,
-		Products:    []*dto.AddOrderProductDto{},
+		TotalAmount: money.MustParse("50.00"),
+		Products:    []*dto.AddOrderProductDto{{ProductId: 10, Quantity: 1}},
 	}
 
 	requestBody, _ := json.Marshal(requestDto)
 
 	// AND the controller returns an error
 	suite.mockController.EXPECT().
-		Add(mock.Anything).
+		Add(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return("", errors.New("database error")).
 		Once()
 
 	// WHEN a POST request is made
 	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
 	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 500
-	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
+	suite.assertProblem(w, http.StatusInternalServerError, "/problems/internal")
 	suite.mockController.AssertExpectations(suite.T())
 }
 
+func (suite *OrderApiControllerTestSuite) Test_Add_WithIdempotencyKey_ShouldForwardKeyAndBodyHash() {
+	// GIVEN a request with an Idempotency-Key header
+	requestBody := []byte(`{"guestName":"Maria","products":[{"productId":10,"quantity":1}]}`)
+	bodyHash := sha256.Sum256(requestBody)
+
+	// THEN the key and the SHA-256 of the body should reach the controller
+	suite.mockController.EXPECT().
+		Add(mock.Anything, mock.Anything, "kiosk-1", hex.EncodeToString(bodyHash[:])).
+		Return("124", nil).
+		Once()
+
+	// WHEN a POST request is made to /v1/order
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "kiosk-1")
+	w := suite.serve(req)
+
+	// THEN the response should have status 201
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "124")
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithTooLongIdempotencyKey_ShouldReturn400() {
+	// GIVEN a request with an oversized Idempotency-Key header
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBufferString(`{}`))
+	req.Header.Set("Idempotency-Key", strings.Repeat("k", 256))
+
+	// WHEN a POST request is made to /v1/order
+	w := suite.serve(req)
+
+	// THEN the response should have status 400
+	suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
+	suite.mockController.AssertNotCalled(suite.T(), "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithReusedIdempotencyKey_ShouldReturn409() {
+	// GIVEN the idempotency key was already used with a different body
+	suite.mockController.EXPECT().
+		Add(mock.Anything, mock.Anything, "kiosk-1", mock.Anything).
+		Return("", &domainerrors.IdempotencyKeyReusedError{Key: "kiosk-1"}).
+		Once()
+
+	// WHEN a POST request is made to /v1/order
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBufferString(`{"guestName":"Maria","products":[{"productId":10,"quantity":1}]}`))
+	req.Header.Set("Idempotency-Key", "kiosk-1")
+	w := suite.serve(req)
+
+	// THEN the response should have status 409
+	suite.assertProblem(w, http.StatusConflict, "/problems/conflict")
+}
+
 // Feature: Order API Controller - Get Order
 // Scenario: Retrieve an order by ID via HTTP GET
 
@@ -128,20 +241,18 @@ func (suite *OrderApiControllerTestSuite) Test_GetOrder_WithValidId_ShouldReturn
 
 	responseDto := &dto.GetOrderResponseDto{
 		ID:          123,
-		CustomerId:  1,
-		TotalAmount: 150.00,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("150.00"),
 	}
 
 	suite.mockController.EXPECT().
-		GetOrder(orderId).
+		GetOrder(mock.Anything, orderId).
 		Return(responseDto, nil).
 		Once()
 
 	// WHEN a GET request is made to /v1/order/123
 	req := httptest.NewRequest(http.MethodGet, "/v1/order/123", nil)
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 200
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
@@ -149,7 +260,7 @@ func (suite *OrderApiControllerTestSuite) Test_GetOrder_WithValidId_ShouldReturn
 	var response dto.GetOrderResponseDto
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.Equal(suite.T(), uint(123), response.ID)
-	assert.Equal(suite.T(), float32(150.00), response.TotalAmount)
+	assert.Equal(suite.T(), money.MustParse("150.00"), response.TotalAmount)
 	suite.mockController.AssertExpectations(suite.T())
 }
 
@@ -157,36 +268,65 @@ func (suite *OrderApiControllerTestSuite) Test_GetOrder_WithInvalidId_ShouldRetu
 	// GIVEN an invalid order ID (non-numeric)
 	// WHEN a GET request is made with invalid ID
 	req := httptest.NewRequest(http.MethodGet, "/v1/order/invalid", nil)
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 400
-	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
 	// AND controller should not be called
 	suite.mockController.AssertNotCalled(suite.T(), "GetOrder")
 }
 
-func (suite *OrderApiControllerTestSuite) Test_GetOrder_WithNonExistentId_ShouldReturn500() {
+func (suite *OrderApiControllerTestSuite) Test_GetOrder_WithNonExistentId_ShouldReturn404() {
 	// GIVEN a valid but non-existent order ID
 	orderId := uint(9999)
 
 	suite.mockController.EXPECT().
-		GetOrder(orderId).
-		Return(nil, errors.New("order not found")).
+		GetOrder(mock.Anything, orderId).
+		Return(nil, fmt.Errorf("%w: %w", domainerrors.ErrOrderNotFound, gorm.ErrRecordNotFound)).
 		Once()
 
 	// WHEN a GET request is made
 	req := httptest.NewRequest(http.MethodGet, "/v1/order/9999", nil)
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
-	// THEN the response should have status 500
-	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
+	// THEN the response should be a 404 problem for the requested order
+	problem := suite.assertProblem(w, http.StatusNotFound, "/problems/not-found")
+	assert.Equal(suite.T(), "/v1/order/9999", problem.Instance)
 	suite.mockController.AssertExpectations(suite.T())
 }
 
+func (suite *OrderApiControllerTestSuite) Test_GetOrder_WithControllerError_ShouldReturn500WithoutInternals() {
+	// GIVEN the controller fails with an unexpected error
+	suite.mockController.EXPECT().
+		GetOrder(mock.Anything, uint(123)).
+		Return(nil, errors.New("pq: connection refused")).
+		Once()
+
+	// WHEN a GET request is made
+	req := httptest.NewRequest(http.MethodGet, "/v1/order/123", nil)
+	w := suite.serve(req)
+
+	// THEN the response should be a 500 problem
+	suite.assertProblem(w, http.StatusInternalServerError, "/problems/internal")
+	// AND the underlying error should not be exposed
+	assert.NotContains(suite.T(), w.Body.String(), "connection refused")
+}
+
+func (suite *OrderApiControllerTestSuite) Test_GetOrderStatus_WithNonExistentOrder_ShouldReturn404() {
+	// GIVEN the order has no status
+	suite.mockController.EXPECT().
+		GetOrderStatus(mock.Anything, uint(9999)).
+		Return(nil, domainerrors.ErrOrderNotFound).
+		Once()
+
+	// WHEN a GET request is made
+	req := httptest.NewRequest(http.MethodGet, "/v1/order/9999/status", nil)
+	w := suite.serve(req)
+
+	// THEN the response should be a 404 problem
+	suite.assertProblem(w, http.StatusNotFound, "/problems/not-found")
+}
+
 // Feature: Order API Controller - Get Orders
 // Scenario: List all orders via HTTP GET
 
@@ -194,21 +334,19 @@ func (suite *OrderApiControllerTestSuite) Test_GetOrders_WithOrders_ShouldReturn
 	// GIVEN multiple orders exist
 	responseDto := &dto.GetOrdersResponseDto{
 		Orders: []*dto.GetOrderResponseDto{
-			{ID: 1, CustomerId: 1, TotalAmount: 50.00},
-			{ID: 2, CustomerId: 2, TotalAmount: 75.00},
+			{ID: 1, CustomerId: uintPtr(1), TotalAmount: money.MustParse("50.00")},
+			{ID: 2, CustomerId: uintPtr(2), TotalAmount: money.MustParse("75.00")},
 		},
 	}
 
 	suite.mockController.EXPECT().
-		GetOrders().
+		GetOrders(mock.Anything).
 		Return(responseDto, nil).
 		Once()
 
 	// WHEN a GET request is made to /v1/order
 	req := httptest.NewRequest(http.MethodGet, "/v1/order", nil)
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 200
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
@@ -222,18 +360,16 @@ func (suite *OrderApiControllerTestSuite) Test_GetOrders_WithOrders_ShouldReturn
 func (suite *OrderApiControllerTestSuite) Test_GetOrders_WithControllerError_ShouldReturn500() {
 	// GIVEN the controller returns an error
 	suite.mockController.EXPECT().
-		GetOrders().
+		GetOrders(mock.Anything).
 		Return(nil, errors.New("database connection error")).
 		Once()
 
 	// WHEN a GET request is made
 	req := httptest.NewRequest(http.MethodGet, "/v1/order", nil)
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 500
-	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
+	suite.assertProblem(w, http.StatusInternalServerError, "/problems/internal")
 	suite.mockController.AssertExpectations(suite.T())
 }
 
@@ -244,15 +380,13 @@ func (suite *OrderApiControllerTestSuite) Test_GetOrders_WithNoOrders_ShouldRetu
 	}
 
 	suite.mockController.EXPECT().
-		GetOrders().
+		GetOrders(mock.Anything).
 		Return(responseDto, nil).
 		Once()
 
 	// WHEN a GET request is made
 	req := httptest.NewRequest(http.MethodGet, "/v1/order", nil)
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 200
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
@@ -278,15 +412,13 @@ func (suite *OrderApiControllerTestSuite) Test_GetOrderStatus_WithValidId_Should
 	}
 
 	suite.mockController.EXPECT().
-		GetOrderStatus(orderId).
+		GetOrderStatus(mock.Anything, orderId).
 		Return(responseDto, nil).
 		Once()
 
 	// WHEN a GET request is made to /v1/order/100/status
 	req := httptest.NewRequest(http.MethodGet, "/v1/order/100/status", nil)
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 200
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
@@ -302,12 +434,10 @@ func (suite *OrderApiControllerTestSuite) Test_GetOrderStatus_WithInvalidId_Shou
 	// GIVEN an invalid order ID
 	// WHEN a GET request is made with non-numeric ID
 	req := httptest.NewRequest(http.MethodGet, "/v1/order/abc/status", nil)
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 400
-	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
 	suite.mockController.AssertNotCalled(suite.T(), "GetOrderStatus")
 }
 
@@ -316,18 +446,16 @@ func (suite *OrderApiControllerTestSuite) Test_GetOrderStatus_WithControllerErro
 	orderId := uint(200)
 
 	suite.mockController.EXPECT().
-		GetOrderStatus(orderId).
+		GetOrderStatus(mock.Anything, orderId).
 		Return(nil, errors.New("status not found")).
 		Once()
 
 	// WHEN a GET request is made
 	req := httptest.NewRequest(http.MethodGet, "/v1/order/200/status", nil)
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 500
-	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
+	suite.assertProblem(w, http.StatusInternalServerError, "/problems/internal")
 	suite.mockController.AssertExpectations(suite.T())
 }
 
@@ -344,16 +472,14 @@ func (suite *OrderApiControllerTestSuite) Test_UpdateOrderStatus_WithValidReques
 	requestBody, _ := json.Marshal(requestDto)
 
 	suite.mockController.EXPECT().
-		UpdateOrderStatus(orderId, mock.Anything).
+		UpdateOrderStatus(mock.Anything, orderId, mock.Anything).
 		Return(nil).
 		Once()
 
 	// WHEN a PUT request is made to /v1/order/123/status
 	req := httptest.NewRequest(http.MethodPut, "/v1/order/123/status", bytes.NewBuffer(requestBody))
 	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 200
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
@@ -368,12 +494,10 @@ func (suite *OrderApiControllerTestSuite) Test_UpdateOrderStatus_WithInvalidOrde
 
 	req := httptest.NewRequest(http.MethodPut, "/v1/order/invalid/status", bytes.NewBuffer(requestBody))
 	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 400
-	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
 	suite.mockController.AssertNotCalled(suite.T(), "UpdateOrderStatus")
 }
 
@@ -381,21 +505,15 @@ func (suite *OrderApiControllerTestSuite) Test_UpdateOrderStatus_WithInvalidJson
 	// GIVEN an invalid JSON payload
 	invalidJson := []byte(`{"status": "invalid"}`)
 
-	// NOTE: Due to missing return statement in implementation, controller still gets called
-	suite.mockController.EXPECT().
-		UpdateOrderStatus(mock.Anything, mock.Anything).
-		Return(errors.New("ignored")).
-		Maybe()
-
 	// WHEN a PUT request is made with invalid JSON
 	req := httptest.NewRequest(http.MethodPut, "/v1/order/123/status", bytes.NewBuffer(invalidJson))
 	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
-	// THEN the response should have status 400 (first error wins)
-	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	// THEN the response should have status 400
+	suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
+	// AND controller should not be called
+	suite.mockController.AssertNotCalled(suite.T(), "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func (suite *OrderApiControllerTestSuite) Test_UpdateOrderStatus_WithControllerError_ShouldReturn500() {
@@ -406,18 +524,333 @@ func (suite *OrderApiControllerTestSuite) Test_UpdateOrderStatus_WithControllerE
 
 	// AND the controller returns an error
 	suite.mockController.EXPECT().
-		UpdateOrderStatus(orderId, mock.Anything).
+		UpdateOrderStatus(mock.Anything, orderId, mock.Anything).
 		Return(errors.New("update failed")).
 		Once()
 
 	// WHEN a PUT request is made
 	req := httptest.NewRequest(http.MethodPut, "/v1/order/456/status", bytes.NewBuffer(requestBody))
 	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	suite.router.ServeHTTP(w, req)
+	w := suite.serve(req)
 
 	// THEN the response should have status 500
-	assert.Equal(suite.T(), http.StatusInternalServerError, w.Code)
+	suite.assertProblem(w, http.StatusInternalServerError, "/problems/internal")
 	suite.mockController.AssertExpectations(suite.T())
 }
+
+func (suite *OrderApiControllerTestSuite) Test_UpdateOrderStatus_WithNonExistentOrder_ShouldReturn404() {
+	// GIVEN a status update for an order that does not exist
+	requestBody, _ := json.Marshal(dto.UpdateOrderStatusRequestDto{Status: 2})
+
+	suite.mockController.EXPECT().
+		UpdateOrderStatus(mock.Anything, uint(9999), mock.Anything).
+		Return(domainerrors.ErrOrderNotFound).
+		Once()
+
+	// WHEN a PUT request is made
+	req := httptest.NewRequest(http.MethodPut, "/v1/order/9999/status", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should have status 404
+	suite.assertProblem(w, http.StatusNotFound, "/problems/not-found")
+}
+
+func (suite *OrderApiControllerTestSuite) Test_UpdateOrderStatus_WithInvalidTransition_ShouldReturn409() {
+	// GIVEN a status update that breaks the order lifecycle
+	requestBody, _ := json.Marshal(dto.UpdateOrderStatusRequestDto{Status: 1})
+
+	suite.mockController.EXPECT().
+		UpdateOrderStatus(mock.Anything, uint(10), mock.Anything).
+		Return(&domainerrors.InvalidStatusTransitionError{From: 4, To: 1}).
+		Once()
+
+	// WHEN a PUT request is made
+	req := httptest.NewRequest(http.MethodPut, "/v1/order/10/status", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should have status 409
+	suite.assertProblem(w, http.StatusConflict, "/problems/invalid-transition")
+}
+
+func (suite *OrderApiControllerTestSuite) Test_UpdateOrderStatus_WithUnknownStatus_ShouldReturn422() {
+	// GIVEN a status update with an unknown status
+	requestBody, _ := json.Marshal(dto.UpdateOrderStatusRequestDto{Status: 9})
+
+	suite.mockController.EXPECT().
+		UpdateOrderStatus(mock.Anything, uint(10), mock.Anything).
+		Return(&domainerrors.UnknownStatusError{Status: 9}).
+		Once()
+
+	// WHEN a PUT request is made
+	req := httptest.NewRequest(http.MethodPut, "/v1/order/10/status", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should have status 422
+	suite.assertProblem(w, http.StatusUnprocessableEntity, "/problems/validation-failed")
+}
+
+// Feature: Order API Controller - Cancel Order
+// Scenario: Cancel an order via HTTP POST
+
+func (suite *OrderApiControllerTestSuite) Test_CancelOrder_WithValidRequest_ShouldReturn200() {
+	// GIVEN a valid cancellation request
+	requestBody := []byte(`{"reasonCode":"CUSTOMER_REQUEST","cancelledBy":"kiosk-01"}`)
+
+	suite.mockController.EXPECT().
+		CancelOrder(mock.Anything, uint(123), mock.MatchedBy(func(request *dto.CancelOrderRequestDto) bool {
+			return request.ReasonCode == "CUSTOMER_REQUEST" && request.CancelledBy == "kiosk-01"
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN a POST request is made to /v1/order/123/cancel
+	req := httptest.NewRequest(http.MethodPost, "/v1/order/123/cancel", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := suite.serve(req)
+
+	// THEN the response should have status 200
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.mockController.AssertExpectations(suite.T())
+}
+
+func (suite *OrderApiControllerTestSuite) Test_CancelOrder_WithInvalidJson_ShouldReturn400() {
+	// GIVEN an invalid JSON payload
+	req := httptest.NewRequest(http.MethodPost, "/v1/order/123/cancel", bytes.NewBuffer([]byte(`{"reasonCode":`)))
+
+	// WHEN the request is handled
+	w := suite.serve(req)
+
+	// THEN the response should have status 400
+	suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
+	// AND the controller should not be called
+	suite.mockController.AssertNotCalled(suite.T(), "CancelOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *OrderApiControllerTestSuite) Test_CancelOrder_WhenOrderIsPronto_ShouldReturn409() {
+	// GIVEN an order that is already "Pronto"
+	requestBody := []byte(`{"reasonCode":"CUSTOMER_REQUEST","cancelledBy":"kiosk-01"}`)
+
+	suite.mockController.EXPECT().
+		CancelOrder(mock.Anything, uint(10), mock.Anything).
+		Return(&domainerrors.InvalidStatusTransitionError{From: 3, To: 5}).
+		Once()
+
+	// WHEN a POST request is made
+	req := httptest.NewRequest(http.MethodPost, "/v1/order/10/cancel", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should have status 409
+	suite.assertProblem(w, http.StatusConflict, "/problems/invalid-transition")
+}
+
+func (suite *OrderApiControllerTestSuite) Test_CancelOrder_WithInvalidFields_ShouldReturn422WithFieldErrors() {
+	// GIVEN a cancellation with an unknown reason code and no author
+	requestBody := []byte(`{"reasonCode":"CHANGED_MIND"}`)
+
+	// WHEN a POST request is made
+	req := httptest.NewRequest(http.MethodPost, "/v1/order/10/cancel", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should be a 422 problem listing both fields
+	problem := suite.assertProblem(w, http.StatusUnprocessableEntity, "/problems/validation-failed")
+	fields := make([]string, 0, len(problem.Errors))
+	for _, fieldError := range problem.Errors {
+		fields = append(fields, fieldError.Field)
+	}
+	assert.ElementsMatch(suite.T(), []string{"reasonCode", "cancelledBy"}, fields)
+	// AND the controller should not be called
+	suite.mockController.AssertNotCalled(suite.T(), "CancelOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *OrderApiControllerTestSuite) Test_CancelOrder_WithNonExistentOrder_ShouldReturn404() {
+	// GIVEN a cancellation for an order that does not exist
+	requestBody := []byte(`{"reasonCode":"OTHER","cancelledBy":"staff"}`)
+
+	suite.mockController.EXPECT().
+		CancelOrder(mock.Anything, uint(9999), mock.Anything).
+		Return(domainerrors.ErrOrderNotFound).
+		Once()
+
+	// WHEN a POST request is made
+	req := httptest.NewRequest(http.MethodPost, "/v1/order/9999/cancel", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should have status 404
+	suite.assertProblem(w, http.StatusNotFound, "/problems/not-found")
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithPriceMismatch_ShouldReturn422() {
+	// GIVEN an order whose prices disagree with the catalog
+	requestBody, _ := json.Marshal(dto.AddOrderDto{
+		Products: []*dto.AddOrderProductDto{{ProductId: 1, Quantity: 1, Price: money.MustParse("1.00")}},
+	})
+
+	suite.mockController.EXPECT().
+		Add(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("", &domainerrors.PriceMismatchError{ProductId: 1, Expected: money.MustParse("20.00"), Actual: money.MustParse("1.00")}).
+		Once()
+
+	// WHEN a POST request is made
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should have status 422
+	suite.assertProblem(w, http.StatusUnprocessableEntity, "/problems/validation-failed")
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithProductServiceUnavailable_ShouldReturn503() {
+	// GIVEN the product service is unavailable
+	requestBody, _ := json.Marshal(dto.AddOrderDto{
+		Products: []*dto.AddOrderProductDto{{ProductId: 1, Quantity: 1}},
+	})
+
+	suite.mockController.EXPECT().
+		Add(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("", &domainerrors.UpstreamUnavailableError{Service: "product", Err: errors.New("timeout")}).
+		Once()
+
+	// WHEN a POST request is made
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should have status 503
+	suite.assertProblem(w, http.StatusServiceUnavailable, "/problems/upstream-unavailable")
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithUnknownCustomer_ShouldReturn422() {
+	// GIVEN an order for a customer that does not exist
+	customerId := uint(42)
+	requestBody, _ := json.Marshal(dto.AddOrderDto{
+		CustomerId: &customerId,
+		Products:   []*dto.AddOrderProductDto{{ProductId: 1, Quantity: 1}},
+	})
+
+	suite.mockController.EXPECT().
+		Add(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("", &domainerrors.CustomerNotFoundError{CustomerId: customerId}).
+		Once()
+
+	// WHEN a POST request is made
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should have status 422
+	suite.assertProblem(w, http.StatusUnprocessableEntity, "/problems/validation-failed")
+	assert.Contains(suite.T(), w.Body.String(), "customer 42 not found")
+}
+
+// Feature: Order API Controller - Request validation
+// Scenario: Invalid requests are rejected before reaching the controller
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithUnknownField_ShouldReturn400() {
+	// GIVEN a request with a field that is not part of the contract
+	requestBody := []byte(`{"products":[{"productId":10,"quantity":1}],"discount":10}`)
+
+	// WHEN a POST request is made to /v1/order
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should be a 400 problem naming the field
+	problem := suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
+	assert.Contains(suite.T(), problem.Detail, "discount")
+	suite.mockController.AssertNotCalled(suite.T(), "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithTrailingData_ShouldReturn400() {
+	// GIVEN a request with two JSON documents
+	requestBody := []byte(`{"products":[{"productId":10,"quantity":1}]} {}`)
+
+	// WHEN a POST request is made to /v1/order
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should be a 400 problem
+	suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithTooLargeBody_ShouldReturn413() {
+	// GIVEN a request body over the size limit
+	requestBody := `{"guestName":"` + strings.Repeat("a", 70<<10) + `"}`
+
+	// WHEN a POST request is made to /v1/order
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBufferString(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should be a 413 problem
+	suite.assertProblem(w, http.StatusRequestEntityTooLarge, "/problems/invalid-request")
+	suite.mockController.AssertNotCalled(suite.T(), "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithInvalidFields_ShouldReturn422WithFieldErrors() {
+	// GIVEN a request with a zero customer, a zero quantity, a negative price and a missing product id
+	requestBody := []byte(`{"customerId":0,"products":[{"productId":10,"quantity":0},{"quantity":1,"price":-1.50}]}`)
+
+	// WHEN a POST request is made to /v1/order
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should be a 422 problem listing every invalid field
+	problem := suite.assertProblem(w, http.StatusUnprocessableEntity, "/problems/validation-failed")
+	fields := make([]string, 0, len(problem.Errors))
+	for _, fieldError := range problem.Errors {
+		assert.NotEmpty(suite.T(), fieldError.Message)
+		fields = append(fields, fieldError.Field)
+	}
+	assert.ElementsMatch(suite.T(), []string{"customerId", "products[0].quantity", "products[1].productId", "products[1].price"}, fields)
+	suite.mockController.AssertNotCalled(suite.T(), "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithoutProducts_ShouldReturn422() {
+	// GIVEN a request without products
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBufferString(`{"guestName":"Maria","products":[]}`))
+
+	// WHEN a POST request is made to /v1/order
+	w := suite.serve(req)
+
+	// THEN the products field should be rejected
+	problem := suite.assertProblem(w, http.StatusUnprocessableEntity, "/problems/validation-failed")
+	assert.Equal(suite.T(), []dto.FieldErrorDto{{Field: "products", Message: "must contain at least one product"}}, problem.Errors)
+}
+
+func (suite *OrderApiControllerTestSuite) Test_Add_WithTooManyProducts_ShouldReturn422() {
+	// GIVEN a request with more products than allowed
+	products := make([]*dto.AddOrderProductDto, 51)
+	for i := range products {
+		products[i] = &dto.AddOrderProductDto{ProductId: uint(i + 1), Quantity: 1}
+	}
+	requestBody, _ := json.Marshal(dto.AddOrderDto{Products: products})
+
+	// WHEN a POST request is made to /v1/order
+	req := httptest.NewRequest(http.MethodPost, "/v1/order", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the products field should be rejected
+	problem := suite.assertProblem(w, http.StatusUnprocessableEntity, "/problems/validation-failed")
+	assert.Equal(suite.T(), "products", problem.Errors[0].Field)
+}
+
+func (suite *OrderApiControllerTestSuite) Test_UpdateOrderStatus_WithoutStatus_ShouldReturn422() {
+	// GIVEN a status update without status
+	req := httptest.NewRequest(http.MethodPut, "/v1/order/1/status", bytes.NewBufferString(`{}`))
+
+	// WHEN the request is handled
+	w := suite.serve(req)
+
+	// THEN the status field should be rejected
+	problem := suite.assertProblem(w, http.StatusUnprocessableEntity, "/problems/validation-failed")
+	assert.Equal(suite.T(), []dto.FieldErrorDto{{Field: "status", Message: "is required"}}, problem.Errors)
+	suite.mockController.AssertNotCalled(suite.T(), "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *OrderApiControllerTestSuite) Test_CancelOrder_WithUnknownField_ShouldReturn400() {
+	// GIVEN a cancellation with a field that is not part of the contract
+	requestBody := []byte(`{"reasonCode":"OTHER","cancelledBy":"kiosk-01","refund":true}`)
+
+	// WHEN the request is handled
+	req := httptest.NewRequest(http.MethodPost, "/v1/order/1/cancel", bytes.NewBuffer(requestBody))
+	w := suite.serve(req)
+
+	// THEN the response should be a 400 problem
+	suite.assertProblem(w, http.StatusBadRequest, "/problems/invalid-request")
+	suite.mockController.AssertNotCalled(suite.T(), "CancelOrder", mock.Anything, mock.Anything, mock.Anything)
+}
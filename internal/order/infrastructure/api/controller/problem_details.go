@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/logging"
+	"go.uber.org/zap"
+)
+
+const problemContentType = "application/problem+json"
+
+const (
+	problemTypeInvalidRequest      = "/problems/invalid-request"
+	problemTypeNotFound            = "/problems/not-found"
+	problemTypeInvalidTransition   = "/problems/invalid-transition"
+	problemTypeConflict            = "/problems/conflict"
+	problemTypeValidationFailed    = "/problems/validation-failed"
+	problemTypeUpstreamUnavailable = "/problems/upstream-unavailable"
+	problemTypeInternal            = "/problems/internal"
+)
+
+// writeError maps err to its problem details response. Domain errors are
+// matched by category; anything else is an internal error whose message is
+// not exposed to the client.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domainerrors.ErrNotFound):
+		writeProblem(w, r, http.StatusNotFound, problemTypeNotFound, "Resource not found", err.Error())
+	case errors.Is(err, domainerrors.ErrInvalidTransition):
+		writeProblem(w, r, http.StatusConflict, problemTypeInvalidTransition, "Invalid status transition", err.Error())
+	case errors.Is(err, domainerrors.ErrConflict):
+		writeProblem(w, r, http.StatusConflict, problemTypeConflict, "Conflict", err.Error())
+	case errors.Is(err, domainerrors.ErrValidationFailed):
+		writeProblem(w, r, http.StatusUnprocessableEntity, problemTypeValidationFailed, "Validation failed", err.Error())
+	case errors.Is(err, domainerrors.ErrUpstreamUnavailable):
+		writeProblem(w, r, http.StatusServiceUnavailable, problemTypeUpstreamUnavailable, "Service temporarily unavailable", upstreamDetail(err))
+	default:
+		logging.FromContext(r.Context()).Error("request failed", zap.Error(err))
+		writeProblem(w, r, http.StatusInternalServerError, problemTypeInternal, "Internal server error", "Error processing request")
+	}
+}
+
+// upstreamDetail names the unavailable service without exposing the underlying error.
+func upstreamDetail(err error) string {
+	var upstreamErr *domainerrors.UpstreamUnavailableError
+	if errors.As(err, &upstreamErr) {
+		return fmt.Sprintf("%s service is unavailable", upstreamErr.Service)
+	}
+	return "A downstream service is unavailable"
+}
+
+// writeInvalidRequest rejects a request that could not be read or parsed.
+func writeInvalidRequest(w http.ResponseWriter, r *http.Request, detail string) {
+	writeProblem(w, r, http.StatusBadRequest, problemTypeInvalidRequest, "Invalid request", detail)
+}
+
+// writeValidationProblem rejects a well-formed request whose fields break the validation rules.
+func writeValidationProblem(w http.ResponseWriter, r *http.Request, fieldErrors []dto.FieldErrorDto) {
+	writeProblemDetails(w, &dto.ProblemDetailsDto{
+		Type:     problemTypeValidationFailed,
+		Title:    "Validation failed",
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "The request has invalid fields",
+		Instance: r.URL.Path,
+		Errors:   fieldErrors,
+	})
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, problemType string, title string, detail string) {
+	writeProblemDetails(w, &dto.ProblemDetailsDto{
+		Type:     problemType,
+		Title:    title,
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	})
+}
+
+func writeProblemDetails(w http.ResponseWriter, problem *dto.ProblemDetailsDto) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
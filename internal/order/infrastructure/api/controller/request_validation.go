@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+)
+
+const (
+	maxRequestBodyBytes  = 64 << 10
+	maxOrderProducts     = 50
+	maxProductQuantity   = 99
+	maxGuestNameLength   = 100
+	maxCancelledByLength = 100
+)
+
+var errRequestBodyTooLarge = fmt.Errorf("request body exceeds %d bytes", maxRequestBodyBytes)
+
+// readBody reads the request body, refusing bodies larger than maxRequestBodyBytes.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return nil, errRequestBodyTooLarge
+	}
+	return body, err
+}
+
+// decodeRequest reads a single JSON document into v, rejecting unknown fields
+// and trailing data. On failure the problem response has been written and
+// false is returned.
+func decodeRequest(w http.ResponseWriter, r *http.Request, body []byte, v interface{}) bool {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err == nil && decoder.More() {
+		err = errors.New("request body must contain a single JSON document")
+	}
+	if err != nil {
+		writeInvalidRequest(w, r, fmt.Sprintf("Invalid request payload: %v", err))
+		return false
+	}
+	return true
+}
+
+// readRequest reads, decodes and, when validate is set, validates the request
+// body into v. On failure the problem response has been written and false is
+// returned.
+func readRequest(w http.ResponseWriter, r *http.Request, v interface{}, validate func() []dto.FieldErrorDto) bool {
+	body, err := readBody(w, r)
+	if err != nil {
+		writeBodyError(w, r, err)
+		return false
+	}
+	if !decodeRequest(w, r, body, v) {
+		return false
+	}
+	if validate == nil {
+		return true
+	}
+	if fieldErrors := validate(); len(fieldErrors) > 0 {
+		writeValidationProblem(w, r, fieldErrors)
+		return false
+	}
+	return true
+}
+
+func writeBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, errRequestBodyTooLarge) {
+		writeProblem(w, r, http.StatusRequestEntityTooLarge, problemTypeInvalidRequest, "Request body too large", err.Error())
+		return
+	}
+	writeInvalidRequest(w, r, "Invalid request payload")
+}
+
+func validateAddOrder(request *dto.AddOrderDto) []dto.FieldErrorDto {
+	var fieldErrors []dto.FieldErrorDto
+
+	if request.CustomerId != nil && *request.CustomerId == 0 {
+		fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: "customerId", Message: "must be greater than 0 when present"})
+	}
+	if len(request.GuestName) > maxGuestNameLength {
+		fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: "guestName", Message: fmt.Sprintf("must be at most %d characters", maxGuestNameLength)})
+	}
+	if request.TotalAmount.Amount() < 0 {
+		fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: "totalAmount", Message: "must not be negative"})
+	}
+
+	switch {
+	case len(request.Products) == 0:
+		fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: "products", Message: "must contain at least one product"})
+	case len(request.Products) > maxOrderProducts:
+		fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: "products", Message: fmt.Sprintf("must contain at most %d products", maxOrderProducts)})
+	}
+
+	for i, product := range request.Products {
+		field := fmt.Sprintf("products[%d]", i)
+		if product == nil {
+			fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: field, Message: "must not be null"})
+			continue
+		}
+		if product.ProductId == 0 {
+			fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: field + ".productId", Message: "must be greater than 0"})
+		}
+		if product.Quantity < 1 || product.Quantity > maxProductQuantity {
+			fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: field + ".quantity", Message: fmt.Sprintf("must be between 1 and %d", maxProductQuantity)})
+		}
+		if product.Price.Amount() < 0 {
+			fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: field + ".price", Message: "must not be negative"})
+		}
+	}
+
+	return fieldErrors
+}
+
+func validateUpdateOrderStatus(request *dto.UpdateOrderStatusRequestDto) []dto.FieldErrorDto {
+	if request.Status == 0 {
+		return []dto.FieldErrorDto{{Field: "status", Message: "is required"}}
+	}
+	return nil
+}
+
+func validateCancelOrderRequest(request *dto.CancelOrderRequestDto) []dto.FieldErrorDto {
+	var fieldErrors []dto.FieldErrorDto
+
+	switch {
+	case request.ReasonCode == "":
+		fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: "reasonCode", Message: "is required"})
+	case !entities.IsKnownCancellationReason(request.ReasonCode):
+		fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: "reasonCode", Message: fmt.Sprintf("%q is not a known reason code", request.ReasonCode)})
+	}
+
+	switch {
+	case request.CancelledBy == "":
+		fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: "cancelledBy", Message: "is required"})
+	case len(request.CancelledBy) > maxCancelledByLength:
+		fieldErrors = append(fieldErrors, dto.FieldErrorDto{Field: "cancelledBy", Message: fmt.Sprintf("must be at most %d characters", maxCancelledByLength)})
+	}
+
+	return fieldErrors
+}
@@ -1,13 +1,18 @@
 package dto
 
+import "github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+
+// AddOrderDto is the order creation request. Omit customerId for a guest
+// order; guestName is an optional display name for the pickup panel.
 type AddOrderDto struct {
-	CustomerId  *uint                 `json:"customerId" example:"1"`
-	TotalAmount float32               `json:"totalAmount" example:"34.99"`
+	CustomerId  *uint                 `json:"customerId,omitempty" example:"1"`
+	GuestName   string                `json:"guestName,omitempty" example:"Maria"`
+	TotalAmount money.Money           `json:"totalAmount" swaggertype:"number" example:"34.99"`
 	Products    []*AddOrderProductDto `json:"products"`
 }
 
 type AddOrderProductDto struct {
-	ProductId uint    `json:"productId" example:"1"`
-	Quantity  uint    `json:"quantity" example:"1"`
-	Price     float32 `json:"price" example:"34.99"`
+	ProductId uint        `json:"productId" example:"1"`
+	Quantity  uint        `json:"quantity" example:"1"`
+	Price     money.Money `json:"price" swaggertype:"number" example:"34.99"`
 }
@@ -0,0 +1,8 @@
+package dto
+
+// CancelOrderRequestDto is the cancellation request. ReasonCode is one of
+// CUSTOMER_REQUEST, PAYMENT_FAILED, OUT_OF_STOCK or OTHER.
+type CancelOrderRequestDto struct {
+	ReasonCode  string `json:"reasonCode" example:"CUSTOMER_REQUEST"`
+	CancelledBy string `json:"cancelledBy" example:"kiosk-01"`
+}
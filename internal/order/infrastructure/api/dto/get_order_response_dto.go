@@ -2,6 +2,8 @@ package dto
 
 import (
 	"time"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 )
 
 type CustomerDto struct {
@@ -11,22 +13,26 @@ type CustomerDto struct {
 	CPF   uint   `json:"cpf"`
 }
 
+// GetOrderResponseDto is the presented order. Guest orders have no customer_id
+// or customer and may carry a guest_name.
 type GetOrderResponseDto struct {
-	ID          uint                         `json:"id"`
-	CreatedAt   time.Time                    `json:"created_at"`
-	TotalAmount float32                      `json:"total_amount"`
-	CustomerId  uint                         `json:"customer_id,omitempty"`
-	Customer    *CustomerDto                 `json:"customer,omitempty"`
-	Products    []*OrderProductDto           `json:"products"`
-	Status      []*GetOrderStatusResponseDto `json:"status"`
+	ID                            uint                         `json:"id"`
+	CreatedAt                     time.Time                    `json:"created_at"`
+	TotalAmount                   money.Money                  `json:"total_amount" swaggertype:"number"`
+	CustomerId                    *uint                        `json:"customer_id,omitempty"`
+	GuestName                     string                       `json:"guest_name,omitempty"`
+	Customer                      *CustomerDto                 `json:"customer,omitempty"`
+	CustomerReconciliationPending bool                         `json:"customer_reconciliation_pending,omitempty"`
+	Products                      []*OrderProductDto           `json:"products"`
+	Status                        []*GetOrderStatusResponseDto `json:"status"`
 }
 
 type OrderProductDto struct {
-	ProductId   uint    `json:"product_id"`
-	Price       float32 `json:"price"`
-	Quantity    uint    `json:"quantity"`
-	Name        string  `json:"name,omitempty"`
-	Description string  `json:"description,omitempty"`
-	Category    int     `json:"category,omitempty"`
-	ImageLink   string  `json:"image_link,omitempty"`
+	ProductId   uint        `json:"product_id"`
+	Price       money.Money `json:"price" swaggertype:"number"`
+	Quantity    uint        `json:"quantity"`
+	Name        string      `json:"name,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Category    int         `json:"category,omitempty"`
+	ImageLink   string      `json:"image_link,omitempty"`
 }
@@ -6,4 +6,6 @@ type GetOrderStatusResponseDto struct {
 	CurrentStatus            uint   `json:"current_status"`
 	CurrentStatusDescription string `json:"current_status_description"`
 	OrderId                  uint   `json:"order_id"`
+	ReasonCode               string `json:"reason_code,omitempty"`
+	ChangedBy                string `json:"changed_by,omitempty"`
 }
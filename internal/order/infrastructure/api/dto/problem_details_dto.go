@@ -0,0 +1,19 @@
+package dto
+
+// ProblemDetailsDto is an RFC 7807 error response, served as application/problem+json.
+// Errors lists the offending fields when request validation fails.
+type ProblemDetailsDto struct {
+	Type     string          `json:"type" example:"/problems/not-found"`
+	Title    string          `json:"title" example:"Resource not found"`
+	Status   int             `json:"status" example:"404"`
+	Detail   string          `json:"detail,omitempty" example:"order not found"`
+	Instance string          `json:"instance,omitempty" example:"/v1/order/42"`
+	Errors   []FieldErrorDto `json:"errors,omitempty"`
+}
+
+// FieldErrorDto describes why a request field was rejected. Field is the JSON
+// path of the value, e.g. products[0].quantity.
+type FieldErrorDto struct {
+	Field   string `json:"field" example:"products[0].quantity"`
+	Message string `json:"message" example:"must be between 1 and 99"`
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase"
+)
+
+var (
+	_ usecase.OrderRecorder = (*OrderMetrics)(nil)
+)
+
+// countTimeout bounds the query counting the active orders on each scrape.
+const countTimeout = 5 * time.Second
+
+// statusLabel returns the label value of status in the order metrics.
+func statusLabel(status uint) string {
+	switch status {
+	case entities.OrderStatusRecebido:
+		return "recebido"
+	case entities.OrderStatusEmPreparacao:
+		return "em_preparacao"
+	case entities.OrderStatusPronto:
+		return "pronto"
+	case entities.OrderStatusFinalizado:
+		return "finalizado"
+	case entities.OrderStatusCancelado:
+		return "cancelado"
+	default:
+		return "unknown"
+	}
+}
+
+// OrderMetrics counts the orders created and the status transitions made.
+type OrderMetrics struct {
+	created     prometheus.Counter
+	transitions *prometheus.CounterVec
+}
+
+func NewOrderMetrics(registerer prometheus.Registerer) *OrderMetrics {
+	created := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "orders_created_total",
+		Help: "Orders created. Idempotent replays are not counted.",
+	})
+	transitions := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "order_status_transitions_total",
+		Help: "Order status changes, by previous and new status.",
+	}, []string{"from", "to"})
+	registerer.MustRegister(created, transitions)
+
+	return &OrderMetrics{created: created, transitions: transitions}
+}
+
+// OrderCreated counts a new order.
+func (m *OrderMetrics) OrderCreated() {
+	m.created.Inc()
+}
+
+// StatusChanged counts an order moving from one status to another.
+func (m *OrderMetrics) StatusChanged(from uint, to uint) {
+	m.transitions.WithLabelValues(statusLabel(from), statusLabel(to)).Inc()
+}
+
+// activeOrdersCollector reports how many orders are in each active status,
+// counted in the database on every scrape so all replicas agree.
+type activeOrdersCollector struct {
+	repository repositories.OrderStatusRepository
+	logger     *zap.Logger
+	desc       *prometheus.Desc
+}
+
+// NewActiveOrdersCollector creates the collector of the orders_active gauge.
+func NewActiveOrdersCollector(repository repositories.OrderStatusRepository, logger *zap.Logger) prometheus.Collector {
+	return &activeOrdersCollector{
+		repository: repository,
+		logger:     logger,
+		desc: prometheus.NewDesc(
+			"orders_active",
+			"Orders not yet finished or cancelled, by current status.",
+			[]string{"status"}, nil,
+		),
+	}
+}
+
+func (c *activeOrdersCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *activeOrdersCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+	defer cancel()
+
+	counts, err := c.repository.CountOrdersByStatus(ctx)
+	if err != nil {
+		// Leave the gauge out rather than failing the whole scrape
+		c.logger.Warn("failed to count active orders", zap.Error(err))
+		return
+	}
+
+	for _, status := range entities.ActiveOrderStatuses() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[status]), statusLabel(status))
+	}
+}
@@ -0,0 +1,77 @@
+package metrics_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/metrics"
+	mockRepositories "github.com/viniciuscluna/tc-fiap-50/mocks/order/domain/repositories"
+)
+
+// Feature: Order metrics
+// Scenario: Count status transitions by status name
+func Test_StatusChanged_ShouldCountByStatusName(t *testing.T) {
+	// GIVEN the order metrics
+	registry := prometheus.NewRegistry()
+	orderMetrics := metrics.NewOrderMetrics(registry)
+
+	// WHEN two orders start being prepared and one is cancelled
+	orderMetrics.StatusChanged(entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao)
+	orderMetrics.StatusChanged(entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao)
+	orderMetrics.StatusChanged(entities.OrderStatusEmPreparacao, entities.OrderStatusCancelado)
+
+	// THEN each transition should be counted
+	expected := `
+# HELP order_status_transitions_total Order status changes, by previous and new status.
+# TYPE order_status_transitions_total counter
+order_status_transitions_total{from="em_preparacao",to="cancelado"} 1
+order_status_transitions_total{from="recebido",to="em_preparacao"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "order_status_transitions_total"))
+}
+
+// Scenario: Report the active orders from the database
+func Test_ActiveOrdersCollector_ShouldReportActiveStatuses(t *testing.T) {
+	// GIVEN orders in every status
+	repository := mockRepositories.NewMockOrderStatusRepository(t)
+	repository.EXPECT().CountOrdersByStatus(mock.Anything).Return(map[uint]int64{
+		entities.OrderStatusRecebido:   3,
+		entities.OrderStatusPronto:     1,
+		entities.OrderStatusFinalizado: 40,
+		entities.OrderStatusCancelado:  2,
+	}, nil)
+	collector := metrics.NewActiveOrdersCollector(repository, zap.NewNop())
+
+	// WHEN the gauge is collected
+	expected := `
+# HELP orders_active Orders not yet finished or cancelled, by current status.
+# TYPE orders_active gauge
+orders_active{status="em_preparacao"} 0
+orders_active{status="pronto"} 1
+orders_active{status="recebido"} 3
+`
+
+	// THEN only the active statuses should be reported, empty ones as zero
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func Test_ActiveOrdersCollector_WithDatabaseError_ShouldReportNothing(t *testing.T) {
+	// GIVEN a database that can't be queried
+	repository := mockRepositories.NewMockOrderStatusRepository(t)
+	repository.EXPECT().CountOrdersByStatus(mock.Anything).Return(nil, errors.New("connection refused"))
+	collector := metrics.NewActiveOrdersCollector(repository, zap.NewNop())
+
+	// WHEN the gauge is collected
+	count := testutil.CollectAndCount(collector)
+
+	// THEN the gauge should be left out instead of failing the scrape
+	assert.Equal(t, 0, count)
+}
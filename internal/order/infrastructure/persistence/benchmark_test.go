@@ -0,0 +1,86 @@
+package secondary_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence/persistencetest"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/postgres"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/sqlite"
+)
+
+// seedBenchmarkOrders inserts the benchmark dataset into db. The dataset
+// already carries the current status of each order, so it is inserted in
+// batches without the status hook.
+func seedBenchmarkOrders(b *testing.B, db *gorm.DB) {
+	b.Helper()
+	seed := db.Session(&gorm.Session{SkipHooks: true, Logger: logger.Discard})
+	if err := seed.CreateInBatches(persistencetest.BenchmarkOrders(), 500).Error; err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkSQLite runs the repository benchmarks on a SQLite file, e.g.
+// go test -run '^$' -bench SQLite ./internal/order/infrastructure/persistence
+func BenchmarkSQLite(b *testing.B) {
+	cfg := &config.Config{SQLitePath: filepath.Join(b.TempDir(), "order.db")}
+	db := sqlite.NewSQLiteDB(cfg, zap.NewNop(), prometheus.NewRegistry())
+	b.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	seedBenchmarkOrders(b, db)
+
+	persistencetest.RunBenchmarks(b, gormBackend(db).Repositories)
+}
+
+// BenchmarkPostgres runs the repository benchmarks against the database of
+// the DB_* settings when TEST_POSTGRES is set, e.g.
+// TEST_POSTGRES=1 go test -run '^$' -bench Postgres ./internal/order/infrastructure/persistence
+// Its tables are emptied before the dataset is inserted.
+func BenchmarkPostgres(b *testing.B) {
+	if os.Getenv("TEST_POSTGRES") == "" {
+		b.Skip("TEST_POSTGRES not set")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		b.Fatal(err)
+	}
+	db, err := postgres.Open(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	migrator, err := postgres.NewMigrator(db, zap.NewNop())
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		b.Fatal(err)
+	}
+	if err := db.Exec(`TRUNCATE "order", "order_product", "order_status", "idempotency_key" RESTART IDENTITY CASCADE`).Error; err != nil {
+		b.Fatal(err)
+	}
+
+	seedBenchmarkOrders(b, db)
+	// Refresh the planner statistics, as autovacuum would after a bulk load
+	if err := db.Exec(`ANALYZE "order", "order_product", "order_status"`).Error; err != nil {
+		b.Fatal(err)
+	}
+
+	persistencetest.RunBenchmarks(b, gormBackend(db).Repositories)
+}
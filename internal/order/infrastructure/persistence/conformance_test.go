@@ -0,0 +1,72 @@
+package secondary_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	secondary "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence/persistencetest"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/postgres"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/sqlite"
+)
+
+func gormBackend(db *gorm.DB) persistencetest.Backend {
+	return persistencetest.Backend{
+		Repositories: &repositories.Repositories{
+			Orders:          secondary.NewOrderRepositoryImpl(db),
+			OrderProducts:   secondary.NewOrderProductRepositoryImpl(db),
+			OrderStatus:     secondary.NewOrderStatusRepositoryImpl(db),
+			IdempotencyKeys: secondary.NewIdempotencyKeyRepositoryImpl(db),
+		},
+		UnitOfWork: secondary.NewUnitOfWorkImpl(db),
+	}
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	conformance := &persistencetest.ConformanceSuite{}
+	conformance.NewBackend = func() persistencetest.Backend {
+		cfg := &config.Config{SQLitePath: filepath.Join(conformance.T().TempDir(), "order.db")}
+		db := sqlite.NewSQLiteDB(cfg, zap.NewNop(), prometheus.NewRegistry())
+		conformance.T().Cleanup(func() {
+			if sqlDB, err := db.DB(); err == nil {
+				_ = sqlDB.Close()
+			}
+		})
+		return gormBackend(db)
+	}
+	suite.Run(t, conformance)
+}
+
+// TestPostgresConformance runs against the database of the DB_* settings when
+// TEST_POSTGRES is set, e.g. with the .env of docker-compose:
+// TEST_POSTGRES=1 DB_HOST=localhost DB_USER=order_user DB_PASSWORD=order_pass go test ./...
+// Its tables are emptied before each test.
+func TestPostgresConformance(t *testing.T) {
+	if os.Getenv("TEST_POSTGRES") == "" {
+		t.Skip("TEST_POSTGRES not set")
+	}
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	db, err := postgres.Open(cfg)
+	require.NoError(t, err)
+	migrator, err := postgres.NewMigrator(db, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(context.Background()))
+
+	conformance := &persistencetest.ConformanceSuite{}
+	conformance.NewBackend = func() persistencetest.Backend {
+		require.NoError(conformance.T(), db.Exec(`TRUNCATE "order", "order_product", "order_status", "idempotency_key" RESTART IDENTITY CASCADE`).Error)
+		return gormBackend(db)
+	}
+	suite.Run(t, conformance)
+}
@@ -0,0 +1,58 @@
+package secondary
+
+import (
+	"context"
+
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	_ repositories.IdempotencyKeyRepository = (*IdempotencyKeyRepositoryImpl)(nil)
+)
+
+type IdempotencyKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepositoryImpl(db *gorm.DB) *IdempotencyKeyRepositoryImpl {
+	return &IdempotencyKeyRepositoryImpl{db: db}
+}
+
+func (r *IdempotencyKeyRepositoryImpl) GetIdempotencyKey(ctx context.Context, key string, now time.Time) (*entities.IdempotencyKeyEntity, error) {
+	idempotencyKey := &entities.IdempotencyKeyEntity{}
+	if err := r.db.WithContext(ctx).Where("key = ? AND expires_at > ?", key, now).First(idempotencyKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %w", domainerrors.ErrIdempotencyKeyNotFound, err)
+		}
+		return nil, err
+	}
+	return idempotencyKey, nil
+}
+
+func (r *IdempotencyKeyRepositoryImpl) AddIdempotencyKey(ctx context.Context, idempotencyKey *entities.IdempotencyKeyEntity) error {
+	// Insert the key, or take over an expired one. A key still in use is left
+	// untouched and reported as a conflict; concurrent inserts of the same key
+	// are serialized by the primary key.
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"request_hash", "order_id", "created_at", "expires_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "idempotency_key.expires_at <= ?", Vars: []interface{}{idempotencyKey.CreatedAt}},
+		}},
+	}).Create(idempotencyKey)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrIdempotencyKeyConflict
+	}
+	return nil
+}
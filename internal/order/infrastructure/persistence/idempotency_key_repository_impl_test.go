@@ -0,0 +1,119 @@
+package secondary_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	secondary "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type IdempotencyKeyRepositoryTestSuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repository *secondary.IdempotencyKeyRepositoryImpl
+}
+
+func (suite *IdempotencyKeyRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(suite.T(), err)
+
+	err = db.AutoMigrate(&entities.IdempotencyKeyEntity{})
+	assert.NoError(suite.T(), err)
+
+	suite.db = db
+	suite.repository = secondary.NewIdempotencyKeyRepositoryImpl(db)
+}
+
+func (suite *IdempotencyKeyRepositoryTestSuite) TearDownTest() {
+	sqlDB, err := suite.db.DB()
+	if err == nil {
+		sqlDB.Close()
+	}
+}
+
+func TestIdempotencyKeyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyKeyRepositoryTestSuite))
+}
+
+func newIdempotencyKey(key string, requestHash string, orderId uint, createdAt time.Time) *entities.IdempotencyKeyEntity {
+	return &entities.IdempotencyKeyEntity{
+		Key:         key,
+		RequestHash: requestHash,
+		OrderId:     orderId,
+		CreatedAt:   createdAt,
+		ExpiresAt:   createdAt.Add(time.Hour),
+	}
+}
+
+// Feature: Idempotency Key Repository
+// Scenario: Store and look up idempotency keys
+
+func (suite *IdempotencyKeyRepositoryTestSuite) Test_AddIdempotencyKey_WithNewKey_ShouldBeReturnedUntilExpired() {
+	// GIVEN a stored idempotency key
+	now := time.Now()
+	err := suite.repository.AddIdempotencyKey(context.Background(), newIdempotencyKey("kiosk-1", "hash-a", 42, now))
+	assert.NoError(suite.T(), err)
+
+	// WHEN the key is looked up before it expires
+	idempotencyKey, err := suite.repository.GetIdempotencyKey(context.Background(), "kiosk-1", now.Add(time.Minute))
+
+	// THEN the stored order and request hash should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(42), idempotencyKey.OrderId)
+	assert.Equal(suite.T(), "hash-a", idempotencyKey.RequestHash)
+
+	// AND the key should no longer be found once expired
+	_, err = suite.repository.GetIdempotencyKey(context.Background(), "kiosk-1", now.Add(2*time.Hour))
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrIdempotencyKeyNotFound)
+}
+
+func (suite *IdempotencyKeyRepositoryTestSuite) Test_GetIdempotencyKey_WithUnknownKey_ShouldReturnNotFound() {
+	// WHEN an unknown key is looked up
+	idempotencyKey, err := suite.repository.GetIdempotencyKey(context.Background(), "unknown", time.Now())
+
+	// THEN a not found error should be returned
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrIdempotencyKeyNotFound)
+	assert.Nil(suite.T(), idempotencyKey)
+}
+
+func (suite *IdempotencyKeyRepositoryTestSuite) Test_AddIdempotencyKey_WithKeyInUse_ShouldReturnConflict() {
+	// GIVEN a stored idempotency key
+	now := time.Now()
+	err := suite.repository.AddIdempotencyKey(context.Background(), newIdempotencyKey("kiosk-1", "hash-a", 42, now))
+	assert.NoError(suite.T(), err)
+
+	// WHEN the same key is stored again before it expires
+	err = suite.repository.AddIdempotencyKey(context.Background(), newIdempotencyKey("kiosk-1", "hash-b", 43, now.Add(time.Minute)))
+
+	// THEN a conflict should be returned
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrIdempotencyKeyConflict)
+	// AND the original key should be kept
+	idempotencyKey, err := suite.repository.GetIdempotencyKey(context.Background(), "kiosk-1", now.Add(time.Minute))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(42), idempotencyKey.OrderId)
+}
+
+func (suite *IdempotencyKeyRepositoryTestSuite) Test_AddIdempotencyKey_WithExpiredKey_ShouldReplaceIt() {
+	// GIVEN an idempotency key that has expired
+	now := time.Now()
+	err := suite.repository.AddIdempotencyKey(context.Background(), newIdempotencyKey("kiosk-1", "hash-a", 42, now))
+	assert.NoError(suite.T(), err)
+
+	// WHEN the key is reused after it expired
+	later := now.Add(2 * time.Hour)
+	err = suite.repository.AddIdempotencyKey(context.Background(), newIdempotencyKey("kiosk-1", "hash-b", 43, later))
+
+	// THEN the key should point to the new order
+	assert.NoError(suite.T(), err)
+	idempotencyKey, err := suite.repository.GetIdempotencyKey(context.Background(), "kiosk-1", later)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(43), idempotencyKey.OrderId)
+	assert.Equal(suite.T(), "hash-b", idempotencyKey.RequestHash)
+}
@@ -0,0 +1,20 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence/memory"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence/persistencetest"
+)
+
+func BenchmarkMemory(b *testing.B) {
+	store := memory.NewStore()
+	repos := &repositories.Repositories{
+		Orders:      memory.NewOrderRepositoryImpl(store),
+		OrderStatus: memory.NewOrderStatusRepositoryImpl(store),
+	}
+	persistencetest.AddBenchmarkOrders(b, repos)
+
+	persistencetest.RunBenchmarks(b, repos)
+}
@@ -0,0 +1,26 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence/memory"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence/persistencetest"
+)
+
+func TestMemoryConformance(t *testing.T) {
+	suite.Run(t, &persistencetest.ConformanceSuite{NewBackend: func() persistencetest.Backend {
+		store := memory.NewStore()
+		return persistencetest.Backend{
+			Repositories: &repositories.Repositories{
+				Orders:          memory.NewOrderRepositoryImpl(store),
+				OrderProducts:   memory.NewOrderProductRepositoryImpl(store),
+				OrderStatus:     memory.NewOrderStatusRepositoryImpl(store),
+				IdempotencyKeys: memory.NewIdempotencyKeyRepositoryImpl(store),
+			},
+			UnitOfWork: memory.NewUnitOfWorkImpl(store),
+		}
+	}})
+}
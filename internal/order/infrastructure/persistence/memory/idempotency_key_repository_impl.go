@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+)
+
+var (
+	_ repositories.IdempotencyKeyRepository = (*IdempotencyKeyRepositoryImpl)(nil)
+)
+
+type IdempotencyKeyRepositoryImpl struct {
+	access
+}
+
+func NewIdempotencyKeyRepositoryImpl(store *Store) *IdempotencyKeyRepositoryImpl {
+	return &IdempotencyKeyRepositoryImpl{access{store: store}}
+}
+
+func (r *IdempotencyKeyRepositoryImpl) GetIdempotencyKey(ctx context.Context, key string, now time.Time) (*entities.IdempotencyKeyEntity, error) {
+	var idempotencyKey *entities.IdempotencyKeyEntity
+	err := r.read(ctx, func(s *state) error {
+		stored, ok := s.idempotencyKeys[key]
+		if !ok || !stored.ExpiresAt.After(now) {
+			return domainerrors.ErrIdempotencyKeyNotFound
+		}
+		idempotencyKeyCopy := *stored
+		idempotencyKey = &idempotencyKeyCopy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return idempotencyKey, nil
+}
+
+func (r *IdempotencyKeyRepositoryImpl) AddIdempotencyKey(ctx context.Context, idempotencyKey *entities.IdempotencyKeyEntity) error {
+	return r.write(ctx, func(s *state) error {
+		// Take over an expired key, keep one still in use
+		if stored, ok := s.idempotencyKeys[idempotencyKey.Key]; ok && stored.ExpiresAt.After(idempotencyKey.CreatedAt) {
+			return domainerrors.ErrIdempotencyKeyConflict
+		}
+		idempotencyKey.CreatedAt = createdAt(idempotencyKey.CreatedAt)
+
+		stored := *idempotencyKey
+		put(s, s.idempotencyKeys, idempotencyKey.Key, &stored)
+		return nil
+	})
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+)
+
+var (
+	_ repositories.OrderProductRepository = (*OrderProductRepositoryImpl)(nil)
+)
+
+type OrderProductRepositoryImpl struct {
+	access
+}
+
+func NewOrderProductRepositoryImpl(store *Store) *OrderProductRepositoryImpl {
+	return &OrderProductRepositoryImpl{access{store: store}}
+}
+
+func (r *OrderProductRepositoryImpl) AddOrderProduct(ctx context.Context, orderProduct *entities.OrderProductEntity) error {
+	return r.write(ctx, func(s *state) error {
+		s.addOrderProduct(orderProduct)
+		return nil
+	})
+}
+
+func (s *state) addOrderProduct(orderProduct *entities.OrderProductEntity) {
+	s.lastOrderProductId++
+	orderProduct.ID = s.lastOrderProductId
+
+	stored := *orderProduct
+	stored.Order = entities.OrderEntity{}
+	put(s, s.orderProducts, stored.OrderId, append(slices.Clip(s.orderProducts[stored.OrderId]), &stored))
+}
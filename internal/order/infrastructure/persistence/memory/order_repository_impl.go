@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+)
+
+var (
+	_ repositories.OrderRepository = (*OrderRepositoryImpl)(nil)
+)
+
+type OrderRepositoryImpl struct {
+	access
+}
+
+func NewOrderRepositoryImpl(store *Store) *OrderRepositoryImpl {
+	return &OrderRepositoryImpl{access{store: store}}
+}
+
+func (r *OrderRepositoryImpl) AddOrder(ctx context.Context, order *entities.OrderEntity) (*entities.OrderEntity, error) {
+	err := r.write(ctx, func(s *state) error {
+		s.lastOrderId++
+		order.ID = s.lastOrderId
+		order.CreatedAt = createdAt(order.CreatedAt)
+
+		stored := *order
+		stored.Products, stored.Status = nil, nil
+		if order.CustomerId != nil {
+			customerId := *order.CustomerId
+			stored.CustomerId = &customerId
+		}
+		if order.StatusUpdatedAt != nil {
+			statusUpdatedAt := *order.StatusUpdatedAt
+			stored.StatusUpdatedAt = &statusUpdatedAt
+		}
+		put(s, s.orders, order.ID, &stored)
+
+		// Like GORM, also create the products and statuses of the order
+		for _, orderProduct := range order.Products {
+			orderProduct.OrderId = order.ID
+			s.addOrderProduct(orderProduct)
+		}
+		for _, orderStatus := range order.Status {
+			orderStatus.OrderId = order.ID
+			s.addOrderStatus(orderStatus)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (r *OrderRepositoryImpl) GetOrder(ctx context.Context, orderId uint) (*entities.OrderEntity, error) {
+	var order *entities.OrderEntity
+	err := r.read(ctx, func(s *state) error {
+		if _, ok := s.orders[orderId]; !ok {
+			return domainerrors.ErrOrderNotFound
+		}
+		order = s.order(orderId)
+		// Latest status first
+		slices.Reverse(order.Status)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetOrders lists the active orders, oldest first, with their products and
+// their status history, latest first.
+func (r *OrderRepositoryImpl) GetOrders(ctx context.Context) ([]*entities.OrderEntity, error) {
+	orders := []*entities.OrderEntity{}
+	err := r.read(ctx, func(s *state) error {
+		for orderId, stored := range s.orders {
+			if !slices.Contains(entities.ActiveOrderStatuses(), stored.CurrentStatus) {
+				continue
+			}
+			order := s.order(orderId)
+			slices.Reverse(order.Status)
+			orders = append(orders, order)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first
+	slices.SortFunc(orders, func(a, b *entities.OrderEntity) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return orders, nil
+}
+
+// order returns a copy of the order with its products and statuses, in the
+// order they were added.
+func (s *state) order(orderId uint) *entities.OrderEntity {
+	order := *s.orders[orderId]
+	if order.CustomerId != nil {
+		customerId := *order.CustomerId
+		order.CustomerId = &customerId
+	}
+
+	if order.StatusUpdatedAt != nil {
+		statusUpdatedAt := *order.StatusUpdatedAt
+		order.StatusUpdatedAt = &statusUpdatedAt
+	}
+
+	order.Products = []*entities.OrderProductEntity{}
+	for _, orderProduct := range s.orderProducts[orderId] {
+		orderProductCopy := *orderProduct
+		order.Products = append(order.Products, &orderProductCopy)
+	}
+	order.Status = []*entities.OrderStatusEntity{}
+	for _, orderStatus := range s.orderStatus[orderId] {
+		orderStatusCopy := *orderStatus
+		order.Status = append(order.Status, &orderStatusCopy)
+	}
+	return &order
+}
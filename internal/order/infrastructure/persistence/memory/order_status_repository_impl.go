@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+)
+
+var (
+	_ repositories.OrderStatusRepository = (*OrderStatusRepositoryImpl)(nil)
+)
+
+type OrderStatusRepositoryImpl struct {
+	access
+}
+
+func NewOrderStatusRepositoryImpl(store *Store) *OrderStatusRepositoryImpl {
+	return &OrderStatusRepositoryImpl{access{store: store}}
+}
+
+func (r *OrderStatusRepositoryImpl) AddOrderStatus(ctx context.Context, orderStatus *entities.OrderStatusEntity) error {
+	return r.write(ctx, func(s *state) error {
+		s.addOrderStatus(orderStatus)
+		return nil
+	})
+}
+
+func (r *OrderStatusRepositoryImpl) ChangeOrderStatus(ctx context.Context, orderStatus *entities.OrderStatusEntity, from uint) error {
+	return r.write(ctx, func(s *state) error {
+		statuses := s.orderStatus[orderStatus.OrderId]
+		if _, ok := s.orders[orderStatus.OrderId]; !ok || len(statuses) == 0 {
+			return domainerrors.ErrOrderNotFound
+		}
+		if statuses[len(statuses)-1].CurrentStatus != from {
+			return &domainerrors.StatusChangedError{From: from, To: orderStatus.CurrentStatus}
+		}
+		s.addOrderStatus(orderStatus)
+		return nil
+	})
+}
+
+func (r *OrderStatusRepositoryImpl) GetOrderStatus(ctx context.Context, orderId uint) (*entities.OrderStatusEntity, error) {
+	var orderStatus *entities.OrderStatusEntity
+	err := r.read(ctx, func(s *state) error {
+		statuses := s.orderStatus[orderId]
+		if _, ok := s.orders[orderId]; !ok || len(statuses) == 0 {
+			return domainerrors.ErrOrderNotFound
+		}
+		orderStatusCopy := *statuses[len(statuses)-1]
+		orderStatus = &orderStatusCopy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orderStatus, nil
+}
+
+func (r *OrderStatusRepositoryImpl) CountOrdersByStatus(ctx context.Context) (map[uint]int64, error) {
+	counts := map[uint]int64{}
+	err := r.read(ctx, func(s *state) error {
+		for _, order := range s.orders {
+			if slices.Contains(entities.ActiveOrderStatuses(), order.CurrentStatus) {
+				counts[order.CurrentStatus]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (s *state) addOrderStatus(orderStatus *entities.OrderStatusEntity) {
+	s.lastOrderStatusId++
+	orderStatus.ID = s.lastOrderStatusId
+	orderStatus.CreatedAt = createdAt(orderStatus.CreatedAt)
+
+	stored := *orderStatus
+	stored.Order = entities.OrderEntity{}
+	put(s, s.orderStatus, stored.OrderId, append(slices.Clip(s.orderStatus[stored.OrderId]), &stored))
+
+	// Like the database, make it the current status of its order
+	if order, ok := s.orders[stored.OrderId]; ok {
+		updated := *order
+		updated.CurrentStatus = stored.CurrentStatus
+		updated.StatusUpdatedAt = &stored.CreatedAt
+		put(s, s.orders, stored.OrderId, &updated)
+	}
+}
@@ -0,0 +1,126 @@
+// Package memory keeps orders in memory, to run the service without a
+// database. Data is lost on restart.
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+)
+
+// Store holds the orders shared by the repositories created from it. It is
+// safe for concurrent use.
+type Store struct {
+	mu    sync.RWMutex
+	state *state
+}
+
+func NewStore() *Store {
+	return &Store{state: newState()}
+}
+
+// state is the content of a store. Entities are copied in and out so callers
+// never share them with the store. Products and statuses are indexed by order,
+// in the order they were added.
+type state struct {
+	orders          map[uint]*entities.OrderEntity
+	orderProducts   map[uint][]*entities.OrderProductEntity
+	orderStatus     map[uint][]*entities.OrderStatusEntity
+	idempotencyKeys map[string]*entities.IdempotencyKeyEntity
+
+	lastOrderId        uint
+	lastOrderProductId uint
+	lastOrderStatusId  uint
+
+	// undo holds how to revert each write of the running unit of work, if any
+	undo []func()
+}
+
+func newState() *state {
+	return &state{
+		orders:          map[uint]*entities.OrderEntity{},
+		orderProducts:   map[uint][]*entities.OrderProductEntity{},
+		orderStatus:     map[uint][]*entities.OrderStatusEntity{},
+		idempotencyKeys: map[string]*entities.IdempotencyKeyEntity{},
+	}
+}
+
+// put sets the value of key in m, one of the maps of s. Entities are never
+// changed in place, and the slices of products and statuses are never
+// appended to in place, so restoring the previous value undoes the write.
+func put[K comparable, V any](s *state, m map[K]V, key K, value V) {
+	if s.undo != nil {
+		previous, existed := m[key]
+		s.undo = append(s.undo, func() {
+			if existed {
+				m[key] = previous
+			} else {
+				delete(m, key)
+			}
+		})
+	}
+	m[key] = value
+}
+
+// begin starts logging the writes to s, so they can be rolled back.
+func (s *state) begin() {
+	lastOrderId, lastOrderProductId, lastOrderStatusId := s.lastOrderId, s.lastOrderProductId, s.lastOrderStatusId
+	s.undo = []func(){func() {
+		s.lastOrderId, s.lastOrderProductId, s.lastOrderStatusId = lastOrderId, lastOrderProductId, lastOrderStatusId
+	}}
+}
+
+// rollback reverts the writes logged since begin, latest first.
+func (s *state) rollback() {
+	for _, undo := range slices.Backward(s.undo) {
+		undo()
+	}
+	s.undo = nil
+}
+
+// commit keeps the writes logged since begin.
+func (s *state) commit() {
+	s.undo = nil
+}
+
+// access gives the repositories the state of the store, under its lock unless
+// they are bound to a unit of work, which already holds it.
+type access struct {
+	store *Store
+	inTx  bool
+}
+
+func (a access) read(ctx context.Context, fn func(s *state) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if a.inTx {
+		return fn(a.store.state)
+	}
+	a.store.mu.RLock()
+	defer a.store.mu.RUnlock()
+	return fn(a.store.state)
+}
+
+func (a access) write(ctx context.Context, fn func(s *state) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if a.inTx {
+		return fn(a.store.state)
+	}
+	a.store.mu.Lock()
+	defer a.store.mu.Unlock()
+	return fn(a.store.state)
+}
+
+// createdAt defaults a creation time to now, as the database would.
+func createdAt(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+)
+
+var (
+	_ repositories.UnitOfWork = (*UnitOfWorkImpl)(nil)
+)
+
+type UnitOfWorkImpl struct {
+	store *Store
+}
+
+func NewUnitOfWorkImpl(store *Store) *UnitOfWorkImpl {
+	return &UnitOfWorkImpl{store: store}
+}
+
+// Do runs fn on the store, undoing its writes unless it succeeds. Units of
+// work, and reads and writes outside them, run one at a time.
+func (u *UnitOfWorkImpl) Do(ctx context.Context, fn func(repos *repositories.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	state := u.store.state
+	state.begin()
+	committed := false
+	defer func() {
+		if !committed {
+			state.rollback()
+		}
+	}()
+
+	tx := access{store: u.store, inTx: true}
+	if err := fn(&repositories.Repositories{
+		Orders:          &OrderRepositoryImpl{tx},
+		OrderProducts:   &OrderProductRepositoryImpl{tx},
+		OrderStatus:     &OrderStatusRepositoryImpl{tx},
+		IdempotencyKeys: &IdempotencyKeyRepositoryImpl{tx},
+	}); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	state.commit()
+	committed = true
+	return nil
+}
@@ -1,6 +1,8 @@
 package secondary
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
 	"gorm.io/gorm"
@@ -18,8 +20,8 @@ func NewOrderProductRepositoryImpl(db *gorm.DB) *OrderProductRepositoryImpl {
 	return &OrderProductRepositoryImpl{db: db}
 }
 
-func (r *OrderProductRepositoryImpl) AddOrderProduct(orderProduct *entities.OrderProductEntity) error {
-	if err := r.db.Create(orderProduct).Error; err != nil {
+func (r *OrderProductRepositoryImpl) AddOrderProduct(ctx context.Context, orderProduct *entities.OrderProductEntity) error {
+	if err := r.db.WithContext(ctx).Create(orderProduct).Error; err != nil {
 		return err
 	}
 	return nil
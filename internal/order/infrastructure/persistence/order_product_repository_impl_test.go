@@ -1,12 +1,14 @@
 package secondary_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	secondary "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -44,19 +46,19 @@ func TestOrderProductRepositoryTestSuite(t *testing.T) {
 
 func (suite *OrderProductRepositoryTestSuite) Test_AddOrderProduct_WithValidData_ShouldCreateSuccessfully() {
 	// GIVEN an existing order
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	// AND a valid order product entity
 	orderProduct := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 10,
-		Price:     25.50,
+		Price:     money.MustParse("25.50"),
 		Quantity:  2,
 	}
 
 	// WHEN the order product is added to the repository
-	err := suite.repository.AddOrderProduct(orderProduct)
+	err := suite.repository.AddOrderProduct(context.Background(), orderProduct)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -72,26 +74,26 @@ func (suite *OrderProductRepositoryTestSuite) Test_AddOrderProduct_WithValidData
 
 func (suite *OrderProductRepositoryTestSuite) Test_AddOrderProduct_WithMultipleProducts_ShouldCreateAll() {
 	// GIVEN an existing order
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 200.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("200.00")}
 	suite.db.Create(order)
 
 	// AND multiple order product entities
 	product1 := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 10,
-		Price:     50.00,
+		Price:     money.MustParse("50.00"),
 		Quantity:  2,
 	}
 	product2 := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 20,
-		Price:     100.00,
+		Price:     money.MustParse("100.00"),
 		Quantity:  1,
 	}
 
 	// WHEN both products are added
-	err1 := suite.repository.AddOrderProduct(product1)
-	err2 := suite.repository.AddOrderProduct(product2)
+	err1 := suite.repository.AddOrderProduct(context.Background(), product1)
+	err2 := suite.repository.AddOrderProduct(context.Background(), product2)
 
 	// THEN both operations should complete without errors
 	assert.NoError(suite.T(), err1)
@@ -104,26 +106,26 @@ func (suite *OrderProductRepositoryTestSuite) Test_AddOrderProduct_WithMultipleP
 
 func (suite *OrderProductRepositoryTestSuite) Test_AddOrderProduct_WithDifferentPrices_ShouldCreateWithCorrectValues() {
 	// GIVEN an existing order
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	// AND order products with different prices
 	product1 := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 10,
-		Price:     10.50,
+		Price:     money.MustParse("10.50"),
 		Quantity:  1,
 	}
 	product2 := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 20,
-		Price:     89.50,
+		Price:     money.MustParse("89.50"),
 		Quantity:  1,
 	}
 
 	// WHEN both products are added
-	err1 := suite.repository.AddOrderProduct(product1)
-	err2 := suite.repository.AddOrderProduct(product2)
+	err1 := suite.repository.AddOrderProduct(context.Background(), product1)
+	err2 := suite.repository.AddOrderProduct(context.Background(), product2)
 
 	// THEN both operations should complete without errors
 	assert.NoError(suite.T(), err1)
@@ -131,24 +133,24 @@ func (suite *OrderProductRepositoryTestSuite) Test_AddOrderProduct_WithDifferent
 	// AND prices should be preserved correctly
 	var saved1 entities.OrderProductEntity
 	suite.db.Where("product_id = ?", 10).First(&saved1)
-	assert.Equal(suite.T(), float32(10.50), saved1.Price)
+	assert.Equal(suite.T(), money.MustParse("10.50"), saved1.Price)
 }
 
 func (suite *OrderProductRepositoryTestSuite) Test_AddOrderProduct_WithZeroQuantity_ShouldCreate() {
 	// GIVEN an existing order
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 0}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("0")}
 	suite.db.Create(order)
 
 	// AND an order product with zero quantity
 	orderProduct := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 10,
-		Price:     25.00,
+		Price:     money.MustParse("25.00"),
 		Quantity:  0,
 	}
 
 	// WHEN the order product is added
-	err := suite.repository.AddOrderProduct(orderProduct)
+	err := suite.repository.AddOrderProduct(context.Background(), orderProduct)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -160,26 +162,26 @@ func (suite *OrderProductRepositoryTestSuite) Test_AddOrderProduct_WithZeroQuant
 
 func (suite *OrderProductRepositoryTestSuite) Test_AddOrderProduct_WithSameProductMultipleTimes_ShouldCreateSeparateRecords() {
 	// GIVEN an existing order
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	// AND the same product added multiple times
 	product1 := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 10,
-		Price:     25.00,
+		Price:     money.MustParse("25.00"),
 		Quantity:  1,
 	}
 	product2 := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 10,
-		Price:     25.00,
+		Price:     money.MustParse("25.00"),
 		Quantity:  1,
 	}
 
 	// WHEN both are added
-	err1 := suite.repository.AddOrderProduct(product1)
-	err2 := suite.repository.AddOrderProduct(product2)
+	err1 := suite.repository.AddOrderProduct(context.Background(), product1)
+	err2 := suite.repository.AddOrderProduct(context.Background(), product2)
 
 	// THEN both operations should complete without errors
 	assert.NoError(suite.T(), err1)
@@ -1,6 +1,12 @@
 package secondary
 
 import (
+	"context"
+
+	"errors"
+	"fmt"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
 	"gorm.io/gorm"
@@ -18,35 +24,42 @@ func NewOrderRepositoryImpl(db *gorm.DB) *OrderRepositoryImpl {
 	return &OrderRepositoryImpl{db: db}
 }
 
-func (r *OrderRepositoryImpl) AddOrder(order *entities.OrderEntity) (*entities.OrderEntity, error) {
-	if err := r.db.Create(order).Error; err != nil {
+func (r *OrderRepositoryImpl) AddOrder(ctx context.Context, order *entities.OrderEntity) (*entities.OrderEntity, error) {
+	if err := r.db.WithContext(ctx).Create(order).Error; err != nil {
 		return nil, err
 	}
 	return order, nil
 }
 
-func (r *OrderRepositoryImpl) GetOrder(orderId uint) (*entities.OrderEntity, error) {
+// latestStatusFirst orders a status history from the last entry added, as
+// latestOrderStatus does.
+func latestStatusFirst(db *gorm.DB) *gorm.DB {
+	return db.Order("id DESC")
+}
+
+func (r *OrderRepositoryImpl) GetOrder(ctx context.Context, orderId uint) (*entities.OrderEntity, error) {
 	order := &entities.OrderEntity{}
-	if err := r.db.
+	if err := r.db.WithContext(ctx).
 		Preload("Products").
-		Preload("Status", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at DESC")
-		}).
+		Preload("Status", latestStatusFirst).
 		Where("id = ?", orderId).
 		First(order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %w", domainerrors.ErrOrderNotFound, err)
+		}
 		return nil, err
 	}
 	return order, nil
 }
 
-func (r *OrderRepositoryImpl) GetOrders() ([]*entities.OrderEntity, error) {
+// GetOrders lists the active orders, oldest first, with their products and
+// their status history, latest first.
+func (r *OrderRepositoryImpl) GetOrders(ctx context.Context) ([]*entities.OrderEntity, error) {
 	var orders []*entities.OrderEntity
-	if err := r.db.
+	if err := r.db.WithContext(ctx).
 		Preload("Products").
-		Preload("Status", func(db *gorm.DB) *gorm.DB {
-			return db.Order("current_status DESC")
-		}).
-		Where("id NOT IN (SELECT order_id FROM order_status WHERE current_status = 4)").
+		Preload("Status", latestStatusFirst).
+		Where("current_status IN ?", entities.ActiveOrderStatuses()).
 		Order("created_at ASC").
 		Find(&orders).Error; err != nil {
 		return nil, err
@@ -1,13 +1,16 @@
 package secondary_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	secondary "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -40,19 +43,23 @@ func TestOrderRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(OrderRepositoryTestSuite))
 }
 
+func uintPtr(v uint) *uint {
+	return &v
+}
+
 // Feature: Order Repository - Add Order
 // Scenario: Create a new order successfully
 
 func (suite *OrderRepositoryTestSuite) Test_AddOrder_WithValidData_ShouldCreateSuccessfully() {
 	// GIVEN a valid order entity
 	order := &entities.OrderEntity{
-		CustomerId:  1,
-		TotalAmount: 100.50,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("100.50"),
 		CreatedAt:   time.Now(),
 	}
 
 	// WHEN the order is added to the repository
-	result, err := suite.repository.AddOrder(order)
+	result, err := suite.repository.AddOrder(context.Background(), order)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -68,11 +75,11 @@ func (suite *OrderRepositoryTestSuite) Test_AddOrder_WithValidData_ShouldCreateS
 func (suite *OrderRepositoryTestSuite) Test_AddOrder_WithMinimalData_ShouldCreateWithDefaults() {
 	// GIVEN an order with minimal required data
 	order := &entities.OrderEntity{
-		CustomerId: 2,
+		CustomerId: uintPtr(2),
 	}
 
 	// WHEN the order is added
-	result, err := suite.repository.AddOrder(order)
+	result, err := suite.repository.AddOrder(context.Background(), order)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -82,21 +89,63 @@ func (suite *OrderRepositoryTestSuite) Test_AddOrder_WithMinimalData_ShouldCreat
 	assert.NotZero(suite.T(), result.CreatedAt)
 }
 
+func (suite *OrderRepositoryTestSuite) Test_AddOrder_WithGuestOrder_ShouldStoreNullCustomer() {
+	// GIVEN a guest order without customer ID
+	order := &entities.OrderEntity{
+		GuestName:   "Maria",
+		TotalAmount: money.MustParse("20.00"),
+	}
+
+	// WHEN the order is added and read back
+	created, err := suite.repository.AddOrder(context.Background(), order)
+	assert.NoError(suite.T(), err)
+	result, err := suite.repository.GetOrder(context.Background(), created.ID)
+
+	// THEN the customer should remain empty instead of id 0
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), result.CustomerId)
+	// AND the guest name should be preserved
+	assert.Equal(suite.T(), "Maria", result.GuestName)
+
+	var nullCustomers int64
+	suite.db.Model(&entities.OrderEntity{}).Where("customer_id IS NULL").Count(&nullCustomers)
+	assert.Equal(suite.T(), int64(1), nullCustomers)
+}
+
+func (suite *OrderRepositoryTestSuite) Test_AddOrder_ShouldStoreTotalAsMinorUnits() {
+	// GIVEN an order with a decimal total
+	order := &entities.OrderEntity{TotalAmount: money.MustParse("34.99")}
+
+	// WHEN the order is added and read back
+	created, err := suite.repository.AddOrder(context.Background(), order)
+	assert.NoError(suite.T(), err)
+	result, err := suite.repository.GetOrder(context.Background(), created.ID)
+
+	// THEN the total should round trip exactly
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("34.99"), result.TotalAmount)
+
+	// AND the column should hold integer cents
+	var storedCents int64
+	suite.db.Raw(`SELECT total_amount FROM "order" WHERE id = ?`, created.ID).Scan(&storedCents)
+	assert.Equal(suite.T(), int64(3499), storedCents)
+}
+
 // Feature: Order Repository - Get Order
 // Scenario: Retrieve an order by ID with preloaded relations
 
 func (suite *OrderRepositoryTestSuite) Test_GetOrder_WithValidId_ShouldReturnOrderWithPreloadedData() {
 	// GIVEN an existing order with products and status
 	order := &entities.OrderEntity{
-		CustomerId:  1,
-		TotalAmount: 150.00,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("150.00"),
 	}
 	suite.db.Create(order)
 
 	product := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 10,
-		Price:     50.00,
+		Price:     money.MustParse("50.00"),
 		Quantity:  3,
 	}
 	suite.db.Create(product)
@@ -109,7 +158,7 @@ func (suite *OrderRepositoryTestSuite) Test_GetOrder_WithValidId_ShouldReturnOrd
 	suite.db.Create(status)
 
 	// WHEN the order is retrieved by ID
-	result, err := suite.repository.GetOrder(order.ID)
+	result, err := suite.repository.GetOrder(context.Background(), order.ID)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -132,7 +181,7 @@ func (suite *OrderRepositoryTestSuite) Test_GetOrder_WithInvalidId_ShouldReturnE
 	nonExistentId := uint(9999)
 
 	// WHEN attempting to retrieve the order
-	result, err := suite.repository.GetOrder(nonExistentId)
+	result, err := suite.repository.GetOrder(context.Background(), nonExistentId)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -140,13 +189,29 @@ func (suite *OrderRepositoryTestSuite) Test_GetOrder_WithInvalidId_ShouldReturnE
 	assert.Nil(suite.T(), result)
 	// AND the error should be a record not found error
 	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+	// AND it should be reported as a missing order
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrOrderNotFound)
+}
+
+func (suite *OrderRepositoryTestSuite) Test_GetOrders_WithCancelledContext_ShouldReturnContextError() {
+	// GIVEN a request context that has already been cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// WHEN attempting to retrieve the orders
+	results, err := suite.repository.GetOrders(ctx)
+
+	// THEN the cancellation should be returned
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+	// AND no orders should be returned
+	assert.Nil(suite.T(), results)
 }
 
 func (suite *OrderRepositoryTestSuite) Test_GetOrder_WithMultipleStatuses_ShouldOrderByCreatedAtDesc() {
 	// GIVEN an order with multiple status updates
 	order := &entities.OrderEntity{
-		CustomerId:  1,
-		TotalAmount: 200.00,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("200.00"),
 	}
 	suite.db.Create(order)
 
@@ -165,7 +230,7 @@ func (suite *OrderRepositoryTestSuite) Test_GetOrder_WithMultipleStatuses_Should
 	suite.db.Create(newStatus)
 
 	// WHEN the order is retrieved
-	result, err := suite.repository.GetOrder(order.ID)
+	result, err := suite.repository.GetOrder(context.Background(), order.ID)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -181,20 +246,20 @@ func (suite *OrderRepositoryTestSuite) Test_GetOrder_WithMultipleStatuses_Should
 
 func (suite *OrderRepositoryTestSuite) Test_GetOrders_ShouldExcludeFinishedOrders() {
 	// GIVEN multiple orders with different statuses
-	activeOrder1 := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	activeOrder1 := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(activeOrder1)
 	suite.db.Create(&entities.OrderStatusEntity{OrderId: activeOrder1.ID, CurrentStatus: 1})
 
-	activeOrder2 := &entities.OrderEntity{CustomerId: 2, TotalAmount: 150.00}
+	activeOrder2 := &entities.OrderEntity{CustomerId: uintPtr(2), TotalAmount: money.MustParse("150.00")}
 	suite.db.Create(activeOrder2)
 	suite.db.Create(&entities.OrderStatusEntity{OrderId: activeOrder2.ID, CurrentStatus: 2})
 
-	finishedOrder := &entities.OrderEntity{CustomerId: 3, TotalAmount: 200.00}
+	finishedOrder := &entities.OrderEntity{CustomerId: uintPtr(3), TotalAmount: money.MustParse("200.00")}
 	suite.db.Create(finishedOrder)
 	suite.db.Create(&entities.OrderStatusEntity{OrderId: finishedOrder.ID, CurrentStatus: 4})
 
 	// WHEN all orders are retrieved
-	results, err := suite.repository.GetOrders()
+	results, err := suite.repository.GetOrders(context.Background())
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -207,26 +272,53 @@ func (suite *OrderRepositoryTestSuite) Test_GetOrders_ShouldExcludeFinishedOrder
 	}
 }
 
+func (suite *OrderRepositoryTestSuite) Test_GetOrders_ShouldExcludeCancelledOrders() {
+	// GIVEN an active order
+	activeOrder := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
+	suite.db.Create(activeOrder)
+	suite.db.Create(&entities.OrderStatusEntity{OrderId: activeOrder.ID, CurrentStatus: entities.OrderStatusRecebido})
+
+	// AND an order cancelled while in preparation
+	cancelledOrder := &entities.OrderEntity{CustomerId: uintPtr(2), TotalAmount: money.MustParse("150.00")}
+	suite.db.Create(cancelledOrder)
+	suite.db.Create(&entities.OrderStatusEntity{OrderId: cancelledOrder.ID, CurrentStatus: entities.OrderStatusRecebido})
+	suite.db.Create(&entities.OrderStatusEntity{OrderId: cancelledOrder.ID, CurrentStatus: entities.OrderStatusEmPreparacao})
+	suite.db.Create(&entities.OrderStatusEntity{
+		OrderId:       cancelledOrder.ID,
+		CurrentStatus: entities.OrderStatusCancelado,
+		ReasonCode:    entities.CancellationReasonOutOfStock,
+		ChangedBy:     "staff-7",
+	})
+
+	// WHEN all orders are retrieved
+	results, err := suite.repository.GetOrders(context.Background())
+
+	// THEN only the active order should be returned
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 1)
+	assert.Equal(suite.T(), activeOrder.ID, results[0].ID)
+}
+
 func (suite *OrderRepositoryTestSuite) Test_GetOrders_ShouldOrderByCreatedAtAsc() {
 	// GIVEN multiple active orders created at different times
 	oldOrder := &entities.OrderEntity{
-		CustomerId:  1,
-		TotalAmount: 100.00,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("100.00"),
 		CreatedAt:   time.Now().Add(-2 * time.Hour),
 	}
 	suite.db.Create(oldOrder)
 	suite.db.Create(&entities.OrderStatusEntity{OrderId: oldOrder.ID, CurrentStatus: 1})
 
 	newOrder := &entities.OrderEntity{
-		CustomerId:  2,
-		TotalAmount: 150.00,
+		CustomerId:  uintPtr(2),
+		TotalAmount: money.MustParse("150.00"),
 		CreatedAt:   time.Now(),
 	}
 	suite.db.Create(newOrder)
 	suite.db.Create(&entities.OrderStatusEntity{OrderId: newOrder.ID, CurrentStatus: 1})
 
 	// WHEN all orders are retrieved
-	results, err := suite.repository.GetOrders()
+	results, err := suite.repository.GetOrders(context.Background())
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -239,7 +331,7 @@ func (suite *OrderRepositoryTestSuite) Test_GetOrders_ShouldOrderByCreatedAtAsc(
 func (suite *OrderRepositoryTestSuite) Test_GetOrders_WithNoOrders_ShouldReturnEmptyList() {
 	// GIVEN an empty database
 	// WHEN all orders are retrieved
-	results, err := suite.repository.GetOrders()
+	results, err := suite.repository.GetOrders(context.Background())
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -250,13 +342,13 @@ func (suite *OrderRepositoryTestSuite) Test_GetOrders_WithNoOrders_ShouldReturnE
 
 func (suite *OrderRepositoryTestSuite) Test_GetOrders_ShouldPreloadProductsAndStatus() {
 	// GIVEN an order with products and status
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	product := &entities.OrderProductEntity{
 		OrderId:   order.ID,
 		ProductId: 5,
-		Price:     25.00,
+		Price:     money.MustParse("25.00"),
 		Quantity:  4,
 	}
 	suite.db.Create(product)
@@ -268,7 +360,7 @@ func (suite *OrderRepositoryTestSuite) Test_GetOrders_ShouldPreloadProductsAndSt
 	suite.db.Create(status)
 
 	// WHEN all orders are retrieved
-	results, err := suite.repository.GetOrders()
+	results, err := suite.repository.GetOrders(context.Background())
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
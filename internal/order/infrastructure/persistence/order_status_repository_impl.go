@@ -1,9 +1,16 @@
 package secondary
 
 import (
+	"context"
+
+	"errors"
+	"fmt"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
@@ -18,17 +25,74 @@ func NewOrderStatusRepositoryImpl(db *gorm.DB) *OrderStatusRepositoryImpl {
 	return &OrderStatusRepositoryImpl{db: db}
 }
 
-func (r *OrderStatusRepositoryImpl) AddOrderStatus(orderStatus *entities.OrderStatusEntity) error {
-	if err := r.db.Create(orderStatus).Error; err != nil {
+func (r *OrderStatusRepositoryImpl) AddOrderStatus(ctx context.Context, orderStatus *entities.OrderStatusEntity) error {
+	if err := r.db.WithContext(ctx).Create(orderStatus).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *OrderStatusRepositoryImpl) GetOrderStatus(orderId uint) (*entities.OrderStatusEntity, error) {
+func (r *OrderStatusRepositoryImpl) ChangeOrderStatus(ctx context.Context, orderStatus *entities.OrderStatusEntity, from uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Concurrent changes of the same order wait for its row lock, so only
+		// the first still finds from as the latest status
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			First(&entities.OrderEntity{}, orderStatus.OrderId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %w", domainerrors.ErrOrderNotFound, err)
+			}
+			return err
+		}
+		latest, err := latestOrderStatus(tx, orderStatus.OrderId)
+		if err != nil {
+			return err
+		}
+		if latest.CurrentStatus != from {
+			return &domainerrors.StatusChangedError{From: from, To: orderStatus.CurrentStatus}
+		}
+		return tx.Create(orderStatus).Error
+	})
+}
+
+// latestOrderStatus returns the last entry added to the status history of the
+// order. Entries are ordered by id rather than by their creation time, which
+// comes from the clock of the replica that added them.
+func latestOrderStatus(db *gorm.DB, orderId uint) (*entities.OrderStatusEntity, error) {
 	orderStatus := &entities.OrderStatusEntity{}
-	if err := r.db.Where("order_id = ?", orderId).Last(orderStatus).Error; err != nil {
+	if err := db.Where("order_id = ?", orderId).Order("id DESC").First(orderStatus).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %w", domainerrors.ErrOrderNotFound, err)
+		}
 		return nil, err
 	}
 	return orderStatus, nil
 }
+
+// GetOrderStatus returns the latest entry of the status history of the order,
+// the one ChangeOrderStatus compares against.
+func (r *OrderStatusRepositoryImpl) GetOrderStatus(ctx context.Context, orderId uint) (*entities.OrderStatusEntity, error) {
+	return latestOrderStatus(r.db.WithContext(ctx), orderId)
+}
+
+func (r *OrderStatusRepositoryImpl) CountOrdersByStatus(ctx context.Context) (map[uint]int64, error) {
+	var rows []struct {
+		CurrentStatus uint
+		Orders        int64
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&entities.OrderEntity{}).
+		Select("current_status, COUNT(*) AS orders").
+		Where("current_status IN ?", entities.ActiveOrderStatuses()).
+		Group("current_status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CurrentStatus] = row.Orders
+	}
+	return counts, nil
+}
@@ -1,13 +1,16 @@
 package secondary_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	secondary "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -45,7 +48,7 @@ func TestOrderStatusRepositoryTestSuite(t *testing.T) {
 
 func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithValidData_ShouldCreateSuccessfully() {
 	// GIVEN an existing order
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	// AND a valid order status entity
@@ -56,7 +59,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithValidData_S
 	}
 
 	// WHEN the order status is added to the repository
-	err := suite.repository.AddOrderStatus(orderStatus)
+	err := suite.repository.AddOrderStatus(context.Background(), orderStatus)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -70,7 +73,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithValidData_S
 
 func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithStatusRecebido_ShouldCreateWithStatus1() {
 	// GIVEN an existing order
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 50.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("50.00")}
 	suite.db.Create(order)
 
 	// AND an order status for "Recebido" (status 1)
@@ -80,7 +83,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithStatusReceb
 	}
 
 	// WHEN the status is added
-	err := suite.repository.AddOrderStatus(orderStatus)
+	err := suite.repository.AddOrderStatus(context.Background(), orderStatus)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -92,7 +95,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithStatusReceb
 
 func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithMultipleStatuses_ShouldCreateHistory() {
 	// GIVEN an existing order
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	// AND multiple status updates
@@ -113,9 +116,9 @@ func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithMultipleSta
 	}
 
 	// WHEN all statuses are added
-	err1 := suite.repository.AddOrderStatus(status1)
-	err2 := suite.repository.AddOrderStatus(status2)
-	err3 := suite.repository.AddOrderStatus(status3)
+	err1 := suite.repository.AddOrderStatus(context.Background(), status1)
+	err2 := suite.repository.AddOrderStatus(context.Background(), status2)
+	err3 := suite.repository.AddOrderStatus(context.Background(), status3)
 
 	// THEN all operations should complete without errors
 	assert.NoError(suite.T(), err1)
@@ -129,7 +132,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithMultipleSta
 
 func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithDifferentStatuses_ShouldCreateCorrectly() {
 	// GIVEN an existing order
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	// AND status updates with different values
@@ -143,8 +146,8 @@ func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithDifferentSt
 	}
 
 	// WHEN both statuses are added
-	err1 := suite.repository.AddOrderStatus(status1)
-	err2 := suite.repository.AddOrderStatus(status2)
+	err1 := suite.repository.AddOrderStatus(context.Background(), status1)
+	err2 := suite.repository.AddOrderStatus(context.Background(), status2)
 
 	// THEN both operations should complete without errors
 	assert.NoError(suite.T(), err1)
@@ -160,7 +163,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_AddOrderStatus_WithDifferentSt
 
 func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithValidOrderId_ShouldReturnLatestStatus() {
 	// GIVEN an existing order with multiple statuses
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	oldStatus := &entities.OrderStatusEntity{
@@ -178,7 +181,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithValidOrderI
 	suite.db.Create(latestStatus)
 
 	// WHEN the order status is retrieved
-	result, err := suite.repository.GetOrderStatus(order.ID)
+	result, err := suite.repository.GetOrderStatus(context.Background(), order.ID)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -191,7 +194,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithValidOrderI
 
 func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithSingleStatus_ShouldReturnThatStatus() {
 	// GIVEN an order with a single status
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	status := &entities.OrderStatusEntity{
@@ -201,7 +204,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithSingleStatu
 	suite.db.Create(status)
 
 	// WHEN the order status is retrieved
-	result, err := suite.repository.GetOrderStatus(order.ID)
+	result, err := suite.repository.GetOrderStatus(context.Background(), order.ID)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -216,7 +219,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithInvalidOrde
 	nonExistentId := uint(9999)
 
 	// WHEN attempting to retrieve the order status
-	result, err := suite.repository.GetOrderStatus(nonExistentId)
+	result, err := suite.repository.GetOrderStatus(context.Background(), nonExistentId)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -224,15 +227,17 @@ func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithInvalidOrde
 	assert.Nil(suite.T(), result)
 	// AND the error should be a record not found error
 	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+	// AND the error should be translated to the domain not found error
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrOrderNotFound)
 }
 
 func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithNoStatus_ShouldReturnError() {
 	// GIVEN an order without any status
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 100.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("100.00")}
 	suite.db.Create(order)
 
 	// WHEN attempting to retrieve the order status
-	result, err := suite.repository.GetOrderStatus(order.ID)
+	result, err := suite.repository.GetOrderStatus(context.Background(), order.ID)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -242,7 +247,7 @@ func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithNoStatus_Sh
 
 func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithAllStatusTypes_ShouldReturnLatest() {
 	// GIVEN an order that went through all status stages
-	order := &entities.OrderEntity{CustomerId: 1, TotalAmount: 150.00}
+	order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("150.00")}
 	suite.db.Create(order)
 
 	statuses := []uint{1, 2, 3, 4}
@@ -256,10 +261,53 @@ func (suite *OrderStatusRepositoryTestSuite) Test_GetOrderStatus_WithAllStatusTy
 	}
 
 	// WHEN the order status is retrieved
-	result, err := suite.repository.GetOrderStatus(order.ID)
+	result, err := suite.repository.GetOrderStatus(context.Background(), order.ID)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
 	// AND the latest status (Finalizado - 4) should be returned
 	assert.Equal(suite.T(), uint(4), result.CurrentStatus)
 }
+
+// Feature: Order Status Repository - Count Orders By Status
+// Scenario: Count each order under its latest status
+
+func (suite *OrderStatusRepositoryTestSuite) Test_CountOrdersByStatus_ShouldCountLatestStatusOnly() {
+	// GIVEN orders that went through different status histories
+	histories := [][]uint{
+		{entities.OrderStatusRecebido},
+		{entities.OrderStatusRecebido},
+		{entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao},
+		{entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao, entities.OrderStatusPronto},
+		{entities.OrderStatusRecebido, entities.OrderStatusCancelado},
+	}
+	for _, history := range histories {
+		order := &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("10.00")}
+		suite.db.Create(order)
+		for _, statusValue := range history {
+			suite.db.Create(&entities.OrderStatusEntity{OrderId: order.ID, CurrentStatus: statusValue})
+		}
+	}
+
+	// WHEN the orders are counted by status
+	counts, err := suite.repository.CountOrdersByStatus(context.Background())
+
+	// THEN each active order should only count under its current status
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[uint]int64{
+		entities.OrderStatusRecebido:     2,
+		entities.OrderStatusEmPreparacao: 1,
+		entities.OrderStatusPronto:       1,
+	}, counts)
+}
+
+func (suite *OrderStatusRepositoryTestSuite) Test_CountOrdersByStatus_WithoutOrders_ShouldReturnEmpty() {
+	// GIVEN no orders
+
+	// WHEN the orders are counted by status
+	counts, err := suite.repository.CountOrdersByStatus(context.Background())
+
+	// THEN no status should be counted
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), counts)
+}
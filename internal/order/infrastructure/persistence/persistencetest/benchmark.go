@@ -0,0 +1,112 @@
+package persistencetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+)
+
+const (
+	// BenchmarkOrderCount is the number of orders of the benchmark dataset.
+	BenchmarkOrderCount = 100_000
+	// BenchmarkActiveOrderCount is how many of them are still in progress: the
+	// latest ones, as in a restaurant where older orders are finished.
+	BenchmarkActiveOrderCount = 1_000
+)
+
+// BenchmarkOrders returns the orders of the benchmark dataset, oldest first,
+// each with a product and its status history. Every twentieth closed order is
+// cancelled, the others are finished. CurrentStatus and StatusUpdatedAt are
+// already set, so the dataset can be inserted in batches without hooks.
+func BenchmarkOrders() []*entities.OrderEntity {
+	start := time.Now().Add(-BenchmarkOrderCount * time.Minute).Truncate(time.Second)
+	orders := make([]*entities.OrderEntity, BenchmarkOrderCount)
+	for i := range orders {
+		createdAt := start.Add(time.Duration(i) * time.Minute)
+
+		var statuses []uint
+		switch {
+		case i >= BenchmarkOrderCount-BenchmarkActiveOrderCount:
+			statuses = []uint{entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao, entities.OrderStatusPronto}[:i%3+1]
+		case i%20 == 0:
+			statuses = []uint{entities.OrderStatusRecebido, entities.OrderStatusCancelado}
+		default:
+			statuses = []uint{entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao,
+				entities.OrderStatusPronto, entities.OrderStatusFinalizado}
+		}
+
+		customerId := uint(i%500 + 1)
+		order := &entities.OrderEntity{
+			CreatedAt:   createdAt,
+			TotalAmount: money.MustParse("25.90"),
+			CustomerId:  &customerId,
+			Products: []*entities.OrderProductEntity{
+				{ProductId: uint(i%50 + 1), Price: money.MustParse("12.95"), Quantity: 2},
+			},
+		}
+		for j, status := range statuses {
+			statusCreatedAt := createdAt.Add(time.Duration(j) * 10 * time.Second)
+			order.Status = append(order.Status, &entities.OrderStatusEntity{
+				CreatedAt:     statusCreatedAt,
+				CurrentStatus: status,
+			})
+			order.CurrentStatus = status
+			order.StatusUpdatedAt = &statusCreatedAt
+		}
+		orders[i] = order
+	}
+	return orders
+}
+
+// AddBenchmarkOrders adds the benchmark dataset through the repositories.
+func AddBenchmarkOrders(b *testing.B, repos *repositories.Repositories) {
+	b.Helper()
+	for _, order := range BenchmarkOrders() {
+		if _, err := repos.Orders.AddOrder(context.Background(), order); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// RunBenchmarks measures the queries of the order lists and of the kitchen
+// dashboard against repositories holding the benchmark dataset.
+func RunBenchmarks(b *testing.B, repos *repositories.Repositories) {
+	ctx := context.Background()
+
+	b.Run("GetOrders", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			orders, err := repos.Orders.GetOrders(ctx)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(orders) != BenchmarkActiveOrderCount {
+				b.Fatalf("expected %d active orders, got %d", BenchmarkActiveOrderCount, len(orders))
+			}
+		}
+	})
+
+	b.Run("GetOrderStatus", func(b *testing.B) {
+		b.ReportAllocs()
+		orderId := uint(0)
+		for b.Loop() {
+			orderId = orderId%BenchmarkOrderCount + 1
+			if _, err := repos.OrderStatus.GetOrderStatus(ctx, orderId); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("CountOrdersByStatus", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := repos.OrderStatus.CountOrdersByStatus(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
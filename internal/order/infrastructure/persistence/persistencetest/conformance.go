@@ -0,0 +1,429 @@
+// Package persistencetest checks that a storage backend behaves as the order
+// use cases expect, whatever stores the orders.
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+)
+
+// Backend is an empty storage: its repositories, used outside a unit of work,
+// and its unit of work.
+type Backend struct {
+	Repositories *repositories.Repositories
+	UnitOfWork   repositories.UnitOfWork
+}
+
+// ConformanceSuite runs the same scenarios against any backend. NewBackend is
+// called before each test and must return an empty storage.
+type ConformanceSuite struct {
+	suite.Suite
+	NewBackend func() Backend
+
+	repos      *repositories.Repositories
+	unitOfWork repositories.UnitOfWork
+}
+
+func (suite *ConformanceSuite) SetupTest() {
+	backend := suite.NewBackend()
+	suite.repos = backend.Repositories
+	suite.unitOfWork = backend.UnitOfWork
+}
+
+// addOrder adds an order created at createdAt, with a product and statuses
+// one minute apart.
+func (suite *ConformanceSuite) addOrder(createdAt time.Time, statuses ...uint) *entities.OrderEntity {
+	ctx := context.Background()
+	customerId := uint(1)
+	order, err := suite.repos.Orders.AddOrder(ctx, &entities.OrderEntity{
+		CustomerId:  &customerId,
+		TotalAmount: money.MustParse("25.90"),
+		CreatedAt:   createdAt,
+	})
+	require.NoError(suite.T(), err)
+
+	require.NoError(suite.T(), suite.repos.OrderProducts.AddOrderProduct(ctx, &entities.OrderProductEntity{
+		OrderId: order.ID, ProductId: 10, Price: money.MustParse("12.95"), Quantity: 2,
+	}))
+	for i, status := range statuses {
+		require.NoError(suite.T(), suite.repos.OrderStatus.AddOrderStatus(ctx, &entities.OrderStatusEntity{
+			OrderId:       order.ID,
+			CurrentStatus: status,
+			CreatedAt:     createdAt.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	return order
+}
+
+// Feature: Storage conformance - Orders
+func (suite *ConformanceSuite) Test_AddOrder_ShouldBeReadBackWithProductsAndStatus() {
+	// GIVEN an order with a product, received then in preparation
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	order := suite.addOrder(createdAt, entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao)
+
+	// WHEN it is read back
+	result, err := suite.repos.Orders.GetOrder(context.Background(), order.ID)
+
+	// THEN it should have its fields, its product and its statuses, latest first
+	require.NoError(suite.T(), err)
+	assert.NotZero(suite.T(), order.ID)
+	assert.Equal(suite.T(), uint(1), *result.CustomerId)
+	assert.Equal(suite.T(), money.MustParse("25.90"), result.TotalAmount)
+	assert.True(suite.T(), createdAt.Equal(result.CreatedAt))
+	require.Len(suite.T(), result.Products, 1)
+	assert.Equal(suite.T(), uint(10), result.Products[0].ProductId)
+	assert.Equal(suite.T(), money.MustParse("12.95"), result.Products[0].Price)
+	require.Len(suite.T(), result.Status, 2)
+	assert.Equal(suite.T(), entities.OrderStatusEmPreparacao, result.Status[0].CurrentStatus)
+}
+
+func (suite *ConformanceSuite) Test_AddOrder_WithGuest_ShouldKeepCustomerEmpty() {
+	// GIVEN a guest order
+	order, err := suite.repos.Orders.AddOrder(context.Background(), &entities.OrderEntity{GuestName: "Maria"})
+	require.NoError(suite.T(), err)
+
+	// WHEN it is read back
+	result, err := suite.repos.Orders.GetOrder(context.Background(), order.ID)
+
+	// THEN it should have no customer and a creation time
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), result.CustomerId)
+	assert.Equal(suite.T(), "Maria", result.GuestName)
+	assert.NotZero(suite.T(), result.CreatedAt)
+}
+
+func (suite *ConformanceSuite) Test_GetOrder_WithUnknownId_ShouldReturnOrderNotFound() {
+	// GIVEN no order
+	// WHEN an unknown order is read
+	result, err := suite.repos.Orders.GetOrder(context.Background(), 9999)
+
+	// THEN it should be reported as not found
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrOrderNotFound)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *ConformanceSuite) Test_GetOrders_ShouldListActiveOrdersOldestFirst() {
+	// GIVEN active, finished and cancelled orders
+	now := time.Now().Truncate(time.Second)
+	newer := suite.addOrder(now.Add(-time.Minute), entities.OrderStatusRecebido)
+	older := suite.addOrder(now.Add(-time.Hour), entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao)
+	suite.addOrder(now.Add(-2*time.Hour), entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao,
+		entities.OrderStatusPronto, entities.OrderStatusFinalizado)
+	suite.addOrder(now.Add(-3*time.Hour), entities.OrderStatusRecebido, entities.OrderStatusCancelado)
+
+	// WHEN the orders are listed
+	results, err := suite.repos.Orders.GetOrders(context.Background())
+
+	// THEN only the active ones should be listed, oldest first
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), results, 2)
+	assert.Equal(suite.T(), older.ID, results[0].ID)
+	assert.Equal(suite.T(), newer.ID, results[1].ID)
+	// AND with their products and statuses
+	assert.Len(suite.T(), results[0].Products, 1)
+	assert.Len(suite.T(), results[0].Status, 2)
+}
+
+func (suite *ConformanceSuite) Test_GetOrders_WithNoOrders_ShouldReturnEmptyList() {
+	// GIVEN no order
+	// WHEN the orders are listed
+	results, err := suite.repos.Orders.GetOrders(context.Background())
+
+	// THEN an empty list should be returned
+	require.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), results)
+	assert.Empty(suite.T(), results)
+}
+
+func (suite *ConformanceSuite) Test_GetOrders_WithCancelledContext_ShouldReturnContextError() {
+	// GIVEN a cancelled request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// WHEN the orders are listed
+	_, err := suite.repos.Orders.GetOrders(ctx)
+
+	// THEN the cancellation should be returned
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+}
+
+// Feature: Storage conformance - Order status
+func (suite *ConformanceSuite) Test_GetOrderStatus_ShouldReturnLatestStatus() {
+	// GIVEN an order ready after being prepared
+	order := suite.addOrder(time.Now().Add(-time.Hour), entities.OrderStatusRecebido,
+		entities.OrderStatusEmPreparacao, entities.OrderStatusPronto)
+
+	// WHEN its status is read
+	status, err := suite.repos.OrderStatus.GetOrderStatus(context.Background(), order.ID)
+
+	// THEN it should be the latest one
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.OrderStatusPronto, status.CurrentStatus)
+	assert.Equal(suite.T(), order.ID, status.OrderId)
+}
+
+func (suite *ConformanceSuite) Test_GetOrderStatus_WithSkewedClocks_ShouldReturnLastAdded() {
+	// GIVEN an order received, then moved to preparation by a replica whose
+	// clock is five minutes behind
+	ctx := context.Background()
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	order := suite.addOrder(createdAt, entities.OrderStatusRecebido)
+	require.NoError(suite.T(), suite.repos.OrderStatus.ChangeOrderStatus(ctx, &entities.OrderStatusEntity{
+		OrderId: order.ID, CurrentStatus: entities.OrderStatusEmPreparacao, CreatedAt: createdAt.Add(-5 * time.Minute),
+	}, entities.OrderStatusRecebido))
+
+	// WHEN its status is read
+	status, err := suite.repos.OrderStatus.GetOrderStatus(ctx, order.ID)
+
+	// THEN it should be the one added last, whatever its creation time
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.OrderStatusEmPreparacao, status.CurrentStatus)
+	// AND the order should list it first, as its current status
+	result, err := suite.repos.Orders.GetOrder(ctx, order.ID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), result.Status, 2)
+	assert.Equal(suite.T(), entities.OrderStatusEmPreparacao, result.Status[0].CurrentStatus)
+	assert.Equal(suite.T(), entities.OrderStatusEmPreparacao, result.CurrentStatus)
+	// AND it should still move on from there
+	assert.NoError(suite.T(), suite.repos.OrderStatus.ChangeOrderStatus(ctx, &entities.OrderStatusEntity{
+		OrderId: order.ID, CurrentStatus: entities.OrderStatusPronto,
+	}, status.CurrentStatus))
+}
+
+func (suite *ConformanceSuite) Test_AddOrderStatus_ShouldUpdateCurrentStatusOfOrder() {
+	// GIVEN an order received, then in preparation a minute later
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	order := suite.addOrder(createdAt, entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao)
+
+	// WHEN it is read back
+	result, err := suite.repos.Orders.GetOrder(context.Background(), order.ID)
+
+	// THEN it should carry its latest status and when it was set
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.OrderStatusEmPreparacao, result.CurrentStatus)
+	require.NotNil(suite.T(), result.StatusUpdatedAt)
+	assert.True(suite.T(), createdAt.Add(time.Minute).Equal(*result.StatusUpdatedAt))
+}
+
+func (suite *ConformanceSuite) Test_ChangeOrderStatus_Concurrently_ShouldApplyOnlyOne() {
+	// GIVEN a received order
+	order := suite.addOrder(time.Now().Add(-time.Hour), entities.OrderStatusRecebido)
+
+	// WHEN several requests, having read it as received, move it at once to
+	// preparation or cancel it
+	const requests = 8
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := &entities.OrderStatusEntity{OrderId: order.ID, CurrentStatus: entities.OrderStatusEmPreparacao}
+			if i%2 == 1 {
+				status = &entities.OrderStatusEntity{OrderId: order.ID, CurrentStatus: entities.OrderStatusCancelado,
+					ReasonCode: entities.CancellationReasonCustomerRequest, ChangedBy: "kiosk-01"}
+			}
+			errs[i] = suite.repos.OrderStatus.ChangeOrderStatus(context.Background(), status, entities.OrderStatusRecebido)
+		}()
+	}
+	wg.Wait()
+
+	// THEN only one should be applied, the others told the status changed
+	applied := 0
+	for _, err := range errs {
+		if err == nil {
+			applied++
+			continue
+		}
+		assert.ErrorIs(suite.T(), err, domainerrors.ErrInvalidTransition)
+	}
+	assert.Equal(suite.T(), 1, applied)
+	// AND the history should only hold the initial status and the applied one
+	result, err := suite.repos.Orders.GetOrder(context.Background(), order.ID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), result.Status, 2)
+	assert.Equal(suite.T(), result.Status[0].CurrentStatus, result.CurrentStatus)
+}
+
+func (suite *ConformanceSuite) Test_ChangeOrderStatus_WithUnknownOrder_ShouldReturnOrderNotFound() {
+	// GIVEN no order
+	// WHEN the status of an unknown order is changed
+	err := suite.repos.OrderStatus.ChangeOrderStatus(context.Background(),
+		&entities.OrderStatusEntity{OrderId: 9999, CurrentStatus: entities.OrderStatusEmPreparacao}, entities.OrderStatusRecebido)
+
+	// THEN it should be reported as not found
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrOrderNotFound)
+}
+
+func (suite *ConformanceSuite) Test_GetOrderStatus_WithUnknownOrder_ShouldReturnOrderNotFound() {
+	// GIVEN no order
+	// WHEN the status of an unknown order is read
+	_, err := suite.repos.OrderStatus.GetOrderStatus(context.Background(), 9999)
+
+	// THEN it should be reported as not found
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrOrderNotFound)
+}
+
+func (suite *ConformanceSuite) Test_CountOrdersByStatus_ShouldCountCurrentActiveStatus() {
+	// GIVEN two orders received, one of them now in preparation, and a cancelled one
+	now := time.Now().Add(-time.Hour)
+	suite.addOrder(now, entities.OrderStatusRecebido)
+	suite.addOrder(now, entities.OrderStatusRecebido, entities.OrderStatusEmPreparacao)
+	suite.addOrder(now, entities.OrderStatusRecebido, entities.OrderStatusCancelado)
+
+	// WHEN the orders are counted by status
+	counts, err := suite.repos.OrderStatus.CountOrdersByStatus(context.Background())
+
+	// THEN only the current status of each active order should count
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[uint]int64{
+		entities.OrderStatusRecebido:     1,
+		entities.OrderStatusEmPreparacao: 1,
+	}, counts)
+}
+
+// Feature: Storage conformance - Idempotency keys
+func (suite *ConformanceSuite) Test_IdempotencyKey_ShouldConflictUntilExpired() {
+	// GIVEN a key valid for an hour
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	require.NoError(suite.T(), suite.repos.IdempotencyKeys.AddIdempotencyKey(ctx, &entities.IdempotencyKeyEntity{
+		Key: "key-1", RequestHash: "hash-1", OrderId: 1, CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+	}))
+
+	// WHEN it is read and reused within the hour
+	stored, err := suite.repos.IdempotencyKeys.GetIdempotencyKey(ctx, "key-1", now.Add(time.Minute))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "hash-1", stored.RequestHash)
+	reused := suite.repos.IdempotencyKeys.AddIdempotencyKey(ctx, &entities.IdempotencyKeyEntity{
+		Key: "key-1", RequestHash: "hash-2", OrderId: 2, CreatedAt: now.Add(time.Minute), ExpiresAt: now.Add(2 * time.Hour),
+	})
+
+	// THEN the reuse should conflict
+	assert.ErrorIs(suite.T(), reused, domainerrors.ErrIdempotencyKeyConflict)
+	// AND once expired, the key should be gone and free to take over
+	_, err = suite.repos.IdempotencyKeys.GetIdempotencyKey(ctx, "key-1", now.Add(2*time.Hour))
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrIdempotencyKeyNotFound)
+	require.NoError(suite.T(), suite.repos.IdempotencyKeys.AddIdempotencyKey(ctx, &entities.IdempotencyKeyEntity{
+		Key: "key-1", RequestHash: "hash-3", OrderId: 3, CreatedAt: now.Add(2 * time.Hour), ExpiresAt: now.Add(3 * time.Hour),
+	}))
+	stored, err = suite.repos.IdempotencyKeys.GetIdempotencyKey(ctx, "key-1", now.Add(2*time.Hour))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(3), stored.OrderId)
+}
+
+// Feature: Storage conformance - Unit of work
+func (suite *ConformanceSuite) Test_UnitOfWork_ShouldCommitEveryWrite() {
+	// GIVEN an order written through a unit of work
+	var orderId uint
+	err := suite.unitOfWork.Do(context.Background(), func(repos *repositories.Repositories) error {
+		order, err := repos.Orders.AddOrder(context.Background(), &entities.OrderEntity{TotalAmount: money.MustParse("10.00")})
+		if err != nil {
+			return err
+		}
+		orderId = order.ID
+		return repos.OrderStatus.AddOrderStatus(context.Background(), &entities.OrderStatusEntity{
+			OrderId: order.ID, CurrentStatus: entities.OrderStatusRecebido,
+		})
+	})
+	require.NoError(suite.T(), err)
+
+	// WHEN it is read outside the unit of work
+	result, err := suite.repos.Orders.GetOrder(context.Background(), orderId)
+
+	// THEN every write should be visible
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), result.Status, 1)
+}
+
+func (suite *ConformanceSuite) Test_UnitOfWork_WithError_ShouldRollBackEveryWrite() {
+	// GIVEN a unit of work failing after writing an order
+	failure := errors.New("product service unavailable")
+	var orderId uint
+	err := suite.unitOfWork.Do(context.Background(), func(repos *repositories.Repositories) error {
+		order, err := repos.Orders.AddOrder(context.Background(), &entities.OrderEntity{TotalAmount: money.MustParse("10.00")})
+		if err != nil {
+			return err
+		}
+		orderId = order.ID
+		return failure
+	})
+
+	// WHEN the order is read
+	_, getErr := suite.repos.Orders.GetOrder(context.Background(), orderId)
+
+	// THEN the failure should be returned and the order never stored
+	assert.ErrorIs(suite.T(), err, failure)
+	assert.ErrorIs(suite.T(), getErr, domainerrors.ErrOrderNotFound)
+}
+
+func (suite *ConformanceSuite) Test_UnitOfWork_WithError_ShouldKeepCurrentStatus() {
+	// GIVEN a received order
+	order := suite.addOrder(time.Now().Add(-time.Hour), entities.OrderStatusRecebido)
+
+	// WHEN a unit of work fails after moving it to preparation
+	failure := errors.New("notification failed")
+	err := suite.unitOfWork.Do(context.Background(), func(repos *repositories.Repositories) error {
+		if err := repos.OrderStatus.AddOrderStatus(context.Background(), &entities.OrderStatusEntity{
+			OrderId: order.ID, CurrentStatus: entities.OrderStatusEmPreparacao,
+		}); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	// THEN the order should still be received
+	assert.ErrorIs(suite.T(), err, failure)
+	result, err := suite.repos.Orders.GetOrder(context.Background(), order.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.OrderStatusRecebido, result.CurrentStatus)
+	status, err := suite.repos.OrderStatus.GetOrderStatus(context.Background(), order.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.OrderStatusRecebido, status.CurrentStatus)
+}
+
+func (suite *ConformanceSuite) Test_UnitOfWork_WithError_ShouldRestoreOverwrittenData() {
+	// GIVEN an order with a product and an expired idempotency key
+	ctx := context.Background()
+	now := time.Now()
+	order := suite.addOrder(now.Add(-time.Hour), entities.OrderStatusRecebido)
+	require.NoError(suite.T(), suite.repos.IdempotencyKeys.AddIdempotencyKey(ctx, &entities.IdempotencyKeyEntity{
+		Key: "key-1", OrderId: order.ID, CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour),
+	}))
+
+	// WHEN a unit of work fails after adding a product and taking over the key
+	failure := errors.New("customer service unavailable")
+	err := suite.unitOfWork.Do(ctx, func(repos *repositories.Repositories) error {
+		if err := repos.OrderProducts.AddOrderProduct(ctx, &entities.OrderProductEntity{
+			OrderId: order.ID, ProductId: 20, Price: money.MustParse("5.00"), Quantity: 1,
+		}); err != nil {
+			return err
+		}
+		if err := repos.IdempotencyKeys.AddIdempotencyKey(ctx, &entities.IdempotencyKeyEntity{
+			Key: "key-1", OrderId: order.ID + 1, CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+		}); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	// THEN the order should keep its single product
+	assert.ErrorIs(suite.T(), err, failure)
+	result, err := suite.repos.Orders.GetOrder(ctx, order.ID)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), result.Products, 1)
+	// AND the key should still be the expired one
+	_, err = suite.repos.IdempotencyKeys.GetIdempotencyKey(ctx, "key-1", now)
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrIdempotencyKeyNotFound)
+}
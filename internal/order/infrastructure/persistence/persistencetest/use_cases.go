@@ -0,0 +1,127 @@
+package persistencetest
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+	addorder "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/addOrder"
+	cancelorder "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/cancelOrder"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	updateorderstatus "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/updateOrderStatus"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+	mockClients "github.com/viniciuscluna/tc-fiap-50/mocks/infrastructure/clients"
+)
+
+// nopRecorder ignores the order events of the use cases.
+type nopRecorder struct{}
+
+func (nopRecorder) OrderCreated()                    {}
+func (nopRecorder) StatusChanged(from uint, to uint) {}
+
+// Feature: Storage conformance - Use cases
+// Scenario: The order use cases work the same on every backend
+func (suite *ConformanceSuite) Test_AddOrderUseCase_ShouldStoreOrderAndReplayItsIdempotencyKey() {
+	// GIVEN a known customer and products
+	customerClient := mockClients.NewMockCustomerClient(suite.T())
+	customerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(1)).
+		Return(&clients.CustomerDTO{ID: 1}, nil).
+		Once()
+	productClient := mockClients.NewMockProductClient(suite.T())
+	productClient.EXPECT().
+		GetProducts(mock.Anything, []uint{10, 11}).
+		Return([]*clients.ProductDTO{
+			{ID: 10, Price: money.MustParse("12.95")},
+			{ID: 11, Price: money.MustParse("5.00")},
+		}, nil).
+		Once()
+	useCase := addorder.NewAddOrderUseCaseImpl(suite.unitOfWork, suite.repos.IdempotencyKeys, customerClient, productClient,
+		addorder.Settings{IdempotencyKeyTTL: time.Hour}, nopRecorder{})
+
+	customerId := uint(1)
+	command := &commands.AddOrderCommand{
+		CustomerId: &customerId,
+		Products: []*dto.AddOrderProductDto{
+			{ProductId: 10, Quantity: 2},
+			{ProductId: 11, Quantity: 1},
+		},
+		IdempotencyKey: "key-1",
+		RequestHash:    "hash-1",
+	}
+
+	// WHEN the order is added twice with the same idempotency key
+	orderId, err := useCase.Execute(context.Background(), command)
+	require.NoError(suite.T(), err)
+	replayedOrderId, err := useCase.Execute(context.Background(), command)
+
+	// THEN the second request should replay the first order
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), orderId, replayedOrderId)
+	orders, err := suite.repos.Orders.GetOrders(context.Background())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), orders, 1)
+	// AND the order should be stored received, with its products and total
+	assert.Equal(suite.T(), money.MustParse("30.90"), orders[0].TotalAmount)
+	assert.Len(suite.T(), orders[0].Products, 2)
+	assert.Equal(suite.T(), entities.OrderStatusRecebido, orders[0].CurrentStatus)
+	assert.Len(suite.T(), orders[0].Status, 1)
+}
+
+func (suite *ConformanceSuite) Test_UpdateOrderStatusUseCase_ShouldFollowTheOrderLifecycle() {
+	// GIVEN a received order
+	order := suite.addOrder(time.Now().Add(-time.Hour), entities.OrderStatusRecebido)
+	useCase := updateorderstatus.NewUpdateOrderStatusUseCaseImpl(suite.repos.OrderStatus, nopRecorder{})
+
+	// WHEN it is prepared and then made ready
+	require.NoError(suite.T(), useCase.Execute(context.Background(), commands.NewUpdateOrderStatusCommand(order.ID, entities.OrderStatusEmPreparacao)))
+	require.NoError(suite.T(), useCase.Execute(context.Background(), commands.NewUpdateOrderStatusCommand(order.ID, entities.OrderStatusPronto)))
+	// AND it is moved back to received
+	err := useCase.Execute(context.Background(), commands.NewUpdateOrderStatusCommand(order.ID, entities.OrderStatusRecebido))
+
+	// THEN going back should be rejected
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrInvalidTransition)
+	// AND the order should be ready, with every status it went through
+	result, err := suite.repos.Orders.GetOrder(context.Background(), order.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.OrderStatusPronto, result.CurrentStatus)
+	require.Len(suite.T(), result.Status, 3)
+	assert.Equal(suite.T(), entities.OrderStatusPronto, result.Status[0].CurrentStatus)
+}
+
+func (suite *ConformanceSuite) Test_CancelOrderUseCase_ShouldRemoveOrderFromActiveOrders() {
+	// GIVEN two received orders
+	now := time.Now()
+	cancelled := suite.addOrder(now.Add(-2*time.Hour), entities.OrderStatusRecebido)
+	kept := suite.addOrder(now.Add(-time.Hour), entities.OrderStatusRecebido)
+	useCase := cancelorder.NewCancelOrderUseCaseImpl(suite.repos.OrderStatus, nopRecorder{})
+
+	// WHEN one of them is cancelled, then cancelled again
+	err := useCase.Execute(context.Background(), commands.NewCancelOrderCommand(cancelled.ID, entities.CancellationReasonCustomerRequest, "attendant-1"))
+	require.NoError(suite.T(), err)
+	err = useCase.Execute(context.Background(), commands.NewCancelOrderCommand(cancelled.ID, entities.CancellationReasonOther, "attendant-2"))
+
+	// THEN the second cancellation should be rejected
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrInvalidTransition)
+	// AND the first one should be recorded with its reason and author
+	status, err := suite.repos.OrderStatus.GetOrderStatus(context.Background(), cancelled.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.OrderStatusCancelado, status.CurrentStatus)
+	assert.Equal(suite.T(), entities.CancellationReasonCustomerRequest, status.ReasonCode)
+	assert.Equal(suite.T(), "attendant-1", status.ChangedBy)
+	// AND only the other order should still be active
+	orders, err := suite.repos.Orders.GetOrders(context.Background())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), orders, 1)
+	assert.Equal(suite.T(), kept.ID, orders[0].ID)
+	counts, err := suite.repos.OrderStatus.CountOrdersByStatus(context.Background())
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[uint]int64{entities.OrderStatusRecebido: 1}, counts)
+}
@@ -0,0 +1,31 @@
+package secondary
+
+import (
+	"context"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	_ repositories.UnitOfWork = (*UnitOfWorkImpl)(nil)
+)
+
+type UnitOfWorkImpl struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWorkImpl(db *gorm.DB) *UnitOfWorkImpl {
+	return &UnitOfWorkImpl{db: db}
+}
+
+func (u *UnitOfWorkImpl) Do(ctx context.Context, fn func(repos *repositories.Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repositories.Repositories{
+			Orders:          NewOrderRepositoryImpl(tx),
+			OrderProducts:   NewOrderProductRepositoryImpl(tx),
+			OrderStatus:     NewOrderStatusRepositoryImpl(tx),
+			IdempotencyKeys: NewIdempotencyKeyRepositoryImpl(tx),
+		})
+	})
+}
@@ -0,0 +1,197 @@
+package secondary_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	secondary "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/persistence"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type UnitOfWorkTestSuite struct {
+	suite.Suite
+	db         *gorm.DB
+	unitOfWork *secondary.UnitOfWorkImpl
+}
+
+func (suite *UnitOfWorkTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(suite.T(), err)
+
+	// A single connection keeps the in-memory database shared between the
+	// transaction and the assertions
+	sqlDB, err := db.DB()
+	assert.NoError(suite.T(), err)
+	sqlDB.SetMaxOpenConns(1)
+
+	err = db.AutoMigrate(&entities.OrderEntity{}, &entities.OrderProductEntity{}, &entities.OrderStatusEntity{})
+	assert.NoError(suite.T(), err)
+
+	suite.db = db
+	suite.unitOfWork = secondary.NewUnitOfWorkImpl(db)
+}
+
+func (suite *UnitOfWorkTestSuite) TearDownTest() {
+	sqlDB, err := suite.db.DB()
+	if err == nil {
+		sqlDB.Close()
+	}
+}
+
+func TestUnitOfWorkTestSuite(t *testing.T) {
+	suite.Run(t, new(UnitOfWorkTestSuite))
+}
+
+// createOrder writes an order, one product and the initial status through the unit of work repositories
+func createOrder(repos *repositories.Repositories) error {
+	order, err := repos.Orders.AddOrder(context.Background(), &entities.OrderEntity{CustomerId: uintPtr(1), TotalAmount: money.MustParse("20.00")})
+	if err != nil {
+		return err
+	}
+	if err := repos.OrderProducts.AddOrderProduct(context.Background(), &entities.OrderProductEntity{
+		OrderId: order.ID, ProductId: 10, Price: money.MustParse("10.00"), Quantity: 2,
+	}); err != nil {
+		return err
+	}
+	return repos.OrderStatus.AddOrderStatus(context.Background(), &entities.OrderStatusEntity{
+		OrderId: order.ID, CurrentStatus: entities.OrderStatusRecebido,
+	})
+}
+
+func (suite *UnitOfWorkTestSuite) assertRowCounts(orders, products, statuses int64) {
+	var count int64
+	suite.db.Model(&entities.OrderEntity{}).Count(&count)
+	assert.Equal(suite.T(), orders, count, "order rows")
+	suite.db.Model(&entities.OrderProductEntity{}).Count(&count)
+	assert.Equal(suite.T(), products, count, "order_product rows")
+	suite.db.Model(&entities.OrderStatusEntity{}).Count(&count)
+	assert.Equal(suite.T(), statuses, count, "order_status rows")
+}
+
+// Feature: Unit of Work
+// Scenario: Commit all writes when the function succeeds
+
+func (suite *UnitOfWorkTestSuite) Test_Do_WithSuccessfulWrites_ShouldCommitAll() {
+	// WHEN an order is written through the unit of work
+	err := suite.unitOfWork.Do(context.Background(), createOrder)
+
+	// THEN the operation should complete without errors
+	assert.NoError(suite.T(), err)
+	// AND every row should be persisted
+	suite.assertRowCounts(1, 1, 1)
+}
+
+// Scenario: Roll back every write when the function fails mid-way
+
+func (suite *UnitOfWorkTestSuite) Test_Do_WithErrorAfterPartialWrites_ShouldRollbackAll() {
+	// GIVEN a function that fails after writing the order and its product
+	expectedError := errors.New("business rule failed")
+
+	// WHEN it runs through the unit of work
+	err := suite.unitOfWork.Do(context.Background(), func(repos *repositories.Repositories) error {
+		order, err := repos.Orders.AddOrder(context.Background(), &entities.OrderEntity{CustomerId: uintPtr(1)})
+		if err != nil {
+			return err
+		}
+		if err := repos.OrderProducts.AddOrderProduct(context.Background(), &entities.OrderProductEntity{
+			OrderId: order.ID, ProductId: 10, Price: money.MustParse("10.00"), Quantity: 1,
+		}); err != nil {
+			return err
+		}
+		return expectedError
+	})
+
+	// THEN the function error should be returned
+	assert.ErrorIs(suite.T(), err, expectedError)
+	// AND no orphan rows should remain
+	suite.assertRowCounts(0, 0, 0)
+}
+
+func (suite *UnitOfWorkTestSuite) Test_Do_WithDatabaseFailureOnStatusInsert_ShouldRollbackOrderAndProducts() {
+	// GIVEN the database fails when inserting the order status
+	injectedError := errors.New("injected order_status failure")
+	err := suite.db.Callback().Create().Before("gorm:create").Register("test:fail_order_status", func(db *gorm.DB) {
+		if db.Statement.Table == (entities.OrderStatusEntity{}).TableName() {
+			db.AddError(injectedError)
+		}
+	})
+	assert.NoError(suite.T(), err)
+
+	// WHEN an order is written through the unit of work
+	err = suite.unitOfWork.Do(context.Background(), createOrder)
+
+	// THEN the database error should be returned
+	assert.ErrorIs(suite.T(), err, injectedError)
+	// AND the order and its products should be rolled back
+	suite.assertRowCounts(0, 0, 0)
+}
+
+func (suite *UnitOfWorkTestSuite) Test_Do_WithDatabaseFailureOnProductInsert_ShouldRollbackOrder() {
+	// GIVEN the database fails when inserting an order product
+	injectedError := errors.New("injected order_product failure")
+	err := suite.db.Callback().Create().Before("gorm:create").Register("test:fail_order_product", func(db *gorm.DB) {
+		if db.Statement.Table == (entities.OrderProductEntity{}).TableName() {
+			db.AddError(injectedError)
+		}
+	})
+	assert.NoError(suite.T(), err)
+
+	// WHEN an order is written through the unit of work
+	err = suite.unitOfWork.Do(context.Background(), createOrder)
+
+	// THEN the database error should be returned
+	assert.ErrorIs(suite.T(), err, injectedError)
+	// AND the order should be rolled back
+	suite.assertRowCounts(0, 0, 0)
+}
+
+func (suite *UnitOfWorkTestSuite) Test_Do_WithPanic_ShouldRollbackAndRepanic() {
+	// GIVEN a function that panics after writing the order
+	// WHEN it runs through the unit of work
+	assert.Panics(suite.T(), func() {
+		_ = suite.unitOfWork.Do(context.Background(), func(repos *repositories.Repositories) error {
+			if _, err := repos.Orders.AddOrder(context.Background(), &entities.OrderEntity{CustomerId: uintPtr(1)}); err != nil {
+				return err
+			}
+			panic("unexpected failure")
+		})
+	})
+
+	// THEN the order should be rolled back
+	suite.assertRowCounts(0, 0, 0)
+}
+
+func (suite *UnitOfWorkTestSuite) Test_Do_AfterFailedUnit_ShouldCommitNextUnit() {
+	// GIVEN a failed unit of work
+	_ = suite.unitOfWork.Do(context.Background(), func(repos *repositories.Repositories) error {
+		return errors.New("failed")
+	})
+
+	// WHEN a second unit of work succeeds
+	err := suite.unitOfWork.Do(context.Background(), createOrder)
+
+	// THEN only the second unit should be persisted
+	assert.NoError(suite.T(), err)
+	suite.assertRowCounts(1, 1, 1)
+}
+
+func (suite *UnitOfWorkTestSuite) Test_Do_WithCancelledContext_ShouldNotPersist() {
+	// GIVEN a request context that has already been cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// WHEN an order is written through the unit of work
+	err := suite.unitOfWork.Do(ctx, createOrder)
+
+	// THEN the cancellation should be returned
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+	// AND nothing should be persisted
+	suite.assertRowCounts(0, 0, 0)
+}
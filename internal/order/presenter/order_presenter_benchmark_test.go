@@ -0,0 +1,123 @@
+package presenter_test
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/presenter"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+)
+
+// countingHTTPClient answers every GET with an entity whose id is the last
+// path segment of the URL and counts the calls made to it.
+type countingHTTPClient struct {
+	calls atomic.Int64
+}
+
+func (c *countingHTTPClient) Get(ctx context.Context, url string, response interface{}) error {
+	c.calls.Add(1)
+
+	id, err := strconv.ParseUint(path.Base(url), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	switch r := response.(type) {
+	case *clients.CustomerDTO:
+		r.ID = uint(id)
+		r.Name = "Customer " + path.Base(url)
+	case *clients.ProductDTO:
+		r.ID = uint(id)
+		r.Name = "Product " + path.Base(url)
+	}
+	return nil
+}
+
+func (c *countingHTTPClient) Post(ctx context.Context, url string, body, response interface{}) error {
+	c.calls.Add(1)
+	return nil
+}
+
+// kitchenOrders builds a kitchen list of 50 orders placed by 10 customers,
+// each with 3 items picked from a menu of 8 products.
+func kitchenOrders() []*entities.OrderEntity {
+	orders := make([]*entities.OrderEntity, 50)
+	for i := range orders {
+		customerId := uint(i%10 + 1)
+		orders[i] = &entities.OrderEntity{
+			ID:          uint(i + 1),
+			CustomerId:  &customerId,
+			TotalAmount: money.MustParse("30.00"),
+		}
+		for j := 0; j < 3; j++ {
+			orders[i].Products = append(orders[i].Products, &entities.OrderProductEntity{
+				ProductId: uint((i+j)%8 + 1),
+				Price:     money.MustParse("10.00"),
+				Quantity:  1,
+			})
+		}
+	}
+	return orders
+}
+
+// BenchmarkPresentOrders compares presenting the kitchen list order by order
+// with presenting it as a whole. The downstream-calls/op metric is 200 (one
+// customer and three product calls per order) for per_order and 18 (one call
+// per distinct customer and product) for shared_lookups.
+func BenchmarkPresentOrders(b *testing.B) {
+	orders := kitchenOrders()
+
+	b.Run("per_order", func(b *testing.B) {
+		httpClient := &countingHTTPClient{}
+		orderPresenter := newBenchmarkPresenter(httpClient)
+
+		for i := 0; i < b.N; i++ {
+			for _, order := range orders {
+				orderPresenter.Present(context.Background(), order)
+			}
+		}
+
+		b.ReportMetric(float64(httpClient.calls.Load())/float64(b.N), "downstream-calls/op")
+	})
+
+	b.Run("shared_lookups", func(b *testing.B) {
+		httpClient := &countingHTTPClient{}
+		orderPresenter := newBenchmarkPresenter(httpClient)
+
+		for i := 0; i < b.N; i++ {
+			orderPresenter.PresentOrders(context.Background(), orders)
+		}
+
+		b.ReportMetric(float64(httpClient.calls.Load())/float64(b.N), "downstream-calls/op")
+	})
+}
+
+func newBenchmarkPresenter(httpClient *countingHTTPClient) presenter.OrderPresenter {
+	return presenter.NewOrderPresenterImpl(
+		clients.NewCustomerClientImpl(httpClient, "http://customer"),
+		clients.NewProductClientImpl(httpClient, "http://product", "", 8),
+	)
+}
+
+func TestPresentOrders_ShouldCallDownstreamOncePerDistinctId(t *testing.T) {
+	// GIVEN a kitchen list with 10 distinct customers and 8 distinct products
+	httpClient := &countingHTTPClient{}
+	orderPresenter := newBenchmarkPresenter(httpClient)
+
+	// WHEN the list is presented
+	result := orderPresenter.PresentOrders(context.Background(), kitchenOrders())
+
+	// THEN each distinct customer and product should be fetched exactly once
+	assert.Equal(t, int64(18), httpClient.calls.Load())
+	// AND every order should be enriched
+	for _, order := range result.Orders {
+		assert.NotNil(t, order.Customer)
+		assert.NotEmpty(t, order.Products[0].Name)
+	}
+}
@@ -3,12 +3,20 @@ package presenter
 import (
 	"context"
 	"errors"
-	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/httpclient"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/logging"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -27,44 +35,33 @@ func NewOrderPresenterImpl(customerClient clients.CustomerClient, productClient
 	}
 }
 
-func (p *OrderPresenterImpl) Present(order *entities.OrderEntity) *dto.GetOrderResponseDto {
-	ctx := context.Background()
+// maxConcurrentLookups bounds how many customer lookups run at once while
+// enriching a list of orders.
+const maxConcurrentLookups = 8
 
-	var customer *dto.CustomerDto
-	if order.CustomerId != 0 {
-		// Fetch customer data from customer service
-		customerData, err := p.customerClient.GetCustomer(ctx, order.CustomerId)
-		if err != nil {
-			// Log error but don't fail - graceful degradation
-			log.Printf("failed to fetch customer %d: %v", order.CustomerId, err)
-		} else {
-			customer = &dto.CustomerDto{
-				ID:    customerData.ID,
-				Name:  customerData.Name,
-				Email: customerData.Email,
-				CPF:   customerData.CPF,
-			}
-		}
-	}
-
-	response := &dto.GetOrderResponseDto{
-		ID:          order.ID,
-		CreatedAt:   order.CreatedAt,
-		TotalAmount: order.TotalAmount,
-		CustomerId:  order.CustomerId,
-		Customer:    customer,
-		Products:    p.PresentProducts(order.Products),
-		Status:      p.PresentMultipleStatus(order.Status),
-	}
+// lookups holds the downstream data shared by every order being presented.
+type lookups struct {
+	mu        sync.Mutex
+	customers map[uint]*dto.CustomerDto
+	products  map[uint]*clients.ProductDTO
+}
 
-	return response
+func (p *OrderPresenterImpl) Present(ctx context.Context, order *entities.OrderEntity) *dto.GetOrderResponseDto {
+	orders := []*entities.OrderEntity{order}
+	return p.presentOrder(order, p.fetchLookups(ctx, customerIDs(orders), productIDs(order.Products)))
 }
 
-func (p *OrderPresenterImpl) PresentOrders(orders []*entities.OrderEntity) *dto.GetOrdersResponseDto {
+func (p *OrderPresenterImpl) PresentOrders(ctx context.Context, orders []*entities.OrderEntity) *dto.GetOrdersResponseDto {
 	orderDto := make([]*dto.GetOrderResponseDto, len(orders))
 
+	// Fetch each distinct customer and product once for the whole list
+	var orderProducts []*entities.OrderProductEntity
+	for _, order := range orders {
+		orderProducts = append(orderProducts, order.Products...)
+	}
+	found := p.fetchLookups(ctx, customerIDs(orders), productIDs(orderProducts))
 	for i, order := range orders {
-		orderDto[i] = p.Present(order)
+		orderDto[i] = p.presentOrder(order, found)
 	}
 
 	return &dto.GetOrdersResponseDto{
@@ -72,63 +69,163 @@ func (p *OrderPresenterImpl) PresentOrders(orders []*entities.OrderEntity) *dto.
 	}
 }
 
-func (p *OrderPresenterImpl) PresentProducts(orderProducts []*entities.OrderProductEntity) []*dto.OrderProductDto {
-	ctx := context.Background()
-	orderProductDtoArr := make([]*dto.OrderProductDto, len(orderProducts))
+func (p *OrderPresenterImpl) PresentProducts(ctx context.Context, orderProducts []*entities.OrderProductEntity) []*dto.OrderProductDto {
+	return presentProducts(orderProducts, p.fetchLookups(ctx, nil, productIDs(orderProducts)))
+}
 
-	// Collect all product IDs
-	productIDs := make([]uint, len(orderProducts))
-	for i, orderProduct := range orderProducts {
-		productIDs[i] = orderProduct.ProductId
+func (p *OrderPresenterImpl) presentOrder(order *entities.OrderEntity, found *lookups) *dto.GetOrderResponseDto {
+	var customer *dto.CustomerDto
+	if order.CustomerId != nil {
+		// Guest orders have no customer; a failed lookup leaves it empty
+		customer = found.customers[*order.CustomerId]
 	}
 
-	// Fetch all products in batch from product service
-	products, err := p.productClient.GetProducts(ctx, productIDs)
-	if err != nil {
-		log.Printf("failed to fetch products: %v", err)
-		// Return products without enriched data
-		for i, orderProduct := range orderProducts {
-			orderProductDtoArr[i] = &dto.OrderProductDto{
-				ProductId: orderProduct.ProductId,
-				Price:     orderProduct.Price,
-				Quantity:  orderProduct.Quantity,
+	return &dto.GetOrderResponseDto{
+		ID:                            order.ID,
+		CreatedAt:                     order.CreatedAt,
+		TotalAmount:                   order.TotalAmount,
+		CustomerId:                    order.CustomerId,
+		GuestName:                     order.GuestName,
+		Customer:                      customer,
+		CustomerReconciliationPending: order.CustomerReconciliationPending,
+		Products:                      presentProducts(order.Products, found),
+		Status:                        p.PresentMultipleStatus(order.Status),
+	}
+}
+
+// fetchLookups fetches the given customers, with at most maxConcurrentLookups
+// requests in flight, while the products are fetched in a single batch call.
+// Failures are logged and leave the entries out (graceful degradation).
+func (p *OrderPresenterImpl) fetchLookups(ctx context.Context, customerIDs []uint, productIDs []uint) *lookups {
+	ctx, span := tracing.Start(ctx, "OrderPresenter.fetchLookups", trace.WithAttributes(
+		attribute.Int("enrichment.customers.requested", len(customerIDs)),
+		attribute.Int("enrichment.products.requested", len(productIDs)),
+	))
+	defer span.End()
+
+	found := &lookups{
+		customers: make(map[uint]*dto.CustomerDto, len(customerIDs)),
+		products:  make(map[uint]*clients.ProductDTO, len(productIDs)),
+	}
+
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentLookups)
+
+	if len(productIDs) > 0 {
+		g.Go(func() error {
+			// Partial results still enrich the products that were fetched
+			products, err := p.productClient.GetProducts(ctx, productIDs)
+			if err != nil {
+				logging.FromContext(ctx).Warn("failed to fetch products", zap.Uints("product_ids", productIDs), zap.Error(err))
 			}
-		}
-		return orderProductDtoArr
+
+			found.mu.Lock()
+			defer found.mu.Unlock()
+			for _, product := range products {
+				found.products[product.ID] = product
+			}
+			return nil
+		})
 	}
 
-	// Create a map for quick lookup
-	productMap := make(map[uint]*clients.ProductDTO)
-	for _, product := range products {
-		productMap[product.ID] = product
+	// Once the customer service circuit is open the remaining lookups are
+	// skipped, and the outage is logged once instead of once per customer
+	var customersDown atomic.Bool
+	for _, customerID := range customerIDs {
+		g.Go(func() error {
+			if customersDown.Load() {
+				return nil
+			}
+
+			customerData, err := p.customerClient.GetCustomer(ctx, customerID)
+			if errors.Is(err, httpclient.ErrCircuitOpen) {
+				if !customersDown.Swap(true) {
+					logging.FromContext(ctx).Warn("skipping customer enrichment", zap.Error(err))
+				}
+				return nil
+			}
+			if err != nil {
+				logging.FromContext(ctx).Warn("failed to fetch customer", zap.Uint("customer_id", customerID), zap.Error(err))
+				return nil
+			}
+
+			found.mu.Lock()
+			defer found.mu.Unlock()
+			found.customers[customerID] = &dto.CustomerDto{
+				ID:    customerData.ID,
+				Name:  customerData.Name,
+				Email: customerData.Email,
+				CPF:   customerData.CPF,
+			}
+			return nil
+		})
 	}
 
+	// Lookups never fail the presentation, so there is no error to report
+	_ = g.Wait()
+	span.SetAttributes(
+		attribute.Int("enrichment.customers.found", len(found.customers)),
+		attribute.Int("enrichment.products.found", len(found.products)),
+		attribute.Bool("enrichment.customers.skipped", customersDown.Load()),
+	)
+
+	return found
+}
+
+func presentProducts(orderProducts []*entities.OrderProductEntity, found *lookups) []*dto.OrderProductDto {
+	orderProductDtoArr := make([]*dto.OrderProductDto, len(orderProducts))
+
 	// Enrich order products with product data
 	for i, orderProduct := range orderProducts {
-		product, exists := productMap[orderProduct.ProductId]
-		if exists {
-			orderProductDtoArr[i] = &dto.OrderProductDto{
-				ProductId:   orderProduct.ProductId,
-				Price:       orderProduct.Price,
-				Quantity:    orderProduct.Quantity,
-				Name:        product.Name,
-				ImageLink:   product.ImageLink,
-				Description: product.Description,
-				Category:    product.Category,
-			}
-		} else {
-			// Product not found, return without enriched data
-			orderProductDtoArr[i] = &dto.OrderProductDto{
-				ProductId: orderProduct.ProductId,
-				Price:     orderProduct.Price,
-				Quantity:  orderProduct.Quantity,
-			}
+		orderProductDtoArr[i] = &dto.OrderProductDto{
+			ProductId: orderProduct.ProductId,
+			Price:     orderProduct.Price,
+			Quantity:  orderProduct.Quantity,
+		}
+
+		// Product not found, return without enriched data
+		product, exists := found.products[orderProduct.ProductId]
+		if !exists {
+			continue
 		}
+
+		orderProductDtoArr[i].Name = product.Name
+		orderProductDtoArr[i].ImageLink = product.ImageLink
+		orderProductDtoArr[i].Description = product.Description
+		orderProductDtoArr[i].Category = product.Category
 	}
 
 	return orderProductDtoArr
 }
 
+// customerIDs returns the distinct customer ids of the orders, skipping guest orders.
+func customerIDs(orders []*entities.OrderEntity) []uint {
+	seen := make(map[uint]bool)
+	ids := make([]uint, 0, len(orders))
+	for _, order := range orders {
+		if order.CustomerId == nil || seen[*order.CustomerId] {
+			continue
+		}
+		seen[*order.CustomerId] = true
+		ids = append(ids, *order.CustomerId)
+	}
+	return ids
+}
+
+// productIDs returns the distinct product ids in first-seen order.
+func productIDs(orderProducts []*entities.OrderProductEntity) []uint {
+	seen := make(map[uint]bool)
+	ids := make([]uint, 0, len(orderProducts))
+	for _, orderProduct := range orderProducts {
+		if seen[orderProduct.ProductId] {
+			continue
+		}
+		seen[orderProduct.ProductId] = true
+		ids = append(ids, orderProduct.ProductId)
+	}
+	return ids
+}
+
 func (p *OrderPresenterImpl) PresentStatus(orderStatus *entities.OrderStatusEntity) *dto.GetOrderStatusResponseDto {
 	statusDescription, err := GetStatusDescription(orderStatus.CurrentStatus)
 	if err != nil {
@@ -141,6 +238,8 @@ func (p *OrderPresenterImpl) PresentStatus(orderStatus *entities.OrderStatusEnti
 		CurrentStatus:            orderStatus.CurrentStatus,
 		CurrentStatusDescription: statusDescription,
 		OrderId:                  orderStatus.OrderId,
+		ReasonCode:               orderStatus.ReasonCode,
+		ChangedBy:                orderStatus.ChangedBy,
 	}
 }
 
@@ -159,16 +258,19 @@ func (p *OrderPresenterImpl) PresentMultipleStatus(orderStatus []*entities.Order
 // 2 - Em preparação
 // 3 - Pronto
 // 4 - Finalizado
+// 5 - Cancelado
 func GetStatusDescription(status uint) (string, error) {
 	switch status {
-	case 1:
+	case entities.OrderStatusRecebido:
 		return "Recebido", nil
-	case 2:
+	case entities.OrderStatusEmPreparacao:
 		return "Em preparação", nil
-	case 3:
+	case entities.OrderStatusPronto:
 		return "Pronto", nil
-	case 4:
+	case entities.OrderStatusFinalizado:
 		return "Finalizado", nil
+	case entities.OrderStatusCancelado:
+		return "Cancelado", nil
 	default:
 		return "", errors.New("status not found")
 	}
@@ -1,6 +1,8 @@
 package presenter_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -11,7 +13,11 @@ import (
 	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/presenter"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/httpclient"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing/tracingtest"
 	mockClients "github.com/viniciuscluna/tc-fiap-50/mocks/infrastructure/clients"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type OrderPresenterTestSuite struct {
@@ -31,6 +37,10 @@ func TestOrderPresenterTestSuite(t *testing.T) {
 	suite.Run(t, new(OrderPresenterTestSuite))
 }
 
+func uintPtr(v uint) *uint {
+	return &v
+}
+
 // Feature: Order Presenter - Present Order
 // Scenario: Transform order entity to DTO with enriched data
 
@@ -39,11 +49,11 @@ func (suite *OrderPresenterTestSuite) Test_Present_WithValidOrderAndCustomer_Sho
 	now := time.Now()
 	order := &entities.OrderEntity{
 		ID:          123,
-		CustomerId:  1,
-		TotalAmount: 100.00,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("100.00"),
 		CreatedAt:   now,
 		Products: []*entities.OrderProductEntity{
-			{ProductId: 10, Price: 50.00, Quantity: 2},
+			{ProductId: 10, Price: money.MustParse("50.00"), Quantity: 2},
 		},
 		Status: []*entities.OrderStatusEntity{
 			{ID: 1, CurrentStatus: 1, OrderId: 123, CreatedAt: now},
@@ -58,7 +68,7 @@ func (suite *OrderPresenterTestSuite) Test_Present_WithValidOrderAndCustomer_Sho
 	}
 
 	products := []*clients.ProductDTO{
-		{ID: 10, Name: "Product A", Price: 50.00, Category: 1},
+		{ID: 10, Name: "Product A", Price: money.MustParse("50.00"), Category: 1},
 	}
 
 	suite.mockCustomerClient.EXPECT().
@@ -72,7 +82,7 @@ func (suite *OrderPresenterTestSuite) Test_Present_WithValidOrderAndCustomer_Sho
 		Once()
 
 	// WHEN the order is presented
-	result := suite.presenter.Present(order)
+	result := suite.presenter.Present(context.Background(), order)
 
 	// THEN the DTO should not be nil
 	assert.NotNil(suite.T(), result)
@@ -94,8 +104,8 @@ func (suite *OrderPresenterTestSuite) Test_Present_WithCustomerServiceFailure_Sh
 	// GIVEN an order with customer ID
 	order := &entities.OrderEntity{
 		ID:          456,
-		CustomerId:  5,
-		TotalAmount: 75.00,
+		CustomerId:  uintPtr(5),
+		TotalAmount: money.MustParse("75.00"),
 		Products:    []*entities.OrderProductEntity{},
 		Status:      []*entities.OrderStatusEntity{},
 	}
@@ -112,7 +122,7 @@ func (suite *OrderPresenterTestSuite) Test_Present_WithCustomerServiceFailure_Sh
 		Maybe()
 
 	// WHEN the order is presented
-	result := suite.presenter.Present(order)
+	result := suite.presenter.Present(context.Background(), order)
 
 	// THEN the operation should complete (graceful degradation)
 	assert.NotNil(suite.T(), result)
@@ -124,14 +134,48 @@ func (suite *OrderPresenterTestSuite) Test_Present_WithCustomerServiceFailure_Sh
 	suite.mockCustomerClient.AssertExpectations(suite.T())
 }
 
+func (suite *OrderPresenterTestSuite) Test_Present_WithCustomerServiceFailure_ShouldTraceMissingEnrichment() {
+	// GIVEN a traced request for an order with a customer and a product
+	recorder := tracingtest.NewRecorder(suite.T())
+	order := &entities.OrderEntity{
+		ID:         456,
+		CustomerId: uintPtr(5),
+		Products: []*entities.OrderProductEntity{
+			{ProductId: 10, Price: money.MustParse("50.00"), Quantity: 1},
+		},
+	}
+
+	// AND the customer service fails
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(5)).
+		Return(nil, errors.New("service unavailable")).
+		Once()
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{10}).
+		Return([]*clients.ProductDTO{{ID: 10, Name: "X-Burger"}}, nil).
+		Once()
+
+	// WHEN the order is presented
+	suite.presenter.Present(context.Background(), order)
+
+	// THEN the enrichment span should show what was requested and found
+	span := tracingtest.SpanNamed(recorder, "OrderPresenter.fetchLookups")
+	suite.Require().NotNil(span)
+	assert.Subset(suite.T(), span.Attributes(), []attribute.KeyValue{
+		attribute.Int("enrichment.customers.requested", 1),
+		attribute.Int("enrichment.customers.found", 0),
+		attribute.Int("enrichment.products.requested", 1),
+		attribute.Int("enrichment.products.found", 1),
+	})
+}
+
 func (suite *OrderPresenterTestSuite) Test_Present_WithProductServiceFailure_ShouldReturnOrderWithBasicProducts() {
 	// GIVEN an order with products
 	order := &entities.OrderEntity{
 		ID:          789,
-		CustomerId:  0,
-		TotalAmount: 150.00,
+		TotalAmount: money.MustParse("150.00"),
 		Products: []*entities.OrderProductEntity{
-			{ProductId: 20, Price: 75.00, Quantity: 2},
+			{ProductId: 20, Price: money.MustParse("75.00"), Quantity: 2},
 		},
 		Status: []*entities.OrderStatusEntity{},
 	}
@@ -143,26 +187,53 @@ func (suite *OrderPresenterTestSuite) Test_Present_WithProductServiceFailure_Sho
 		Once()
 
 	// WHEN the order is presented
-	result := suite.presenter.Present(order)
+	result := suite.presenter.Present(context.Background(), order)
 
 	// THEN the operation should complete (graceful degradation)
 	assert.NotNil(suite.T(), result)
 	// AND products should be returned without enrichment
 	assert.Len(suite.T(), result.Products, 1)
 	assert.Equal(suite.T(), uint(20), result.Products[0].ProductId)
-	assert.Equal(suite.T(), float32(75.00), result.Products[0].Price)
+	assert.Equal(suite.T(), money.MustParse("75.00"), result.Products[0].Price)
 	// AND enriched fields should be empty
 	assert.Empty(suite.T(), result.Products[0].Name)
 	assert.Empty(suite.T(), result.Products[0].Description)
 	suite.mockProductClient.AssertExpectations(suite.T())
 }
 
-func (suite *OrderPresenterTestSuite) Test_Present_WithNoCustomerId_ShouldNotFetchCustomer() {
-	// GIVEN an order without customer ID
+func (suite *OrderPresenterTestSuite) Test_PresentOrders_WithCustomerCircuitOpen_ShouldReturnOrdersWithoutCustomers() {
+	// GIVEN orders from several customers
+	orders := []*entities.OrderEntity{
+		{ID: 1, CustomerId: uintPtr(1)},
+		{ID: 2, CustomerId: uintPtr(2)},
+		{ID: 3, CustomerId: uintPtr(3)},
+	}
+
+	// AND the customer service circuit is open
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, mock.Anything).
+		Return(nil, &httpclient.CircuitOpenError{Host: "customer-service", RetryAfter: time.Minute}).
+		Maybe()
+
+	// WHEN the orders are presented
+	result := suite.presenter.PresentOrders(context.Background(), orders)
+
+	// THEN the orders should be returned without customer data
+	assert.Len(suite.T(), result.Orders, 3)
+	for _, order := range result.Orders {
+		assert.Nil(suite.T(), order.Customer)
+		assert.NotNil(suite.T(), order.CustomerId)
+	}
+}
+
+// Scenario: Present guest orders
+
+func (suite *OrderPresenterTestSuite) Test_Present_WithGuestOrder_ShouldNotFetchCustomer() {
+	// GIVEN a guest order without customer ID
 	order := &entities.OrderEntity{
 		ID:          100,
-		CustomerId:  0,
-		TotalAmount: 50.00,
+		GuestName:   "Maria",
+		TotalAmount: money.MustParse("50.00"),
 		Products:    []*entities.OrderProductEntity{},
 		Status:      []*entities.OrderStatusEntity{},
 	}
@@ -173,13 +244,63 @@ func (suite *OrderPresenterTestSuite) Test_Present_WithNoCustomerId_ShouldNotFet
 		Maybe()
 
 	// WHEN the order is presented
-	result := suite.presenter.Present(order)
+	result := suite.presenter.Present(context.Background(), order)
 
 	// THEN customer service should not be called
-	suite.mockCustomerClient.AssertNotCalled(suite.T(), "GetCustomer")
-	// AND customer should be nil in response
-	assert.Nil(suite.T(), result.Customer)
+	suite.mockCustomerClient.AssertNotCalled(suite.T(), "GetCustomer", mock.Anything, mock.Anything)
+	// AND customer fields should be empty in response
 	assert.NotNil(suite.T(), result)
+	assert.Nil(suite.T(), result.Customer)
+	assert.Nil(suite.T(), result.CustomerId)
+	// AND the guest name should be presented
+	assert.Equal(suite.T(), "Maria", result.GuestName)
+}
+
+func (suite *OrderPresenterTestSuite) Test_Present_WithGuestOrder_ShouldOmitCustomerFromJSON() {
+	// GIVEN a guest order without customer ID or guest name
+	order := &entities.OrderEntity{
+		ID:       101,
+		Products: []*entities.OrderProductEntity{},
+		Status:   []*entities.OrderStatusEntity{},
+	}
+
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, mock.Anything).
+		Return([]*clients.ProductDTO{}, nil).
+		Maybe()
+
+	// WHEN the order is presented and serialized
+	body, err := json.Marshal(suite.presenter.Present(context.Background(), order))
+
+	// THEN the customer fields should be omitted instead of reported as id 0
+	assert.NoError(suite.T(), err)
+	assert.NotContains(suite.T(), string(body), "customer_id")
+	assert.NotContains(suite.T(), string(body), "guest_name")
+}
+
+func (suite *OrderPresenterTestSuite) Test_Present_ShouldSerializeMoneyAsDecimal() {
+	// GIVEN a guest order with decimal amounts
+	order := &entities.OrderEntity{
+		ID:          102,
+		TotalAmount: money.MustParse("104.95"),
+		Products: []*entities.OrderProductEntity{
+			{ProductId: 2, Price: money.MustParse("34.99"), Quantity: 1},
+		},
+		Status: []*entities.OrderStatusEntity{},
+	}
+
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{2}).
+		Return([]*clients.ProductDTO{}, nil).
+		Once()
+
+	// WHEN the order is presented and serialized
+	body, err := json.Marshal(suite.presenter.Present(context.Background(), order))
+
+	// THEN the amounts should keep the decimal number format
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(body), `"total_amount":104.95`)
+	assert.Contains(suite.T(), string(body), `"price":34.99`)
 }
 
 // Feature: Order Presenter - Present Orders
@@ -188,8 +309,8 @@ func (suite *OrderPresenterTestSuite) Test_Present_WithNoCustomerId_ShouldNotFet
 func (suite *OrderPresenterTestSuite) Test_PresentOrders_WithMultipleOrders_ShouldPresentAll() {
 	// GIVEN multiple orders
 	orders := []*entities.OrderEntity{
-		{ID: 1, CustomerId: 1, TotalAmount: 50.00, Products: []*entities.OrderProductEntity{}, Status: []*entities.OrderStatusEntity{}},
-		{ID: 2, CustomerId: 2, TotalAmount: 75.00, Products: []*entities.OrderProductEntity{}, Status: []*entities.OrderStatusEntity{}},
+		{ID: 1, CustomerId: uintPtr(1), TotalAmount: money.MustParse("50.00"), Products: []*entities.OrderProductEntity{}, Status: []*entities.OrderStatusEntity{}},
+		{ID: 2, CustomerId: uintPtr(2), TotalAmount: money.MustParse("75.00"), Products: []*entities.OrderProductEntity{}, Status: []*entities.OrderStatusEntity{}},
 	}
 
 	suite.mockCustomerClient.EXPECT().
@@ -197,13 +318,8 @@ func (suite *OrderPresenterTestSuite) Test_PresentOrders_WithMultipleOrders_Shou
 		Return(&clients.CustomerDTO{ID: 1, Name: "Customer"}, nil).
 		Times(2)
 
-	suite.mockProductClient.EXPECT().
-		GetProducts(mock.Anything, mock.Anything).
-		Return([]*clients.ProductDTO{}, nil).
-		Times(2)
-
 	// WHEN orders are presented
-	result := suite.presenter.PresentOrders(orders)
+	result := suite.presenter.PresentOrders(context.Background(), orders)
 
 	// THEN all orders should be in the result
 	assert.NotNil(suite.T(), result)
@@ -212,12 +328,58 @@ func (suite *OrderPresenterTestSuite) Test_PresentOrders_WithMultipleOrders_Shou
 	assert.Equal(suite.T(), uint(2), result.Orders[1].ID)
 }
 
+func (suite *OrderPresenterTestSuite) Test_PresentOrders_WithSharedCustomersAndProducts_ShouldFetchEachOnce() {
+	// GIVEN orders that share customers and products
+	orders := []*entities.OrderEntity{
+		{ID: 1, CustomerId: uintPtr(1), Products: []*entities.OrderProductEntity{{ProductId: 10, Quantity: 1}, {ProductId: 20, Quantity: 1}}},
+		{ID: 2, CustomerId: uintPtr(2), Products: []*entities.OrderProductEntity{{ProductId: 20, Quantity: 2}}},
+		{ID: 3, CustomerId: uintPtr(1), Products: []*entities.OrderProductEntity{{ProductId: 10, Quantity: 3}}},
+		{ID: 4, GuestName: "Maria", Products: []*entities.OrderProductEntity{{ProductId: 30, Quantity: 1}}},
+	}
+
+	// AND each distinct customer is fetched once
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(1)).
+		Return(&clients.CustomerDTO{ID: 1, Name: "John Doe"}, nil).
+		Once()
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(2)).
+		Return(&clients.CustomerDTO{ID: 2, Name: "Jane Doe"}, nil).
+		Once()
+
+	// AND the distinct products are fetched in a single batch
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{10, 20, 30}).
+		Return([]*clients.ProductDTO{
+			{ID: 10, Name: "Product A"},
+			{ID: 20, Name: "Product B"},
+			{ID: 30, Name: "Product C"},
+		}, nil).
+		Once()
+
+	// WHEN orders are presented
+	result := suite.presenter.PresentOrders(context.Background(), orders)
+
+	// THEN every order should be enriched from the shared lookups
+	assert.Len(suite.T(), result.Orders, 4)
+	assert.Equal(suite.T(), "John Doe", result.Orders[0].Customer.Name)
+	assert.Equal(suite.T(), "Jane Doe", result.Orders[1].Customer.Name)
+	assert.Equal(suite.T(), "John Doe", result.Orders[2].Customer.Name)
+	assert.Nil(suite.T(), result.Orders[3].Customer)
+	assert.Equal(suite.T(), "Product B", result.Orders[0].Products[1].Name)
+	assert.Equal(suite.T(), "Product B", result.Orders[1].Products[0].Name)
+	assert.Equal(suite.T(), "Product A", result.Orders[2].Products[0].Name)
+	assert.Equal(suite.T(), "Product C", result.Orders[3].Products[0].Name)
+	// AND the quantities should stay per order
+	assert.Equal(suite.T(), uint(3), result.Orders[2].Products[0].Quantity)
+}
+
 func (suite *OrderPresenterTestSuite) Test_PresentOrders_WithEmptyList_ShouldReturnEmptyDTO() {
 	// GIVEN an empty order list
 	orders := []*entities.OrderEntity{}
 
 	// WHEN orders are presented
-	result := suite.presenter.PresentOrders(orders)
+	result := suite.presenter.PresentOrders(context.Background(), orders)
 
 	// THEN an empty orders DTO should be returned
 	assert.NotNil(suite.T(), result)
@@ -230,13 +392,13 @@ func (suite *OrderPresenterTestSuite) Test_PresentOrders_WithEmptyList_ShouldRet
 func (suite *OrderPresenterTestSuite) Test_PresentProducts_WithEnrichedData_ShouldIncludeAllFields() {
 	// GIVEN order products
 	orderProducts := []*entities.OrderProductEntity{
-		{ProductId: 10, Price: 25.00, Quantity: 2},
-		{ProductId: 20, Price: 50.00, Quantity: 1},
+		{ProductId: 10, Price: money.MustParse("25.00"), Quantity: 2},
+		{ProductId: 20, Price: money.MustParse("50.00"), Quantity: 1},
 	}
 
 	enrichedProducts := []*clients.ProductDTO{
-		{ID: 10, Name: "Product 1", Description: "Desc 1", Category: 1, ImageLink: "img1.jpg", Price: 25.00},
-		{ID: 20, Name: "Product 2", Description: "Desc 2", Category: 2, ImageLink: "img2.jpg", Price: 50.00},
+		{ID: 10, Name: "Product 1", Description: "Desc 1", Category: 1, ImageLink: "img1.jpg", Price: money.MustParse("25.00")},
+		{ID: 20, Name: "Product 2", Description: "Desc 2", Category: 2, ImageLink: "img2.jpg", Price: money.MustParse("50.00")},
 	}
 
 	suite.mockProductClient.EXPECT().
@@ -245,7 +407,7 @@ func (suite *OrderPresenterTestSuite) Test_PresentProducts_WithEnrichedData_Shou
 		Once()
 
 	// WHEN products are presented
-	result := suite.presenter.PresentProducts(orderProducts)
+	result := suite.presenter.PresentProducts(context.Background(), orderProducts)
 
 	// THEN all products should be enriched
 	assert.Len(suite.T(), result, 2)
@@ -258,13 +420,13 @@ func (suite *OrderPresenterTestSuite) Test_PresentProducts_WithEnrichedData_Shou
 func (suite *OrderPresenterTestSuite) Test_PresentProducts_WithMissingProduct_ShouldReturnPartialEnrichment() {
 	// GIVEN order products
 	orderProducts := []*entities.OrderProductEntity{
-		{ProductId: 10, Price: 25.00, Quantity: 2},
-		{ProductId: 99, Price: 50.00, Quantity: 1},
+		{ProductId: 10, Price: money.MustParse("25.00"), Quantity: 2},
+		{ProductId: 99, Price: money.MustParse("50.00"), Quantity: 1},
 	}
 
 	// AND product service only returns one product
 	enrichedProducts := []*clients.ProductDTO{
-		{ID: 10, Name: "Product 1", Price: 25.00},
+		{ID: 10, Name: "Product 1", Price: money.MustParse("25.00")},
 	}
 
 	suite.mockProductClient.EXPECT().
@@ -273,7 +435,7 @@ func (suite *OrderPresenterTestSuite) Test_PresentProducts_WithMissingProduct_Sh
 		Once()
 
 	// WHEN products are presented
-	result := suite.presenter.PresentProducts(orderProducts)
+	result := suite.presenter.PresentProducts(context.Background(), orderProducts)
 
 	// THEN first product should be enriched
 	assert.Equal(suite.T(), "Product 1", result[0].Name)
@@ -283,6 +445,31 @@ func (suite *OrderPresenterTestSuite) Test_PresentProducts_WithMissingProduct_Sh
 	suite.mockProductClient.AssertExpectations(suite.T())
 }
 
+func (suite *OrderPresenterTestSuite) Test_PresentProducts_WithPartialProductServiceFailure_ShouldEnrichFetchedProducts() {
+	// GIVEN order products
+	orderProducts := []*entities.OrderProductEntity{
+		{ProductId: 10, Price: money.MustParse("25.00"), Quantity: 2},
+		{ProductId: 20, Price: money.MustParse("50.00"), Quantity: 1},
+	}
+
+	// AND the product service fails for one of them
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{10, 20}).
+		Return([]*clients.ProductDTO{{ID: 10, Name: "Product 1"}}, &clients.ProductsError{
+			Errors: map[uint]error{20: errors.New("service down")},
+		}).
+		Once()
+
+	// WHEN products are presented
+	result := suite.presenter.PresentProducts(context.Background(), orderProducts)
+
+	// THEN the fetched product should be enriched
+	assert.Equal(suite.T(), "Product 1", result[0].Name)
+	// AND the failed product should have basic data only
+	assert.Equal(suite.T(), uint(20), result[1].ProductId)
+	assert.Empty(suite.T(), result[1].Name)
+}
+
 // Feature: Order Presenter - Present Status
 // Scenario: Transform status entity to DTO with description
 
@@ -353,6 +540,26 @@ func (suite *OrderPresenterTestSuite) Test_PresentStatus_WithFinalizado_ShouldRe
 	assert.Equal(suite.T(), "Finalizado", result.CurrentStatusDescription)
 }
 
+func (suite *OrderPresenterTestSuite) Test_PresentStatus_WithCancelado_ShouldIncludeReasonAndAuthor() {
+	// GIVEN a status with value 5 (Cancelado)
+	status := &entities.OrderStatusEntity{
+		CurrentStatus: 5,
+		OrderId:       1100,
+		ReasonCode:    entities.CancellationReasonCustomerRequest,
+		ChangedBy:     "kiosk-01",
+		CreatedAt:     time.Now(),
+	}
+
+	// WHEN the status is presented
+	result := suite.presenter.PresentStatus(status)
+
+	// THEN the description should be "Cancelado"
+	assert.Equal(suite.T(), "Cancelado", result.CurrentStatusDescription)
+	// AND the reason and author should be presented
+	assert.Equal(suite.T(), entities.CancellationReasonCustomerRequest, result.ReasonCode)
+	assert.Equal(suite.T(), "kiosk-01", result.ChangedBy)
+}
+
 func (suite *OrderPresenterTestSuite) Test_PresentStatus_WithInvalidStatus_ShouldReturnNil() {
 	// GIVEN a status with invalid value
 	status := &entities.OrderStatusEntity{
@@ -1,9 +1,24 @@
 package addorder
 
 import (
+	"context"
+	"time"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 )
 
 type AddOrderUseCase interface {
-	Execute(command *commands.AddOrderCommand) (string, error)
+	Execute(ctx context.Context, command *commands.AddOrderCommand) (string, error)
+}
+
+// Settings are the configurable rules of order creation.
+type Settings struct {
+	// IdempotencyKeyTTL is how long an idempotency key replays its order.
+	IdempotencyKeyTTL time.Duration
+	// RejectPriceMismatch rejects client prices that disagree with the
+	// catalog, instead of replacing them with the catalog ones.
+	RejectPriceMismatch bool
+	// AcceptUnverifiedCustomers accepts orders while the customer service is
+	// unavailable, flagged for reconciliation, instead of failing them.
+	AcceptUnverifiedCustomers bool
 }
@@ -1,12 +1,23 @@
 package addorder
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/logging"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
 var (
@@ -14,61 +25,251 @@ var (
 )
 
 type AddOrderUseCaseImpl struct {
-	orderRepository        repositories.OrderRepository
-	orderProductRepository repositories.OrderProductRepository
-	orderStatusRepository  repositories.OrderStatusRepository
-	customerClient         clients.CustomerClient
-	productClient          clients.ProductClient
+	unitOfWork               repositories.UnitOfWork
+	idempotencyKeyRepository repositories.IdempotencyKeyRepository
+	customerClient           clients.CustomerClient
+	productClient            clients.ProductClient
+	settings                 Settings
+	recorder                 usecase.OrderRecorder
 }
 
 func NewAddOrderUseCaseImpl(
-	orderRepository repositories.OrderRepository,
-	orderProductRepository repositories.OrderProductRepository,
-	orderStatusRepository repositories.OrderStatusRepository,
+	unitOfWork repositories.UnitOfWork,
+	idempotencyKeyRepository repositories.IdempotencyKeyRepository,
 	customerClient clients.CustomerClient,
-	productClient clients.ProductClient) *AddOrderUseCaseImpl {
+	productClient clients.ProductClient,
+	settings Settings,
+	recorder usecase.OrderRecorder) *AddOrderUseCaseImpl {
 	return &AddOrderUseCaseImpl{
-		orderRepository:        orderRepository,
-		orderProductRepository: orderProductRepository,
-		orderStatusRepository:  orderStatusRepository,
-		customerClient:         customerClient,
-		productClient:          productClient,
+		unitOfWork:               unitOfWork,
+		idempotencyKeyRepository: idempotencyKeyRepository,
+		customerClient:           customerClient,
+		productClient:            productClient,
+		settings:                 settings,
+		recorder:                 recorder,
 	}
 }
 
-func (u *AddOrderUseCaseImpl) Execute(command *commands.AddOrderCommand) (string, error) {
-	// Create order
-	orderResult, err := u.orderRepository.AddOrder(&entities.OrderEntity{
-		CustomerId:  command.CustomerId,
-		TotalAmount: command.TotalAmount,
-	})
+func (u *AddOrderUseCaseImpl) Execute(ctx context.Context, command *commands.AddOrderCommand) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "AddOrderUseCase.Execute", trace.WithAttributes(
+		attribute.Bool("order.guest", command.CustomerId == nil),
+		attribute.Int("order.product_count", len(command.Products)),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	// Replay requests already processed with the same idempotency key
+	if command.IdempotencyKey != "" {
+		orderId, replayed, err := u.replay(ctx, command)
+		if err != nil || replayed {
+			span.SetAttributes(attribute.Bool("order.idempotent_replay", replayed))
+			return orderId, err
+		}
+	}
+
+	// Verify the customer with the customer service
+	reconciliationPending, err := u.verifyCustomer(ctx, command.CustomerId)
+	if err != nil {
+		return "", err
+	}
+
+	// Price the order with the catalog prices
+	orderProducts, totalAmount, err := u.priceOrder(ctx, command)
 	if err != nil {
 		return "", err
 	}
 
-	// Add order products
-	for _, orderProductDto := range command.Products {
-		orderProductEntity := &entities.OrderProductEntity{
-			OrderId:   orderResult.ID,
-			ProductId: orderProductDto.ProductId,
-			Price:     orderProductDto.Price,
-			Quantity:  orderProductDto.Quantity,
+	// Persist the order, its products, the initial status and the idempotency
+	// key atomically
+	var orderId uint
+	err = u.unitOfWork.Do(ctx, func(repos *repositories.Repositories) error {
+		orderResult, err := repos.Orders.AddOrder(ctx, &entities.OrderEntity{
+			CustomerId:                    command.CustomerId,
+			GuestName:                     command.GuestName,
+			TotalAmount:                   totalAmount,
+			CustomerReconciliationPending: reconciliationPending,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, orderProductEntity := range orderProducts {
+			orderProductEntity.OrderId = orderResult.ID
+			if err := repos.OrderProducts.AddOrderProduct(ctx, orderProductEntity); err != nil {
+				return err
+			}
 		}
-		err := u.orderProductRepository.AddOrderProduct(orderProductEntity)
+
+		err = repos.OrderStatus.AddOrderStatus(ctx, &entities.OrderStatusEntity{
+			OrderId:       orderResult.ID,
+			CurrentStatus: entities.OrderStatusRecebido,
+		})
 		if err != nil {
-			return "", err
+			return err
 		}
-	}
 
-	// Create initial order status
-	orderStatusEntity := &entities.OrderStatusEntity{
-		OrderId:       orderResult.ID,
-		CurrentStatus: 1,
+		if command.IdempotencyKey != "" {
+			now := time.Now()
+			err = repos.IdempotencyKeys.AddIdempotencyKey(ctx, &entities.IdempotencyKeyEntity{
+				Key:         command.IdempotencyKey,
+				RequestHash: command.RequestHash,
+				OrderId:     orderResult.ID,
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(u.settings.IdempotencyKeyTTL),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		orderId = orderResult.ID
+		return nil
+	})
+	if errors.Is(err, domainerrors.ErrIdempotencyKeyConflict) {
+		// A concurrent request with the same key committed first, answer as a replay
+		replayedOrderId, replayed, replayErr := u.replay(ctx, command)
+		if replayErr != nil || replayed {
+			return replayedOrderId, replayErr
+		}
+		return "", err
 	}
-	err = u.orderStatusRepository.AddOrderStatus(orderStatusEntity)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("%d", orderResult.ID), nil
+	u.recorder.OrderCreated()
+	span.SetAttributes(attribute.Int64("order.id", int64(orderId)))
+	return fmt.Sprintf("%d", orderId), nil
+}
+
+// replay returns the order created by an earlier request with the same
+// idempotency key. Reusing the key with a different request is rejected.
+func (u *AddOrderUseCaseImpl) replay(ctx context.Context, command *commands.AddOrderCommand) (string, bool, error) {
+	idempotencyKey, err := u.idempotencyKeyRepository.GetIdempotencyKey(ctx, command.IdempotencyKey, time.Now())
+	if errors.Is(err, domainerrors.ErrIdempotencyKeyNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	if idempotencyKey.RequestHash != command.RequestHash {
+		return "", false, &domainerrors.IdempotencyKeyReusedError{Key: command.IdempotencyKey}
+	}
+
+	return fmt.Sprintf("%d", idempotencyKey.OrderId), true, nil
+}
+
+// verifyCustomer checks that the customer exists. Guest orders (nil customerId)
+// skip the check. When the customer service is unavailable the configured policy
+// decides whether the order is rejected or accepted and flagged for
+// reconciliation (returned as true).
+func (u *AddOrderUseCaseImpl) verifyCustomer(ctx context.Context, customerId *uint) (bool, error) {
+	if customerId == nil {
+		return false, nil
+	}
+
+	_, err := u.customerClient.GetCustomer(ctx, *customerId)
+	if err == nil {
+		return false, nil
+	}
+
+	if errors.Is(err, clients.ErrCustomerNotFound) {
+		return false, &domainerrors.CustomerNotFoundError{CustomerId: *customerId}
+	}
+
+	if u.settings.AcceptUnverifiedCustomers {
+		logging.FromContext(ctx).Warn("customer service unavailable, accepting order for reconciliation",
+			zap.Uint("customer_id", *customerId), zap.Error(err))
+		return true, nil
+	}
+
+	return false, &domainerrors.UpstreamUnavailableError{Service: "customer", Err: err}
+}
+
+// priceOrder fetches the authoritative prices from the product service and
+// computes the order lines and total. Client supplied prices (non-zero values)
+// are checked against the catalog according to the configured mismatch policy.
+func (u *AddOrderUseCaseImpl) priceOrder(ctx context.Context, command *commands.AddOrderCommand) ([]*entities.OrderProductEntity, money.Money, error) {
+	catalog, err := u.fetchCatalog(ctx, command)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+
+	orderProducts := make([]*entities.OrderProductEntity, 0, len(command.Products))
+	var totalAmount money.Money
+	for _, item := range command.Products {
+		product, exists := catalog[item.ProductId]
+		if !exists {
+			return nil, money.Money{}, &domainerrors.ProductNotFoundError{ProductId: item.ProductId}
+		}
+
+		if !item.Price.IsZero() && item.Price != product.Price {
+			if err := u.onPriceMismatch(item.ProductId, product.Price, item.Price); err != nil {
+				return nil, money.Money{}, err
+			}
+		}
+
+		subtotal, err := product.Price.Multiply(int64(item.Quantity))
+		if err == nil {
+			totalAmount, err = totalAmount.Add(subtotal)
+		}
+		if err != nil {
+			return nil, money.Money{}, &domainerrors.OrderTotalTooLargeError{Err: err}
+		}
+		orderProducts = append(orderProducts, &entities.OrderProductEntity{
+			ProductId: item.ProductId,
+			Price:     product.Price,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	if !command.TotalAmount.IsZero() && command.TotalAmount != totalAmount {
+		if err := u.onPriceMismatch(0, totalAmount, command.TotalAmount); err != nil {
+			return nil, money.Money{}, err
+		}
+	}
+
+	return orderProducts, totalAmount, nil
+}
+
+func (u *AddOrderUseCaseImpl) fetchCatalog(ctx context.Context, command *commands.AddOrderCommand) (map[uint]*clients.ProductDTO, error) {
+	catalog := make(map[uint]*clients.ProductDTO)
+	if len(command.Products) == 0 {
+		return catalog, nil
+	}
+
+	// Fetch each distinct product only once
+	productIDs := make([]uint, 0, len(command.Products))
+	seen := make(map[uint]bool)
+	for _, item := range command.Products {
+		if !seen[item.ProductId] {
+			seen[item.ProductId] = true
+			productIDs = append(productIDs, item.ProductId)
+		}
+	}
+
+	// Products missing from the catalog are reported by priceOrder; any other
+	// failure means the catalog can't be trusted
+	products, err := u.productClient.GetProducts(ctx, productIDs)
+	var productsErr *clients.ProductsError
+	if err != nil && !(errors.As(err, &productsErr) && productsErr.NotFound()) {
+		return nil, &domainerrors.UpstreamUnavailableError{Service: "product", Err: err}
+	}
+
+	for _, product := range products {
+		catalog[product.ID] = product
+	}
+
+	return catalog, nil
+}
+
+func (u *AddOrderUseCaseImpl) onPriceMismatch(productId uint, expected money.Money, actual money.Money) error {
+	if u.settings.RejectPriceMismatch {
+		return &domainerrors.PriceMismatchError{
+			ProductId: productId,
+			Expected:  expected,
+			Actual:    actual,
+		}
+	}
+	return nil
 }
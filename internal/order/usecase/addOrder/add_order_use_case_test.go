@@ -1,16 +1,28 @@
 package addorder_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/infrastructure/clients"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/metrics"
 	addorder "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/addOrder"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 	mockClients "github.com/viniciuscluna/tc-fiap-50/mocks/infrastructure/clients"
 	mockRepositories "github.com/viniciuscluna/tc-fiap-50/mocks/order/domain/repositories"
 )
@@ -20,8 +32,12 @@ type AddOrderUseCaseTestSuite struct {
 	mockOrderRepository        *mockRepositories.MockOrderRepository
 	mockOrderProductRepository *mockRepositories.MockOrderProductRepository
 	mockOrderStatusRepository  *mockRepositories.MockOrderStatusRepository
+	mockUnitOfWork             *mockRepositories.MockUnitOfWork
+	mockIdempotencyKeyRepo     *mockRepositories.MockIdempotencyKeyRepository
 	mockCustomerClient         *mockClients.MockCustomerClient
 	mockProductClient          *mockClients.MockProductClient
+	settings                   addorder.Settings
+	registry                   *prometheus.Registry
 	useCase                    addorder.AddOrderUseCase
 }
 
@@ -29,69 +45,125 @@ func (suite *AddOrderUseCaseTestSuite) SetupTest() {
 	suite.mockOrderRepository = mockRepositories.NewMockOrderRepository(suite.T())
 	suite.mockOrderProductRepository = mockRepositories.NewMockOrderProductRepository(suite.T())
 	suite.mockOrderStatusRepository = mockRepositories.NewMockOrderStatusRepository(suite.T())
+	suite.mockUnitOfWork = mockRepositories.NewMockUnitOfWork(suite.T())
+	suite.mockIdempotencyKeyRepo = mockRepositories.NewMockIdempotencyKeyRepository(suite.T())
 	suite.mockCustomerClient = mockClients.NewMockCustomerClient(suite.T())
 	suite.mockProductClient = mockClients.NewMockProductClient(suite.T())
+	suite.settings = addorder.Settings{IdempotencyKeyTTL: 24 * time.Hour}
+
+	// Customers used across the scenarios exist in the customer service
+	for _, customerId := range []uint{1, 5} {
+		suite.mockCustomerClient.EXPECT().
+			GetCustomer(mock.Anything, customerId).
+			Return(&clients.CustomerDTO{ID: customerId}, nil).
+			Maybe()
+	}
+
+	// The unit of work hands the repository mocks to the transactional function
+	suite.mockUnitOfWork.EXPECT().
+		Do(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fn func(*repositories.Repositories) error) error {
+			return fn(&repositories.Repositories{
+				Orders:          suite.mockOrderRepository,
+				OrderProducts:   suite.mockOrderProductRepository,
+				OrderStatus:     suite.mockOrderStatusRepository,
+				IdempotencyKeys: suite.mockIdempotencyKeyRepo,
+			})
+		}).
+		Maybe()
+
+	suite.newUseCase()
+}
 
+// newUseCase creates the use case with the current settings.
+func (suite *AddOrderUseCaseTestSuite) newUseCase() {
+	suite.registry = prometheus.NewRegistry()
 	suite.useCase = addorder.NewAddOrderUseCaseImpl(
-		suite.mockOrderRepository,
-		suite.mockOrderProductRepository,
-		suite.mockOrderStatusRepository,
+		suite.mockUnitOfWork,
+		suite.mockIdempotencyKeyRepo,
 		suite.mockCustomerClient,
 		suite.mockProductClient,
+		suite.settings,
+		metrics.NewOrderMetrics(suite.registry),
 	)
 }
 
+// assertOrdersCreated checks the orders_created_total counter.
+func (suite *AddOrderUseCaseTestSuite) assertOrdersCreated(expected int) {
+	assert.NoError(suite.T(), testutil.GatherAndCompare(suite.registry, strings.NewReader(fmt.Sprintf(`
+# HELP orders_created_total Orders created. Idempotent replays are not counted.
+# TYPE orders_created_total counter
+orders_created_total %d
+`, expected)), "orders_created_total"))
+}
+
 func TestAddOrderUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(AddOrderUseCaseTestSuite))
 }
 
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func (suite *AddOrderUseCaseTestSuite) givenCatalog(productIDs []uint, products ...*clients.ProductDTO) {
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, productIDs).
+		Return(products, nil).
+		Once()
+}
+
 // Feature: Add Order Use Case
 // Scenario: Create a new order with products successfully
 
 func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithValidCommand_ShouldCreateOrderSuccessfully() {
 	// GIVEN a valid add order command with products
 	products := []*dto.AddOrderProductDto{
-		{ProductId: 1, Quantity: 2, Price: 25.50},
-		{ProductId: 2, Quantity: 1, Price: 50.00},
+		{ProductId: 1, Quantity: 2, Price: money.MustParse("25.50")},
+		{ProductId: 2, Quantity: 1, Price: money.MustParse("50.00")},
 	}
-	command := commands.NewAddOrderCommand(1, 101.00, products)
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("101.00"), products)
+
+	// AND the catalog has the same prices
+	suite.givenCatalog([]uint{1, 2},
+		&clients.ProductDTO{ID: 1, Price: money.MustParse("25.50")},
+		&clients.ProductDTO{ID: 2, Price: money.MustParse("50.00")})
 
 	createdOrder := &entities.OrderEntity{
 		ID:          123,
-		CustomerId:  1,
-		TotalAmount: 101.00,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("101.00"),
 	}
 
 	suite.mockOrderRepository.EXPECT().
-		AddOrder(mock.MatchedBy(func(order *entities.OrderEntity) bool {
-			return order.CustomerId == 1 && order.TotalAmount == 101.00
+		AddOrder(mock.Anything, mock.MatchedBy(func(order *entities.OrderEntity) bool {
+			return order.CustomerId != nil && *order.CustomerId == 1 && order.TotalAmount == money.MustParse("101.00")
 		})).
 		Return(createdOrder, nil).
 		Once()
 
 	suite.mockOrderProductRepository.EXPECT().
-		AddOrderProduct(mock.MatchedBy(func(product *entities.OrderProductEntity) bool {
+		AddOrderProduct(mock.Anything, mock.MatchedBy(func(product *entities.OrderProductEntity) bool {
 			return product.OrderId == 123 && product.ProductId == 1
 		})).
 		Return(nil).
 		Once()
 
 	suite.mockOrderProductRepository.EXPECT().
-		AddOrderProduct(mock.MatchedBy(func(product *entities.OrderProductEntity) bool {
+		AddOrderProduct(mock.Anything, mock.MatchedBy(func(product *entities.OrderProductEntity) bool {
 			return product.OrderId == 123 && product.ProductId == 2
 		})).
 		Return(nil).
 		Once()
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
+		AddOrderStatus(mock.Anything, mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
 			return status.OrderId == 123 && status.CurrentStatus == 1
 		})).
 		Return(nil).
 		Once()
 
 	// WHEN the order creation is executed
-	orderId, err := suite.useCase.Execute(command)
+	orderId, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -101,36 +173,43 @@ func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithValidCommand_ShouldCrea
 	suite.mockOrderRepository.AssertExpectations(suite.T())
 	suite.mockOrderProductRepository.AssertExpectations(suite.T())
 	suite.mockOrderStatusRepository.AssertExpectations(suite.T())
+	// AND the order should be counted as created
+	suite.assertOrdersCreated(1)
 }
 
 func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithMultipleProducts_ShouldAddAllProducts() {
 	// GIVEN an order with three different products
 	products := []*dto.AddOrderProductDto{
-		{ProductId: 10, Quantity: 1, Price: 10.00},
-		{ProductId: 20, Quantity: 2, Price: 20.00},
-		{ProductId: 30, Quantity: 3, Price: 30.00},
+		{ProductId: 10, Quantity: 1, Price: money.MustParse("10.00")},
+		{ProductId: 20, Quantity: 2, Price: money.MustParse("20.00")},
+		{ProductId: 30, Quantity: 3, Price: money.MustParse("30.00")},
 	}
-	command := commands.NewAddOrderCommand(5, 140.00, products)
+	command := commands.NewAddOrderCommand(uintPtr(5), "", money.MustParse("140.00"), products)
+
+	suite.givenCatalog([]uint{10, 20, 30},
+		&clients.ProductDTO{ID: 10, Price: money.MustParse("10.00")},
+		&clients.ProductDTO{ID: 20, Price: money.MustParse("20.00")},
+		&clients.ProductDTO{ID: 30, Price: money.MustParse("30.00")})
 
-	createdOrder := &entities.OrderEntity{ID: 456, CustomerId: 5, TotalAmount: 140.00}
+	createdOrder := &entities.OrderEntity{ID: 456, CustomerId: uintPtr(5), TotalAmount: money.MustParse("140.00")}
 
 	suite.mockOrderRepository.EXPECT().
-		AddOrder(mock.Anything).
+		AddOrder(mock.Anything, mock.Anything).
 		Return(createdOrder, nil).
 		Once()
 
 	suite.mockOrderProductRepository.EXPECT().
-		AddOrderProduct(mock.Anything).
+		AddOrderProduct(mock.Anything, mock.Anything).
 		Return(nil).
 		Times(3)
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.Anything).
+		AddOrderStatus(mock.Anything, mock.Anything).
 		Return(nil).
 		Once()
 
 	// WHEN the order is created
-	orderId, err := suite.useCase.Execute(command)
+	orderId, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -142,31 +221,32 @@ func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithMultipleProducts_Should
 func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_ShouldSetInitialStatusToRecebido() {
 	// GIVEN a valid order command
 	products := []*dto.AddOrderProductDto{
-		{ProductId: 1, Quantity: 1, Price: 50.00},
+		{ProductId: 1, Quantity: 1, Price: money.MustParse("50.00")},
 	}
-	command := commands.NewAddOrderCommand(1, 50.00, products)
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("50.00"), products)
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("50.00")})
 
-	createdOrder := &entities.OrderEntity{ID: 789, CustomerId: 1, TotalAmount: 50.00}
+	createdOrder := &entities.OrderEntity{ID: 789, CustomerId: uintPtr(1), TotalAmount: money.MustParse("50.00")}
 
 	suite.mockOrderRepository.EXPECT().
-		AddOrder(mock.Anything).
+		AddOrder(mock.Anything, mock.Anything).
 		Return(createdOrder, nil).
 		Once()
 
 	suite.mockOrderProductRepository.EXPECT().
-		AddOrderProduct(mock.Anything).
+		AddOrderProduct(mock.Anything, mock.Anything).
 		Return(nil).
 		Once()
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
+		AddOrderStatus(mock.Anything, mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
 			return status.OrderId == 789 && status.CurrentStatus == 1
 		})).
 		Return(nil).
 		Once()
 
 	// WHEN the order is created
-	orderId, err := suite.useCase.Execute(command)
+	orderId, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -178,19 +258,20 @@ func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_ShouldSetInitialStatusToRec
 func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithOrderRepositoryError_ShouldReturnError() {
 	// GIVEN a valid order command
 	products := []*dto.AddOrderProductDto{
-		{ProductId: 1, Quantity: 1, Price: 50.00},
+		{ProductId: 1, Quantity: 1, Price: money.MustParse("50.00")},
 	}
-	command := commands.NewAddOrderCommand(1, 50.00, products)
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("50.00"), products)
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("50.00")})
 
 	expectedError := errors.New("database connection error")
 
 	suite.mockOrderRepository.EXPECT().
-		AddOrder(mock.Anything).
+		AddOrder(mock.Anything, mock.Anything).
 		Return(nil, expectedError).
 		Once()
 
 	// WHEN the order creation is attempted
-	orderId, err := suite.useCase.Execute(command)
+	orderId, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -207,25 +288,26 @@ func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithOrderRepositoryError_Sh
 func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithProductRepositoryError_ShouldReturnError() {
 	// GIVEN a valid order command
 	products := []*dto.AddOrderProductDto{
-		{ProductId: 1, Quantity: 1, Price: 50.00},
+		{ProductId: 1, Quantity: 1, Price: money.MustParse("50.00")},
 	}
-	command := commands.NewAddOrderCommand(1, 50.00, products)
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("50.00"), products)
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("50.00")})
 
-	createdOrder := &entities.OrderEntity{ID: 100, CustomerId: 1, TotalAmount: 50.00}
+	createdOrder := &entities.OrderEntity{ID: 100, CustomerId: uintPtr(1), TotalAmount: money.MustParse("50.00")}
 	expectedError := errors.New("product insert error")
 
 	suite.mockOrderRepository.EXPECT().
-		AddOrder(mock.Anything).
+		AddOrder(mock.Anything, mock.Anything).
 		Return(createdOrder, nil).
 		Once()
 
 	suite.mockOrderProductRepository.EXPECT().
-		AddOrderProduct(mock.Anything).
+		AddOrderProduct(mock.Anything, mock.Anything).
 		Return(expectedError).
 		Once()
 
 	// WHEN the order creation is attempted
-	orderId, err := suite.useCase.Execute(command)
+	orderId, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -239,30 +321,31 @@ func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithProductRepositoryError_
 func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithStatusRepositoryError_ShouldReturnError() {
 	// GIVEN a valid order command
 	products := []*dto.AddOrderProductDto{
-		{ProductId: 1, Quantity: 1, Price: 50.00},
+		{ProductId: 1, Quantity: 1, Price: money.MustParse("50.00")},
 	}
-	command := commands.NewAddOrderCommand(1, 50.00, products)
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("50.00"), products)
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("50.00")})
 
-	createdOrder := &entities.OrderEntity{ID: 200, CustomerId: 1, TotalAmount: 50.00}
+	createdOrder := &entities.OrderEntity{ID: 200, CustomerId: uintPtr(1), TotalAmount: money.MustParse("50.00")}
 	expectedError := errors.New("status insert error")
 
 	suite.mockOrderRepository.EXPECT().
-		AddOrder(mock.Anything).
+		AddOrder(mock.Anything, mock.Anything).
 		Return(createdOrder, nil).
 		Once()
 
 	suite.mockOrderProductRepository.EXPECT().
-		AddOrderProduct(mock.Anything).
+		AddOrderProduct(mock.Anything, mock.Anything).
 		Return(nil).
 		Once()
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.Anything).
+		AddOrderStatus(mock.Anything, mock.Anything).
 		Return(expectedError).
 		Once()
 
 	// WHEN the order creation is attempted
-	orderId, err := suite.useCase.Execute(command)
+	orderId, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -274,28 +357,558 @@ func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithStatusRepositoryError_S
 func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithNoProducts_ShouldCreateOrderWithoutProducts() {
 	// GIVEN an order command without products
 	products := []*dto.AddOrderProductDto{}
-	command := commands.NewAddOrderCommand(1, 0, products)
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("0"), products)
 
-	createdOrder := &entities.OrderEntity{ID: 300, CustomerId: 1, TotalAmount: 0}
+	createdOrder := &entities.OrderEntity{ID: 300, CustomerId: uintPtr(1), TotalAmount: money.MustParse("0")}
 
 	suite.mockOrderRepository.EXPECT().
-		AddOrder(mock.Anything).
+		AddOrder(mock.Anything, mock.Anything).
 		Return(createdOrder, nil).
 		Once()
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.Anything).
+		AddOrderStatus(mock.Anything, mock.Anything).
 		Return(nil).
 		Once()
 
 	// WHEN the order is created
-	orderId, err := suite.useCase.Execute(command)
+	orderId, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "300", orderId)
+	// AND the product service should not be called
+	suite.mockProductClient.AssertNotCalled(suite.T(), "GetProducts", mock.Anything, mock.Anything)
 	// AND no products should have been added
 	suite.mockOrderProductRepository.AssertNotCalled(suite.T(), "AddOrderProduct")
 	// AND the status should still be created
 	suite.mockOrderStatusRepository.AssertExpectations(suite.T())
 }
+
+// Scenario: Price the order on the server
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithoutClientPrices_ShouldUseCatalogPrices() {
+	// GIVEN an order without client prices or total
+	products := []*dto.AddOrderProductDto{
+		{ProductId: 1, Quantity: 3},
+		{ProductId: 2, Quantity: 1},
+	}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("0"), products)
+
+	// AND the catalog prices
+	suite.givenCatalog([]uint{1, 2},
+		&clients.ProductDTO{ID: 1, Price: money.MustParse("34.99")},
+		&clients.ProductDTO{ID: 2, Price: money.MustParse("0.10")})
+
+	// THEN the order total should be computed from the catalog
+	suite.mockOrderRepository.EXPECT().
+		AddOrder(mock.Anything, mock.MatchedBy(func(order *entities.OrderEntity) bool {
+			return order.TotalAmount == money.MustParse("105.07")
+		})).
+		Return(&entities.OrderEntity{ID: 1}, nil).
+		Once()
+
+	// AND the catalog prices should be snapshotted into the order products
+	suite.mockOrderProductRepository.EXPECT().
+		AddOrderProduct(mock.Anything, mock.MatchedBy(func(product *entities.OrderProductEntity) bool {
+			return product.ProductId == 1 && product.Price == money.MustParse("34.99") && product.Quantity == 3
+		})).
+		Return(nil).
+		Once()
+	suite.mockOrderProductRepository.EXPECT().
+		AddOrderProduct(mock.Anything, mock.MatchedBy(func(product *entities.OrderProductEntity) bool {
+			return product.ProductId == 2 && product.Price == money.MustParse("0.10")
+		})).
+		Return(nil).
+		Once()
+
+	suite.mockOrderStatusRepository.EXPECT().
+		AddOrderStatus(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN the order is created
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the operation should complete without errors
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithDuplicatedProducts_ShouldFetchEachProductOnce() {
+	// GIVEN an order with the same product in two lines
+	products := []*dto.AddOrderProductDto{
+		{ProductId: 7, Quantity: 1},
+		{ProductId: 7, Quantity: 2},
+	}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("0"), products)
+
+	// THEN the catalog should be queried once per distinct product
+	suite.givenCatalog([]uint{7}, &clients.ProductDTO{ID: 7, Price: money.MustParse("5.00")})
+
+	suite.mockOrderRepository.EXPECT().
+		AddOrder(mock.Anything, mock.MatchedBy(func(order *entities.OrderEntity) bool {
+			return order.TotalAmount == money.MustParse("15.00")
+		})).
+		Return(&entities.OrderEntity{ID: 1}, nil).
+		Once()
+	suite.mockOrderProductRepository.EXPECT().
+		AddOrderProduct(mock.Anything, mock.Anything).
+		Return(nil).
+		Times(2)
+	suite.mockOrderStatusRepository.EXPECT().
+		AddOrderStatus(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN the order is created
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the operation should complete without errors
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithMismatchedPriceAndOverwritePolicy_ShouldUseCatalogPrice() {
+	// GIVEN a client price lower than the catalog price
+	products := []*dto.AddOrderProductDto{
+		{ProductId: 1, Quantity: 2, Price: money.MustParse("1.00")},
+	}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("2.00"), products)
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("20.00")})
+
+	// THEN the client values should be overwritten
+	suite.mockOrderRepository.EXPECT().
+		AddOrder(mock.Anything, mock.MatchedBy(func(order *entities.OrderEntity) bool {
+			return order.TotalAmount == money.MustParse("40.00")
+		})).
+		Return(&entities.OrderEntity{ID: 1}, nil).
+		Once()
+	suite.mockOrderProductRepository.EXPECT().
+		AddOrderProduct(mock.Anything, mock.MatchedBy(func(product *entities.OrderProductEntity) bool {
+			return product.Price == money.MustParse("20.00")
+		})).
+		Return(nil).
+		Once()
+	suite.mockOrderStatusRepository.EXPECT().
+		AddOrderStatus(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN the order is created
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the operation should complete without errors
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithMismatchedPriceAndRejectPolicy_ShouldReturnPriceMismatch() {
+	// GIVEN the reject policy
+	suite.settings.RejectPriceMismatch = true
+	suite.newUseCase()
+
+	// AND a client price lower than the catalog price
+	products := []*dto.AddOrderProductDto{
+		{ProductId: 1, Quantity: 1, Price: money.MustParse("1.00")},
+	}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("0"), products)
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("20.00")})
+
+	// WHEN the order creation is attempted
+	orderId, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN a price mismatch error should be returned
+	var mismatchErr *domainerrors.PriceMismatchError
+	assert.ErrorAs(suite.T(), err, &mismatchErr)
+	assert.Equal(suite.T(), uint(1), mismatchErr.ProductId)
+	assert.Equal(suite.T(), money.MustParse("20.00"), mismatchErr.Expected)
+	assert.Empty(suite.T(), orderId)
+	// AND nothing should be persisted
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do", mock.Anything, mock.Anything)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithMismatchedTotalAndRejectPolicy_ShouldReturnPriceMismatch() {
+	// GIVEN the reject policy
+	suite.settings.RejectPriceMismatch = true
+	suite.newUseCase()
+
+	// AND a client total that disagrees with the computed total
+	products := []*dto.AddOrderProductDto{
+		{ProductId: 1, Quantity: 2, Price: money.MustParse("20.00")},
+	}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("20.00"), products)
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("20.00")})
+
+	// WHEN the order creation is attempted
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN a total mismatch error should be returned
+	var mismatchErr *domainerrors.PriceMismatchError
+	assert.ErrorAs(suite.T(), err, &mismatchErr)
+	assert.Zero(suite.T(), mismatchErr.ProductId)
+	assert.Equal(suite.T(), money.MustParse("40.00"), mismatchErr.Expected)
+	suite.mockOrderRepository.AssertNotCalled(suite.T(), "AddOrder", mock.Anything, mock.Anything)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithFloatUnfriendlyPrices_ShouldComputeExactTotal() {
+	// GIVEN the reject policy
+	suite.settings.RejectPriceMismatch = true
+	suite.newUseCase()
+
+	// AND prices that accumulate rounding errors when summed as floats
+	products := []*dto.AddOrderProductDto{
+		{ProductId: 1, Quantity: 3, Price: money.MustParse("34.99")},
+		{ProductId: 2, Quantity: 1, Price: money.MustParse("0.10")},
+		{ProductId: 3, Quantity: 1, Price: money.MustParse("0.20")},
+	}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("105.27"), products)
+	suite.givenCatalog([]uint{1, 2, 3},
+		&clients.ProductDTO{ID: 1, Price: money.MustParse("34.99")},
+		&clients.ProductDTO{ID: 2, Price: money.MustParse("0.10")},
+		&clients.ProductDTO{ID: 3, Price: money.MustParse("0.20")})
+
+	// THEN the total should match the client total to the cent
+	suite.mockOrderRepository.EXPECT().
+		AddOrder(mock.Anything, mock.MatchedBy(func(order *entities.OrderEntity) bool {
+			return order.TotalAmount.Amount() == 10527
+		})).
+		Return(&entities.OrderEntity{ID: 1}, nil).
+		Once()
+	suite.mockOrderProductRepository.EXPECT().
+		AddOrderProduct(mock.Anything, mock.Anything).
+		Return(nil).
+		Times(3)
+	suite.mockOrderStatusRepository.EXPECT().
+		AddOrderStatus(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN the order is created
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN no price mismatch should be reported
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithUnknownProduct_ShouldReturnProductNotFound() {
+	// GIVEN a product that is missing from the catalog
+	products := []*dto.AddOrderProductDto{
+		{ProductId: 1, Quantity: 1},
+		{ProductId: 99, Quantity: 1},
+	}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("0"), products)
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1, 99}).
+		Return([]*clients.ProductDTO{{ID: 1, Price: money.MustParse("20.00")}}, &clients.ProductsError{
+			Errors: map[uint]error{99: fmt.Errorf("failed to fetch product 99: %w", clients.ErrProductNotFound)},
+		}).
+		Once()
+
+	// WHEN the order creation is attempted
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN a product not found error should be returned
+	var notFoundErr *domainerrors.ProductNotFoundError
+	assert.ErrorAs(suite.T(), err, &notFoundErr)
+	assert.Equal(suite.T(), uint(99), notFoundErr.ProductId)
+	suite.mockOrderRepository.AssertNotCalled(suite.T(), "AddOrder", mock.Anything, mock.Anything)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithTotalTooLarge_ShouldReturnValidationError() {
+	// GIVEN a quantity whose total does not fit in an amount of money
+	products := []*dto.AddOrderProductDto{{ProductId: 1, Quantity: 3}}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("0"), products)
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.FromMinor(math.MaxInt64 / 2)})
+
+	// WHEN the order creation is attempted
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN it should be rejected instead of wrapping around
+	var tooLargeErr *domainerrors.OrderTotalTooLargeError
+	assert.ErrorAs(suite.T(), err, &tooLargeErr)
+	assert.ErrorIs(suite.T(), err, money.ErrOverflow)
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrValidationFailed)
+	suite.mockOrderRepository.AssertNotCalled(suite.T(), "AddOrder", mock.Anything, mock.Anything)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithProductServiceFailure_ShouldReturnUpstreamUnavailable() {
+	// GIVEN the product service is unavailable
+	products := []*dto.AddOrderProductDto{
+		{ProductId: 1, Quantity: 1},
+	}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("0"), products)
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1}).
+		Return(nil, errors.New("connection refused")).
+		Once()
+
+	// WHEN the order creation is attempted
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN an upstream unavailable error should be returned
+	var upstreamErr *domainerrors.UpstreamUnavailableError
+	assert.ErrorAs(suite.T(), err, &upstreamErr)
+	assert.Equal(suite.T(), "product", upstreamErr.Service)
+	suite.mockOrderRepository.AssertNotCalled(suite.T(), "AddOrder", mock.Anything, mock.Anything)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithPartialProductServiceFailure_ShouldReturnUpstreamUnavailable() {
+	// GIVEN the product service fails for one of the products
+	products := []*dto.AddOrderProductDto{
+		{ProductId: 1, Quantity: 1},
+		{ProductId: 2, Quantity: 1},
+	}
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("0"), products)
+	suite.mockProductClient.EXPECT().
+		GetProducts(mock.Anything, []uint{1, 2}).
+		Return([]*clients.ProductDTO{{ID: 1, Price: money.MustParse("20.00")}}, &clients.ProductsError{
+			Errors: map[uint]error{2: errors.New("connection refused")},
+		}).
+		Once()
+
+	// WHEN the order creation is attempted
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN an upstream unavailable error should be returned instead of product not found
+	var upstreamErr *domainerrors.UpstreamUnavailableError
+	assert.ErrorAs(suite.T(), err, &upstreamErr)
+	assert.Equal(suite.T(), "product", upstreamErr.Service)
+	suite.mockOrderRepository.AssertNotCalled(suite.T(), "AddOrder", mock.Anything, mock.Anything)
+}
+
+// Scenario: Validate the customer with the customer service
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithUnknownCustomer_ShouldReturnCustomerNotFound() {
+	// GIVEN a customer unknown to the customer service
+	command := commands.NewAddOrderCommand(uintPtr(42), "", money.MustParse("0"), []*dto.AddOrderProductDto{{ProductId: 1, Quantity: 1}})
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(42)).
+		Return(nil, fmt.Errorf("failed to fetch customer 42: %w", clients.ErrCustomerNotFound)).
+		Once()
+
+	// WHEN the order creation is attempted
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN a customer not found error should be returned
+	var notFoundErr *domainerrors.CustomerNotFoundError
+	assert.ErrorAs(suite.T(), err, &notFoundErr)
+	assert.Equal(suite.T(), uint(42), notFoundErr.CustomerId)
+	// AND neither the catalog nor the repositories should be used
+	suite.mockProductClient.AssertNotCalled(suite.T(), "GetProducts", mock.Anything, mock.Anything)
+	suite.mockOrderRepository.AssertNotCalled(suite.T(), "AddOrder", mock.Anything, mock.Anything)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithCustomerServiceDownAndFailClosedPolicy_ShouldReturnUpstreamUnavailable() {
+	// GIVEN the customer service is unavailable
+	command := commands.NewAddOrderCommand(uintPtr(42), "", money.MustParse("0"), []*dto.AddOrderProductDto{{ProductId: 1, Quantity: 1}})
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(42)).
+		Return(nil, errors.New("connection refused")).
+		Once()
+
+	// WHEN the order creation is attempted
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN an upstream unavailable error should be returned
+	var upstreamErr *domainerrors.UpstreamUnavailableError
+	assert.ErrorAs(suite.T(), err, &upstreamErr)
+	assert.Equal(suite.T(), "customer", upstreamErr.Service)
+	suite.mockOrderRepository.AssertNotCalled(suite.T(), "AddOrder", mock.Anything, mock.Anything)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithCustomerServiceDownAndAcceptPolicy_ShouldFlagOrderForReconciliation() {
+	// GIVEN the accept policy
+	suite.settings.AcceptUnverifiedCustomers = true
+	suite.newUseCase()
+
+	// AND the customer service is unavailable
+	command := commands.NewAddOrderCommand(uintPtr(42), "", money.MustParse("0"), []*dto.AddOrderProductDto{{ProductId: 1, Quantity: 1}})
+	suite.mockCustomerClient.EXPECT().
+		GetCustomer(mock.Anything, uint(42)).
+		Return(nil, errors.New("connection refused")).
+		Once()
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("10.00")})
+
+	// THEN the order should be flagged for reconciliation
+	suite.mockOrderRepository.EXPECT().
+		AddOrder(mock.Anything, mock.MatchedBy(func(order *entities.OrderEntity) bool {
+			return order.CustomerId != nil && *order.CustomerId == 42 && order.CustomerReconciliationPending
+		})).
+		Return(&entities.OrderEntity{ID: 1}, nil).
+		Once()
+	suite.mockOrderProductRepository.EXPECT().
+		AddOrderProduct(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+	suite.mockOrderStatusRepository.EXPECT().
+		AddOrderStatus(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN the order is created
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the operation should complete without errors
+	assert.NoError(suite.T(), err)
+}
+
+// Scenario: Create guest orders without a customer
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithGuestOrder_ShouldSkipCustomerVerification() {
+	// GIVEN a guest order command without customer ID
+	command := commands.NewAddOrderCommand(nil, "Maria", money.MustParse("0"), []*dto.AddOrderProductDto{{ProductId: 1, Quantity: 1}})
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("10.00")})
+
+	// THEN the order should be stored without customer and with the guest name
+	suite.mockOrderRepository.EXPECT().
+		AddOrder(mock.Anything, mock.MatchedBy(func(order *entities.OrderEntity) bool {
+			return order.CustomerId == nil && order.GuestName == "Maria" && !order.CustomerReconciliationPending
+		})).
+		Return(&entities.OrderEntity{ID: 1}, nil).
+		Once()
+	suite.mockOrderProductRepository.EXPECT().
+		AddOrderProduct(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+	suite.mockOrderStatusRepository.EXPECT().
+		AddOrderStatus(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN the order is created
+	orderId, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the operation should complete without errors
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1", orderId)
+	// AND the customer service should not be called
+	suite.mockCustomerClient.AssertNotCalled(suite.T(), "GetCustomer", mock.Anything, mock.Anything)
+}
+
+// Feature: Add Order Use Case - Idempotency keys
+// Scenario: Retried requests replay the original order
+
+func (suite *AddOrderUseCaseTestSuite) givenIdempotentCommand(key string, requestHash string) *commands.AddOrderCommand {
+	command := commands.NewAddOrderCommand(uintPtr(1), "", money.MustParse("0"), []*dto.AddOrderProductDto{{ProductId: 1, Quantity: 1}})
+	command.IdempotencyKey = key
+	command.RequestHash = requestHash
+	return command
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithNewIdempotencyKey_ShouldStoreKeyWithOrder() {
+	// GIVEN a command with an idempotency key that was never used
+	command := suite.givenIdempotentCommand("kiosk-1", "hash-a")
+	suite.mockIdempotencyKeyRepo.EXPECT().
+		GetIdempotencyKey(mock.Anything, "kiosk-1", mock.Anything).
+		Return(nil, domainerrors.ErrIdempotencyKeyNotFound).
+		Once()
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("10.00")})
+
+	suite.mockOrderRepository.EXPECT().
+		AddOrder(mock.Anything, mock.Anything).
+		Return(&entities.OrderEntity{ID: 42}, nil).
+		Once()
+	suite.mockOrderProductRepository.EXPECT().
+		AddOrderProduct(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+	suite.mockOrderStatusRepository.EXPECT().
+		AddOrderStatus(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// THEN the key should be stored with the request hash, the order and the TTL
+	suite.mockIdempotencyKeyRepo.EXPECT().
+		AddIdempotencyKey(mock.Anything, mock.MatchedBy(func(key *entities.IdempotencyKeyEntity) bool {
+			return key.Key == "kiosk-1" &&
+				key.RequestHash == "hash-a" &&
+				key.OrderId == 42 &&
+				key.ExpiresAt.Sub(key.CreatedAt) == 24*time.Hour
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN the order is created
+	orderId, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the new order ID should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "42", orderId)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithReplayedIdempotencyKey_ShouldReturnOriginalOrder() {
+	// GIVEN a command whose key was already used with the same request
+	command := suite.givenIdempotentCommand("kiosk-1", "hash-a")
+	suite.mockIdempotencyKeyRepo.EXPECT().
+		GetIdempotencyKey(mock.Anything, "kiosk-1", mock.Anything).
+		Return(&entities.IdempotencyKeyEntity{Key: "kiosk-1", RequestHash: "hash-a", OrderId: 42}, nil).
+		Once()
+
+	// WHEN the order is created
+	orderId, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the original order ID should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "42", orderId)
+	// AND no new order should be created
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do", mock.Anything, mock.Anything)
+	suite.mockProductClient.AssertNotCalled(suite.T(), "GetProducts", mock.Anything, mock.Anything)
+	// AND the replay should not be counted as a new order
+	suite.assertOrdersCreated(0)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithIdempotencyKeyReusedForDifferentRequest_ShouldReturnReusedError() {
+	// GIVEN a command whose key was already used with a different request
+	command := suite.givenIdempotentCommand("kiosk-1", "hash-b")
+	suite.mockIdempotencyKeyRepo.EXPECT().
+		GetIdempotencyKey(mock.Anything, "kiosk-1", mock.Anything).
+		Return(&entities.IdempotencyKeyEntity{Key: "kiosk-1", RequestHash: "hash-a", OrderId: 42}, nil).
+		Once()
+
+	// WHEN the order is created
+	orderId, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the reuse should be rejected
+	var reusedErr *domainerrors.IdempotencyKeyReusedError
+	assert.ErrorAs(suite.T(), err, &reusedErr)
+	assert.Empty(suite.T(), orderId)
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do", mock.Anything, mock.Anything)
+}
+
+func (suite *AddOrderUseCaseTestSuite) Test_AddOrder_WithConcurrentIdempotencyKey_ShouldReplayWinningOrder() {
+	// GIVEN a command whose key is not stored yet
+	command := suite.givenIdempotentCommand("kiosk-1", "hash-a")
+	suite.mockIdempotencyKeyRepo.EXPECT().
+		GetIdempotencyKey(mock.Anything, "kiosk-1", mock.Anything).
+		Return(nil, domainerrors.ErrIdempotencyKeyNotFound).
+		Once()
+	suite.givenCatalog([]uint{1}, &clients.ProductDTO{ID: 1, Price: money.MustParse("10.00")})
+
+	suite.mockOrderRepository.EXPECT().
+		AddOrder(mock.Anything, mock.Anything).
+		Return(&entities.OrderEntity{ID: 43}, nil).
+		Once()
+	suite.mockOrderProductRepository.EXPECT().
+		AddOrderProduct(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+	suite.mockOrderStatusRepository.EXPECT().
+		AddOrderStatus(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// AND a concurrent request with the same key commits first
+	suite.mockIdempotencyKeyRepo.EXPECT().
+		AddIdempotencyKey(mock.Anything, mock.Anything).
+		Return(domainerrors.ErrIdempotencyKeyConflict).
+		Once()
+	suite.mockIdempotencyKeyRepo.EXPECT().
+		GetIdempotencyKey(mock.Anything, "kiosk-1", mock.Anything).
+		Return(&entities.IdempotencyKeyEntity{Key: "kiosk-1", RequestHash: "hash-a", OrderId: 42}, nil).
+		Once()
+
+	// WHEN the order is created
+	orderId, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the order of the winning request should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "42", orderId)
+}
@@ -0,0 +1,11 @@
+package cancelorder
+
+import (
+	"context"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+)
+
+type CancelOrderUseCase interface {
+	Execute(ctx context.Context, command *commands.CancelOrderCommand) error
+}
@@ -0,0 +1,58 @@
+package cancelorder
+
+import (
+	"context"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	_ CancelOrderUseCase = (*CancelOrderUseCaseImpl)(nil)
+)
+
+type CancelOrderUseCaseImpl struct {
+	orderStatusRepository repositories.OrderStatusRepository
+	recorder              usecase.OrderRecorder
+}
+
+func NewCancelOrderUseCaseImpl(orderStatusRepository repositories.OrderStatusRepository, recorder usecase.OrderRecorder) *CancelOrderUseCaseImpl {
+	return &CancelOrderUseCaseImpl{
+		orderStatusRepository: orderStatusRepository,
+		recorder:              recorder,
+	}
+}
+
+func (u *CancelOrderUseCaseImpl) Execute(ctx context.Context, command *commands.CancelOrderCommand) (err error) {
+	ctx, span := tracing.Start(ctx, "CancelOrderUseCase.Execute", trace.WithAttributes(attribute.Int64("order.id", int64(command.OrderId))))
+	defer func() { tracing.End(span, err) }()
+
+	// A missing status means the order does not exist (domainerrors.ErrOrderNotFound)
+	currentStatus, err := u.orderStatusRepository.GetOrderStatus(ctx, command.OrderId)
+	if err != nil {
+		return err
+	}
+
+	if err := entities.ValidateCancellation(currentStatus.CurrentStatus, command.ReasonCode, command.CancelledBy); err != nil {
+		return err
+	}
+
+	// Only recorded if no concurrent request changed the status meanwhile
+	err = u.orderStatusRepository.ChangeOrderStatus(ctx, &entities.OrderStatusEntity{
+		OrderId:       command.OrderId,
+		CurrentStatus: entities.OrderStatusCancelado,
+		ReasonCode:    command.ReasonCode,
+		ChangedBy:     command.CancelledBy,
+	}, currentStatus.CurrentStatus)
+	if err != nil {
+		return err
+	}
+
+	u.recorder.StatusChanged(currentStatus.CurrentStatus, entities.OrderStatusCancelado)
+	return nil
+}
@@ -0,0 +1,186 @@
+package cancelorder_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/metrics"
+	cancelorder "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/cancelOrder"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	mockRepositories "github.com/viniciuscluna/tc-fiap-50/mocks/order/domain/repositories"
+)
+
+type CancelOrderUseCaseTestSuite struct {
+	suite.Suite
+	mockOrderStatusRepository *mockRepositories.MockOrderStatusRepository
+	registry                  *prometheus.Registry
+	useCase                   cancelorder.CancelOrderUseCase
+}
+
+func (suite *CancelOrderUseCaseTestSuite) SetupTest() {
+	suite.mockOrderStatusRepository = mockRepositories.NewMockOrderStatusRepository(suite.T())
+	suite.registry = prometheus.NewRegistry()
+	suite.useCase = cancelorder.NewCancelOrderUseCaseImpl(suite.mockOrderStatusRepository, metrics.NewOrderMetrics(suite.registry))
+}
+
+func TestCancelOrderUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CancelOrderUseCaseTestSuite))
+}
+
+func (suite *CancelOrderUseCaseTestSuite) givenCurrentStatus(orderId uint, status uint) {
+	suite.mockOrderStatusRepository.EXPECT().
+		GetOrderStatus(mock.Anything, orderId).
+		Return(&entities.OrderStatusEntity{OrderId: orderId, CurrentStatus: status}, nil).
+		Once()
+}
+
+// Feature: Cancel Order Use Case
+// Scenario: Cancel an order before it is ready
+
+func (suite *CancelOrderUseCaseTestSuite) Test_CancelOrder_WhenRecebido_ShouldAddCanceladoStatusWithReasonAndAuthor() {
+	// GIVEN a "Recebido" order
+	orderId := uint(100)
+	command := commands.NewCancelOrderCommand(orderId, entities.CancellationReasonCustomerRequest, "kiosk-01")
+	suite.givenCurrentStatus(orderId, entities.OrderStatusRecebido)
+
+	suite.mockOrderStatusRepository.EXPECT().
+		ChangeOrderStatus(mock.Anything, mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
+			return status.OrderId == orderId &&
+				status.CurrentStatus == entities.OrderStatusCancelado &&
+				status.ReasonCode == entities.CancellationReasonCustomerRequest &&
+				status.ChangedBy == "kiosk-01"
+		}), entities.OrderStatusRecebido).
+		Return(nil).
+		Once()
+
+	// WHEN the order is cancelled
+	err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the operation should complete without errors
+	assert.NoError(suite.T(), err)
+	// AND a "Cancelado" status should be recorded with the reason and author
+	suite.mockOrderStatusRepository.AssertExpectations(suite.T())
+	// AND the transition should be counted
+	assert.NoError(suite.T(), testutil.GatherAndCompare(suite.registry, strings.NewReader(`
+# HELP order_status_transitions_total Order status changes, by previous and new status.
+# TYPE order_status_transitions_total counter
+order_status_transitions_total{from="recebido",to="cancelado"} 1
+`), "order_status_transitions_total"))
+}
+
+func (suite *CancelOrderUseCaseTestSuite) Test_CancelOrder_WhenEmPreparacao_ShouldCancel() {
+	// GIVEN an "Em preparação" order
+	orderId := uint(200)
+	command := commands.NewCancelOrderCommand(orderId, entities.CancellationReasonOutOfStock, "staff-7")
+	suite.givenCurrentStatus(orderId, entities.OrderStatusEmPreparacao)
+
+	suite.mockOrderStatusRepository.EXPECT().
+		ChangeOrderStatus(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN the order is cancelled
+	err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the operation should complete without errors
+	assert.NoError(suite.T(), err)
+}
+
+// Scenario: Refuse cancellation once the order is ready
+
+func (suite *CancelOrderUseCaseTestSuite) Test_CancelOrder_WhenProntoOrLater_ShouldReturnInvalidTransition() {
+	for _, status := range []uint{entities.OrderStatusPronto, entities.OrderStatusFinalizado, entities.OrderStatusCancelado} {
+		// GIVEN an order that is already "Pronto", "Finalizado" or "Cancelado"
+		orderId := uint(300) + status
+		command := commands.NewCancelOrderCommand(orderId, entities.CancellationReasonCustomerRequest, "kiosk-01")
+		suite.givenCurrentStatus(orderId, status)
+
+		// WHEN attempting to cancel it
+		err := suite.useCase.Execute(context.Background(), command)
+
+		// THEN an invalid transition error should be returned
+		var transitionErr *domainerrors.InvalidStatusTransitionError
+		assert.ErrorAs(suite.T(), err, &transitionErr)
+		assert.Equal(suite.T(), status, transitionErr.From)
+		assert.Equal(suite.T(), entities.OrderStatusCancelado, transitionErr.To)
+	}
+
+	// AND no status should be added
+	suite.mockOrderStatusRepository.AssertNotCalled(suite.T(), "ChangeOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Scenario: Require a known reason code and an author
+
+func (suite *CancelOrderUseCaseTestSuite) Test_CancelOrder_WithUnknownReason_ShouldReturnInvalidCancellationRequest() {
+	// GIVEN a cancellation with an unknown reason code
+	command := commands.NewCancelOrderCommand(400, "CHANGED_MIND", "kiosk-01")
+	suite.givenCurrentStatus(400, entities.OrderStatusRecebido)
+
+	// WHEN attempting to cancel the order
+	err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN an invalid cancellation request error should be returned
+	var requestErr *domainerrors.InvalidCancellationRequestError
+	assert.ErrorAs(suite.T(), err, &requestErr)
+	assert.Equal(suite.T(), "reasonCode", requestErr.Field)
+	suite.mockOrderStatusRepository.AssertNotCalled(suite.T(), "ChangeOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CancelOrderUseCaseTestSuite) Test_CancelOrder_WithoutAuthor_ShouldReturnInvalidCancellationRequest() {
+	// GIVEN a cancellation without author
+	command := commands.NewCancelOrderCommand(500, entities.CancellationReasonOther, "")
+	suite.givenCurrentStatus(500, entities.OrderStatusRecebido)
+
+	// WHEN attempting to cancel the order
+	err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN an invalid cancellation request error should be returned
+	var requestErr *domainerrors.InvalidCancellationRequestError
+	assert.ErrorAs(suite.T(), err, &requestErr)
+	assert.Equal(suite.T(), "cancelledBy", requestErr.Field)
+	suite.mockOrderStatusRepository.AssertNotCalled(suite.T(), "ChangeOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Scenario: Propagate lookup and persistence failures
+
+func (suite *CancelOrderUseCaseTestSuite) Test_CancelOrder_WithUnknownOrder_ShouldReturnNotFound() {
+	// GIVEN an order that does not exist
+	suite.mockOrderStatusRepository.EXPECT().
+		GetOrderStatus(mock.Anything, uint(9999)).
+		Return(nil, fmt.Errorf("%w: record not found", domainerrors.ErrOrderNotFound)).
+		Once()
+
+	// WHEN attempting to cancel it
+	err := suite.useCase.Execute(context.Background(), commands.NewCancelOrderCommand(9999, entities.CancellationReasonOther, "staff"))
+
+	// THEN an order not found error should be returned
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrOrderNotFound)
+}
+
+func (suite *CancelOrderUseCaseTestSuite) Test_CancelOrder_WithRepositoryError_ShouldReturnError() {
+	// GIVEN a cancellable order
+	suite.givenCurrentStatus(600, entities.OrderStatusRecebido)
+
+	// AND the repository fails to add the status
+	expectedError := errors.New("database connection error")
+	suite.mockOrderStatusRepository.EXPECT().
+		ChangeOrderStatus(mock.Anything, mock.Anything, mock.Anything).
+		Return(expectedError).
+		Once()
+
+	// WHEN the order is cancelled
+	err := suite.useCase.Execute(context.Background(), commands.NewCancelOrderCommand(600, entities.CancellationReasonPaymentFailed, "payment-service"))
+
+	// THEN the repository error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+}
@@ -1,16 +1,25 @@
 package commands
 
-import "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+import (
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+)
 
+// AddOrderCommand creates an order. A nil CustomerId denotes a guest order.
+// When IdempotencyKey is set, RequestHash identifies the request it was sent with.
 type AddOrderCommand struct {
-	CustomerId  uint
-	TotalAmount float32
-	Products    []*dto.AddOrderProductDto
+	CustomerId     *uint
+	GuestName      string
+	TotalAmount    money.Money
+	Products       []*dto.AddOrderProductDto
+	IdempotencyKey string
+	RequestHash    string
 }
 
-func NewAddOrderCommand(customerId uint, totalAmount float32, products []*dto.AddOrderProductDto) *AddOrderCommand {
+func NewAddOrderCommand(customerId *uint, guestName string, totalAmount money.Money, products []*dto.AddOrderProductDto) *AddOrderCommand {
 	return &AddOrderCommand{
 		CustomerId:  customerId,
+		GuestName:   guestName,
 		TotalAmount: totalAmount,
 		Products:    products,
 	}
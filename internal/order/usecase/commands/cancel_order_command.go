@@ -0,0 +1,15 @@
+package commands
+
+type CancelOrderCommand struct {
+	OrderId     uint
+	ReasonCode  string
+	CancelledBy string
+}
+
+func NewCancelOrderCommand(orderId uint, reasonCode string, cancelledBy string) *CancelOrderCommand {
+	return &CancelOrderCommand{
+		OrderId:     orderId,
+		ReasonCode:  reasonCode,
+		CancelledBy: cancelledBy,
+	}
+}
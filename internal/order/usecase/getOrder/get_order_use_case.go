@@ -1,10 +1,12 @@
 package getorder
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 )
 
 type GetOrderUseCase interface {
-	Execute(command *commands.GetOrderCommand) (*entities.OrderEntity, error)
+	Execute(ctx context.Context, command *commands.GetOrderCommand) (*entities.OrderEntity, error)
 }
@@ -1,9 +1,14 @@
 package getorder
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -18,8 +23,11 @@ func NewGetOrderUseCaseImpl(orderRepository repositories.OrderRepository) *GetOr
 	return &GetOrderUseCaseImpl{orderRepository: orderRepository}
 }
 
-func (u *GetOrderUseCaseImpl) Execute(command *commands.GetOrderCommand) (*entities.OrderEntity, error) {
-	order, err := u.orderRepository.GetOrder(command.OrderId)
+func (u *GetOrderUseCaseImpl) Execute(ctx context.Context, command *commands.GetOrderCommand) (_ *entities.OrderEntity, err error) {
+	ctx, span := tracing.Start(ctx, "GetOrderUseCase.Execute", trace.WithAttributes(attribute.Int64("order.id", int64(command.OrderId))))
+	defer func() { tracing.End(span, err) }()
+
+	order, err := u.orderRepository.GetOrder(ctx, command.OrderId)
 	if err != nil {
 		return nil, err
 	}
@@ -1,16 +1,23 @@
 package getorder_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 	getorder "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/getOrder"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing/tracingtest"
 	mockRepositories "github.com/viniciuscluna/tc-fiap-50/mocks/order/domain/repositories"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 type GetOrderUseCaseTestSuite struct {
@@ -28,6 +35,10 @@ func TestGetOrderUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(GetOrderUseCaseTestSuite))
 }
 
+func uintPtr(v uint) *uint {
+	return &v
+}
+
 // Feature: Get Order Use Case
 // Scenario: Retrieve an order by ID successfully
 
@@ -38,11 +49,11 @@ func (suite *GetOrderUseCaseTestSuite) Test_GetOrder_WithValidOrderId_ShouldRetu
 
 	expectedOrder := &entities.OrderEntity{
 		ID:          123,
-		CustomerId:  1,
-		TotalAmount: 100.50,
+		CustomerId:  uintPtr(1),
+		TotalAmount: money.MustParse("100.50"),
 		CreatedAt:   time.Now(),
 		Products: []*entities.OrderProductEntity{
-			{ID: 1, ProductId: 10, Quantity: 2, Price: 50.25},
+			{ID: 1, ProductId: 10, Quantity: 2, Price: money.MustParse("50.25")},
 		},
 		Status: []*entities.OrderStatusEntity{
 			{ID: 1, CurrentStatus: 2, OrderId: 123},
@@ -50,12 +61,12 @@ func (suite *GetOrderUseCaseTestSuite) Test_GetOrder_WithValidOrderId_ShouldRetu
 	}
 
 	suite.mockOrderRepository.EXPECT().
-		GetOrder(orderId).
+		GetOrder(mock.Anything, orderId).
 		Return(expectedOrder, nil).
 		Once()
 
 	// WHEN the order is retrieved
-	result, err := suite.useCase.Execute(command)
+	result, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -80,12 +91,12 @@ func (suite *GetOrderUseCaseTestSuite) Test_GetOrder_WithNonExistentOrderId_Shou
 	expectedError := errors.New("record not found")
 
 	suite.mockOrderRepository.EXPECT().
-		GetOrder(orderId).
+		GetOrder(mock.Anything, orderId).
 		Return(nil, expectedError).
 		Once()
 
 	// WHEN attempting to retrieve the order
-	result, err := suite.useCase.Execute(command)
+	result, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -105,12 +116,12 @@ func (suite *GetOrderUseCaseTestSuite) Test_GetOrder_WithRepositoryError_ShouldR
 	expectedError := errors.New("database connection error")
 
 	suite.mockOrderRepository.EXPECT().
-		GetOrder(orderId).
+		GetOrder(mock.Anything, orderId).
 		Return(nil, expectedError).
 		Once()
 
 	// WHEN attempting to retrieve the order
-	result, err := suite.useCase.Execute(command)
+	result, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -126,8 +137,8 @@ func (suite *GetOrderUseCaseTestSuite) Test_GetOrder_WithOrderWithoutProducts_Sh
 
 	expectedOrder := &entities.OrderEntity{
 		ID:          200,
-		CustomerId:  5,
-		TotalAmount: 0,
+		CustomerId:  uintPtr(5),
+		TotalAmount: money.MustParse("0"),
 		Products:    []*entities.OrderProductEntity{},
 		Status: []*entities.OrderStatusEntity{
 			{ID: 1, CurrentStatus: 1, OrderId: 200},
@@ -135,12 +146,12 @@ func (suite *GetOrderUseCaseTestSuite) Test_GetOrder_WithOrderWithoutProducts_Sh
 	}
 
 	suite.mockOrderRepository.EXPECT().
-		GetOrder(orderId).
+		GetOrder(mock.Anything, orderId).
 		Return(expectedOrder, nil).
 		Once()
 
 	// WHEN the order is retrieved
-	result, err := suite.useCase.Execute(command)
+	result, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -151,3 +162,30 @@ func (suite *GetOrderUseCaseTestSuite) Test_GetOrder_WithOrderWithoutProducts_Sh
 	assert.NotEmpty(suite.T(), result.Status)
 	suite.mockOrderRepository.AssertExpectations(suite.T())
 }
+
+// Scenario: Trace the use case
+
+func (suite *GetOrderUseCaseTestSuite) Test_GetOrder_WithRepositoryError_ShouldRecordFailedSpan() {
+	// GIVEN a traced request for an order
+	recorder := tracingtest.NewRecorder(suite.T())
+	orderId := uint(100)
+	command := commands.NewGetOrderCommand(orderId)
+
+	// AND a repository error occurs
+	expectedError := errors.New("database connection error")
+	suite.mockOrderRepository.EXPECT().
+		GetOrder(mock.Anything, orderId).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN attempting to retrieve the order
+	_, err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN the use case span should record the order and the failure
+	assert.Error(suite.T(), err)
+	span := tracingtest.SpanNamed(recorder, "GetOrderUseCase.Execute")
+	require.NotNil(suite.T(), span)
+	assert.Contains(suite.T(), span.Attributes(), attribute.Int64("order.id", 100))
+	assert.Equal(suite.T(), codes.Error, span.Status().Code)
+	assert.Equal(suite.T(), "database connection error", span.Status().Description)
+}
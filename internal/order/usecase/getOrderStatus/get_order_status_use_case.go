@@ -1,10 +1,12 @@
 package getorderstatus
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 )
 
 type GetOrderStatusUseCase interface {
-	Execute(command *commands.GetOrderStatusCommand) (*entities.OrderStatusEntity, error)
+	Execute(ctx context.Context, command *commands.GetOrderStatusCommand) (*entities.OrderStatusEntity, error)
 }
@@ -1,11 +1,13 @@
 package getorderstatus_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
@@ -44,12 +46,12 @@ func (suite *GetOrderStatusUseCaseTestSuite) Test_GetOrderStatus_WithValidOrderI
 	}
 
 	suite.mockOrderStatusRepository.EXPECT().
-		GetOrderStatus(orderId).
+		GetOrderStatus(mock.Anything, orderId).
 		Return(expectedStatus, nil).
 		Once()
 
 	// WHEN the order status is retrieved
-	result, err := suite.useCase.Execute(command)
+	result, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -69,12 +71,12 @@ func (suite *GetOrderStatusUseCaseTestSuite) Test_GetOrderStatus_WithNonExistent
 	expectedError := errors.New("record not found")
 
 	suite.mockOrderStatusRepository.EXPECT().
-		GetOrderStatus(orderId).
+		GetOrderStatus(mock.Anything, orderId).
 		Return(nil, expectedError).
 		Once()
 
 	// WHEN attempting to retrieve the status
-	result, err := suite.useCase.Execute(command)
+	result, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -94,12 +96,12 @@ func (suite *GetOrderStatusUseCaseTestSuite) Test_GetOrderStatus_WithRepositoryE
 	expectedError := errors.New("database connection error")
 
 	suite.mockOrderStatusRepository.EXPECT().
-		GetOrderStatus(orderId).
+		GetOrderStatus(mock.Anything, orderId).
 		Return(nil, expectedError).
 		Once()
 
 	// WHEN attempting to retrieve the status
-	result, err := suite.useCase.Execute(command)
+	result, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -121,12 +123,12 @@ func (suite *GetOrderStatusUseCaseTestSuite) Test_GetOrderStatus_WithRecebidoSta
 	}
 
 	suite.mockOrderStatusRepository.EXPECT().
-		GetOrderStatus(orderId).
+		GetOrderStatus(mock.Anything, orderId).
 		Return(expectedStatus, nil).
 		Once()
 
 	// WHEN the status is retrieved
-	result, err := suite.useCase.Execute(command)
+	result, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the status should be 1 (Recebido)
 	assert.NoError(suite.T(), err)
@@ -147,12 +149,12 @@ func (suite *GetOrderStatusUseCaseTestSuite) Test_GetOrderStatus_WithFinalizadoS
 	}
 
 	suite.mockOrderStatusRepository.EXPECT().
-		GetOrderStatus(orderId).
+		GetOrderStatus(mock.Anything, orderId).
 		Return(expectedStatus, nil).
 		Once()
 
 	// WHEN the status is retrieved
-	result, err := suite.useCase.Execute(command)
+	result, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the status should be 4 (Finalizado)
 	assert.NoError(suite.T(), err)
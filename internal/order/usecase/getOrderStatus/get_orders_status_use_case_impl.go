@@ -1,9 +1,14 @@
 package getorderstatus
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -18,8 +23,11 @@ func NewGetOrderStatusUseCaseImpl(orderStatusRepository repositories.OrderStatus
 	return &GetOrderStatusUseCaseImpl{orderStatusRepository: orderStatusRepository}
 }
 
-func (u *GetOrderStatusUseCaseImpl) Execute(command *commands.GetOrderStatusCommand) (*entities.OrderStatusEntity, error) {
-	orderStatus, err := u.orderStatusRepository.GetOrderStatus(command.OrderId)
+func (u *GetOrderStatusUseCaseImpl) Execute(ctx context.Context, command *commands.GetOrderStatusCommand) (_ *entities.OrderStatusEntity, err error) {
+	ctx, span := tracing.Start(ctx, "GetOrderStatusUseCase.Execute", trace.WithAttributes(attribute.Int64("order.id", int64(command.OrderId))))
+	defer func() { tracing.End(span, err) }()
+
+	orderStatus, err := u.orderStatusRepository.GetOrderStatus(ctx, command.OrderId)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,12 @@
 package getorders
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 )
 
 type GetOrdersUseCase interface {
-	Execute(command *commands.GetOrdersCommand) ([]*entities.OrderEntity, error)
+	Execute(ctx context.Context, command *commands.GetOrdersCommand) ([]*entities.OrderEntity, error)
 }
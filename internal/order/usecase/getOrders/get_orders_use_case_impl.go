@@ -1,9 +1,13 @@
 package getorders
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -18,11 +22,15 @@ func NewGetOrdersUseCaseImpl(orderRepository repositories.OrderRepository) *GetO
 	return &GetOrdersUseCaseImpl{orderRepository: orderRepository}
 }
 
-func (u *GetOrdersUseCaseImpl) Execute(command *commands.GetOrdersCommand) ([]*entities.OrderEntity, error) {
-	orders, err := u.orderRepository.GetOrders()
+func (u *GetOrdersUseCaseImpl) Execute(ctx context.Context, command *commands.GetOrdersCommand) (_ []*entities.OrderEntity, err error) {
+	ctx, span := tracing.Start(ctx, "GetOrdersUseCase.Execute")
+	defer func() { tracing.End(span, err) }()
+
+	orders, err := u.orderRepository.GetOrders(ctx)
 	if err != nil {
 		return nil, err
 	}
+	span.SetAttributes(attribute.Int("order.count", len(orders)))
 
 	return orders, nil
 }
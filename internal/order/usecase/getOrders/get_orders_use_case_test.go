@@ -1,15 +1,18 @@
 package getorders_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 	getorders "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/getOrders"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
 	mockRepositories "github.com/viniciuscluna/tc-fiap-50/mocks/order/domain/repositories"
 )
 
@@ -28,6 +31,10 @@ func TestGetOrdersUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(GetOrdersUseCaseTestSuite))
 }
 
+func uintPtr(v uint) *uint {
+	return &v
+}
+
 // Feature: Get Orders Use Case
 // Scenario: List all active orders successfully
 
@@ -38,34 +45,34 @@ func (suite *GetOrdersUseCaseTestSuite) Test_GetOrders_WithActiveOrders_ShouldRe
 	expectedOrders := []*entities.OrderEntity{
 		{
 			ID:          100,
-			CustomerId:  1,
-			TotalAmount: 50.00,
+			CustomerId:  uintPtr(1),
+			TotalAmount: money.MustParse("50.00"),
 			CreatedAt:   time.Now().Add(-2 * time.Hour),
 			Status:      []*entities.OrderStatusEntity{{CurrentStatus: 1}},
 		},
 		{
 			ID:          101,
-			CustomerId:  2,
-			TotalAmount: 75.00,
+			CustomerId:  uintPtr(2),
+			TotalAmount: money.MustParse("75.00"),
 			CreatedAt:   time.Now().Add(-1 * time.Hour),
 			Status:      []*entities.OrderStatusEntity{{CurrentStatus: 2}},
 		},
 		{
 			ID:          102,
-			CustomerId:  3,
-			TotalAmount: 100.00,
+			CustomerId:  uintPtr(3),
+			TotalAmount: money.MustParse("100.00"),
 			CreatedAt:   time.Now(),
 			Status:      []*entities.OrderStatusEntity{{CurrentStatus: 3}},
 		},
 	}
 
 	suite.mockOrderRepository.EXPECT().
-		GetOrders().
+		GetOrders(mock.Anything).
 		Return(expectedOrders, nil).
 		Once()
 
 	// WHEN all orders are retrieved
-	results, err := suite.useCase.Execute(command)
+	results, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -83,12 +90,12 @@ func (suite *GetOrdersUseCaseTestSuite) Test_GetOrders_WithNoOrders_ShouldReturn
 	command := commands.NewGetOrdersCommand()
 
 	suite.mockOrderRepository.EXPECT().
-		GetOrders().
+		GetOrders(mock.Anything).
 		Return([]*entities.OrderEntity{}, nil).
 		Once()
 
 	// WHEN all orders are retrieved
-	results, err := suite.useCase.Execute(command)
+	results, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -105,12 +112,12 @@ func (suite *GetOrdersUseCaseTestSuite) Test_GetOrders_WithRepositoryError_Shoul
 	expectedError := errors.New("database connection error")
 
 	suite.mockOrderRepository.EXPECT().
-		GetOrders().
+		GetOrders(mock.Anything).
 		Return(nil, expectedError).
 		Once()
 
 	// WHEN attempting to retrieve orders
-	results, err := suite.useCase.Execute(command)
+	results, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -126,18 +133,18 @@ func (suite *GetOrdersUseCaseTestSuite) Test_GetOrders_ShouldExcludeFinishedOrde
 	command := commands.NewGetOrdersCommand()
 
 	activeOrders := []*entities.OrderEntity{
-		{ID: 1, CustomerId: 1, Status: []*entities.OrderStatusEntity{{CurrentStatus: 1}}},
-		{ID: 2, CustomerId: 2, Status: []*entities.OrderStatusEntity{{CurrentStatus: 2}}},
-		{ID: 3, CustomerId: 3, Status: []*entities.OrderStatusEntity{{CurrentStatus: 3}}},
+		{ID: 1, CustomerId: uintPtr(1), Status: []*entities.OrderStatusEntity{{CurrentStatus: 1}}},
+		{ID: 2, CustomerId: uintPtr(2), Status: []*entities.OrderStatusEntity{{CurrentStatus: 2}}},
+		{ID: 3, CustomerId: uintPtr(3), Status: []*entities.OrderStatusEntity{{CurrentStatus: 3}}},
 	}
 
 	suite.mockOrderRepository.EXPECT().
-		GetOrders().
+		GetOrders(mock.Anything).
 		Return(activeOrders, nil).
 		Once()
 
 	// WHEN orders are retrieved
-	results, err := suite.useCase.Execute(command)
+	results, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN only active orders should be returned
 	assert.NoError(suite.T(), err)
@@ -158,22 +165,22 @@ func (suite *GetOrdersUseCaseTestSuite) Test_GetOrders_WithOrdersAndProducts_Sho
 	ordersWithProducts := []*entities.OrderEntity{
 		{
 			ID:          10,
-			CustomerId:  1,
-			TotalAmount: 100.00,
+			CustomerId:  uintPtr(1),
+			TotalAmount: money.MustParse("100.00"),
 			Products: []*entities.OrderProductEntity{
-				{ID: 1, ProductId: 5, Quantity: 2, Price: 50.00},
+				{ID: 1, ProductId: 5, Quantity: 2, Price: money.MustParse("50.00")},
 			},
 			Status: []*entities.OrderStatusEntity{{CurrentStatus: 1}},
 		},
 	}
 
 	suite.mockOrderRepository.EXPECT().
-		GetOrders().
+		GetOrders(mock.Anything).
 		Return(ordersWithProducts, nil).
 		Once()
 
 	// WHEN orders are retrieved
-	results, err := suite.useCase.Execute(command)
+	results, err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -0,0 +1,12 @@
+// Package usecase holds what the order use cases share with the adapters
+// providing their dependencies.
+package usecase
+
+// OrderRecorder records the order events of the use cases, such as for the
+// order metrics.
+type OrderRecorder interface {
+	// OrderCreated records a new order. Idempotent replays are not recorded.
+	OrderCreated()
+	// StatusChanged records an order moving from one status to another.
+	StatusChanged(from uint, to uint)
+}
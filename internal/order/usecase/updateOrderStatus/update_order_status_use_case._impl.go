@@ -1,9 +1,15 @@
 package updateorderstatus
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/repositories"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -12,22 +18,40 @@ var (
 
 type UpdateOrderStatusUseCaseImpl struct {
 	orderStatusRepository repositories.OrderStatusRepository
+	recorder              usecase.OrderRecorder
 }
 
-func NewUpdateOrderStatusUseCaseImpl(orderStatusRepository repositories.OrderStatusRepository) *UpdateOrderStatusUseCaseImpl {
+func NewUpdateOrderStatusUseCaseImpl(orderStatusRepository repositories.OrderStatusRepository, recorder usecase.OrderRecorder) *UpdateOrderStatusUseCaseImpl {
 	return &UpdateOrderStatusUseCaseImpl{
 		orderStatusRepository: orderStatusRepository,
+		recorder:              recorder,
 	}
 }
 
-func (u *UpdateOrderStatusUseCaseImpl) Execute(command *commands.UpdateOrderStatusCommand) error {
-	err := u.orderStatusRepository.AddOrderStatus(&entities.OrderStatusEntity{
+func (u *UpdateOrderStatusUseCaseImpl) Execute(ctx context.Context, command *commands.UpdateOrderStatusCommand) (err error) {
+	ctx, span := tracing.Start(ctx, "UpdateOrderStatusUseCase.Execute", trace.WithAttributes(attribute.Int64("order.id", int64(command.OrderId)), attribute.Int64("order.status", int64(command.Status))))
+	defer func() { tracing.End(span, err) }()
+
+	// Every order is created with an initial status, so a missing
+	// status means the order does not exist (domainerrors.ErrOrderNotFound)
+	currentStatus, err := u.orderStatusRepository.GetOrderStatus(ctx, command.OrderId)
+	if err != nil {
+		return err
+	}
+
+	if err := entities.ValidateStatusTransition(currentStatus.CurrentStatus, command.Status); err != nil {
+		return err
+	}
+
+	// Only recorded if no concurrent request changed the status meanwhile
+	err = u.orderStatusRepository.ChangeOrderStatus(ctx, &entities.OrderStatusEntity{
 		OrderId:       command.OrderId,
 		CurrentStatus: command.Status,
-	})
+	}, currentStatus.CurrentStatus)
 	if err != nil {
 		return err
 	}
 
+	u.recorder.StatusChanged(currentStatus.CurrentStatus, command.Status)
 	return nil
 }
@@ -1,9 +1,11 @@
 package updateorderstatus
 
 import (
+	"context"
+
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 )
 
 type UpdateOrderStatusUseCase interface {
-	Execute(command *commands.UpdateOrderStatusCommand) error
+	Execute(ctx context.Context, command *commands.UpdateOrderStatusCommand) error
 }
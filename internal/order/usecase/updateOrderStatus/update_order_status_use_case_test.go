@@ -1,13 +1,20 @@
 package updateorderstatus_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/domainerrors"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/metrics"
 	"github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 	updateorderstatus "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/updateOrderStatus"
 	mockRepositories "github.com/viniciuscluna/tc-fiap-50/mocks/order/domain/repositories"
@@ -16,18 +23,27 @@ import (
 type UpdateOrderStatusUseCaseTestSuite struct {
 	suite.Suite
 	mockOrderStatusRepository *mockRepositories.MockOrderStatusRepository
+	registry                  *prometheus.Registry
 	useCase                   updateorderstatus.UpdateOrderStatusUseCase
 }
 
 func (suite *UpdateOrderStatusUseCaseTestSuite) SetupTest() {
 	suite.mockOrderStatusRepository = mockRepositories.NewMockOrderStatusRepository(suite.T())
-	suite.useCase = updateorderstatus.NewUpdateOrderStatusUseCaseImpl(suite.mockOrderStatusRepository)
+	suite.registry = prometheus.NewRegistry()
+	suite.useCase = updateorderstatus.NewUpdateOrderStatusUseCaseImpl(suite.mockOrderStatusRepository, metrics.NewOrderMetrics(suite.registry))
 }
 
 func TestUpdateOrderStatusUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(UpdateOrderStatusUseCaseTestSuite))
 }
 
+func (suite *UpdateOrderStatusUseCaseTestSuite) givenCurrentStatus(orderId uint, status uint) {
+	suite.mockOrderStatusRepository.EXPECT().
+		GetOrderStatus(mock.Anything, orderId).
+		Return(&entities.OrderStatusEntity{OrderId: orderId, CurrentStatus: status}, nil).
+		Once()
+}
+
 // Feature: Update Order Status Use Case
 // Scenario: Update order status successfully
 
@@ -37,36 +53,46 @@ func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_WithValid
 	newStatus := uint(2)
 	command := commands.NewUpdateOrderStatusCommand(orderId, newStatus)
 
+	// AND the order is currently "Recebido"
+	suite.givenCurrentStatus(orderId, 1)
+
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
+		ChangeOrderStatus(mock.Anything, mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
 			return status.OrderId == orderId && status.CurrentStatus == newStatus
-		})).
+		}), uint(1)).
 		Return(nil).
 		Once()
 
 	// WHEN the order status is updated
-	err := suite.useCase.Execute(command)
+	err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
 	// AND the new status should have been added
 	suite.mockOrderStatusRepository.AssertExpectations(suite.T())
+	// AND the transition should be counted
+	assert.NoError(suite.T(), testutil.GatherAndCompare(suite.registry, strings.NewReader(`
+# HELP order_status_transitions_total Order status changes, by previous and new status.
+# TYPE order_status_transitions_total counter
+order_status_transitions_total{from="recebido",to="em_preparacao"} 1
+`), "order_status_transitions_total"))
 }
 
 func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_ToEmPreparacao_ShouldSetStatus2() {
-	// GIVEN an order transitioning to "Em preparação" (status 2)
+	// GIVEN a "Recebido" order transitioning to "Em preparação" (status 2)
 	orderId := uint(100)
 	command := commands.NewUpdateOrderStatusCommand(orderId, 2)
+	suite.givenCurrentStatus(orderId, 1)
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
+		ChangeOrderStatus(mock.Anything, mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
 			return status.OrderId == 100 && status.CurrentStatus == 2
-		})).
+		}), mock.Anything).
 		Return(nil).
 		Once()
 
 	// WHEN the status is updated
-	err := suite.useCase.Execute(command)
+	err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -74,19 +100,20 @@ func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_ToEmPrepa
 }
 
 func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_ToPronto_ShouldSetStatus3() {
-	// GIVEN an order transitioning to "Pronto" (status 3)
+	// GIVEN an "Em preparação" order transitioning to "Pronto" (status 3)
 	orderId := uint(200)
 	command := commands.NewUpdateOrderStatusCommand(orderId, 3)
+	suite.givenCurrentStatus(orderId, 2)
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
+		ChangeOrderStatus(mock.Anything, mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
 			return status.OrderId == 200 && status.CurrentStatus == 3
-		})).
+		}), mock.Anything).
 		Return(nil).
 		Once()
 
 	// WHEN the status is updated
-	err := suite.useCase.Execute(command)
+	err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -94,19 +121,20 @@ func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_ToPronto_
 }
 
 func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_ToFinalizado_ShouldSetStatus4() {
-	// GIVEN an order transitioning to "Finalizado" (status 4)
+	// GIVEN a "Pronto" order transitioning to "Finalizado" (status 4)
 	orderId := uint(300)
 	command := commands.NewUpdateOrderStatusCommand(orderId, 4)
+	suite.givenCurrentStatus(orderId, 3)
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
+		ChangeOrderStatus(mock.Anything, mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
 			return status.OrderId == 300 && status.CurrentStatus == 4
-		})).
+		}), mock.Anything).
 		Return(nil).
 		Once()
 
 	// WHEN the status is updated
-	err := suite.useCase.Execute(command)
+	err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN the operation should complete without errors
 	assert.NoError(suite.T(), err)
@@ -117,17 +145,18 @@ func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_WithRepos
 	// GIVEN a valid command
 	orderId := uint(400)
 	command := commands.NewUpdateOrderStatusCommand(orderId, 2)
+	suite.givenCurrentStatus(orderId, 1)
 
 	// AND the repository encounters an error
 	expectedError := errors.New("database connection error")
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.Anything).
+		ChangeOrderStatus(mock.Anything, mock.Anything, mock.Anything).
 		Return(expectedError).
 		Once()
 
 	// WHEN attempting to update the status
-	err := suite.useCase.Execute(command)
+	err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN an error should be returned
 	assert.Error(suite.T(), err)
@@ -136,42 +165,115 @@ func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_WithRepos
 	suite.mockOrderStatusRepository.AssertExpectations(suite.T())
 }
 
-func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_WithInvalidOrderId_ShouldReturnError() {
+func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_WithInvalidOrderId_ShouldReturnNotFound() {
 	// GIVEN a command with non-existent order ID
 	command := commands.NewUpdateOrderStatusCommand(9999, 2)
 
-	expectedError := errors.New("foreign key constraint failed")
-
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.Anything).
-		Return(expectedError).
+		GetOrderStatus(mock.Anything, uint(9999)).
+		Return(nil, fmt.Errorf("%w: record not found", domainerrors.ErrOrderNotFound)).
 		Once()
 
 	// WHEN attempting to update the status
-	err := suite.useCase.Execute(command)
+	err := suite.useCase.Execute(context.Background(), command)
 
-	// THEN an error should be returned
-	assert.Error(suite.T(), err)
-	assert.Equal(suite.T(), expectedError, err)
-	suite.mockOrderStatusRepository.AssertExpectations(suite.T())
+	// THEN an order not found error should be returned
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrOrderNotFound)
+	// AND no status should be added
+	suite.mockOrderStatusRepository.AssertNotCalled(suite.T(), "ChangeOrderStatus", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_ShouldCreateNewStatusRecord() {
 	// GIVEN an order with existing status history
 	orderId := uint(500)
 	command := commands.NewUpdateOrderStatusCommand(orderId, 3)
+	suite.givenCurrentStatus(orderId, 2)
 
 	suite.mockOrderStatusRepository.EXPECT().
-		AddOrderStatus(mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
+		ChangeOrderStatus(mock.Anything, mock.MatchedBy(func(status *entities.OrderStatusEntity) bool {
 			return status.OrderId == 500 && status.CurrentStatus == 3
-		})).
+		}), mock.Anything).
 		Return(nil).
 		Once()
 
 	// WHEN the status is updated
-	err := suite.useCase.Execute(command)
+	err := suite.useCase.Execute(context.Background(), command)
 
 	// THEN a new status record should be created (not updated)
 	assert.NoError(suite.T(), err)
 	suite.mockOrderStatusRepository.AssertExpectations(suite.T())
 }
+
+// Scenario: Reject transitions outside the order lifecycle
+
+func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_FromFinalizadoToRecebido_ShouldReturnInvalidTransition() {
+	// GIVEN a "Finalizado" order
+	orderId := uint(600)
+	command := commands.NewUpdateOrderStatusCommand(orderId, 1)
+	suite.givenCurrentStatus(orderId, 4)
+
+	// WHEN attempting to move it back to "Recebido"
+	err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN an invalid transition error should be returned
+	var transitionErr *domainerrors.InvalidStatusTransitionError
+	assert.ErrorAs(suite.T(), err, &transitionErr)
+	assert.Equal(suite.T(), uint(4), transitionErr.From)
+	assert.Equal(suite.T(), uint(1), transitionErr.To)
+	// AND no status should be added
+	suite.mockOrderStatusRepository.AssertNotCalled(suite.T(), "ChangeOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_SkippingSteps_ShouldReturnInvalidTransition() {
+	// GIVEN a "Recebido" order
+	orderId := uint(700)
+	command := commands.NewUpdateOrderStatusCommand(orderId, 3)
+	suite.givenCurrentStatus(orderId, 1)
+
+	// WHEN attempting to jump straight to "Pronto"
+	err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN an invalid transition error should be returned
+	var transitionErr *domainerrors.InvalidStatusTransitionError
+	assert.ErrorAs(suite.T(), err, &transitionErr)
+	suite.mockOrderStatusRepository.AssertNotCalled(suite.T(), "ChangeOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_WhenChangedConcurrently_ShouldReturnInvalidTransition() {
+	// GIVEN a "Recebido" order
+	orderId := uint(750)
+	command := commands.NewUpdateOrderStatusCommand(orderId, 2)
+	suite.givenCurrentStatus(orderId, 1)
+
+	// AND a concurrent request changes its status before this one is recorded
+	suite.mockOrderStatusRepository.EXPECT().
+		ChangeOrderStatus(mock.Anything, mock.Anything, uint(1)).
+		Return(&domainerrors.StatusChangedError{From: 1, To: 2}).
+		Once()
+
+	// WHEN the status is updated
+	err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN an invalid transition error should be returned
+	assert.ErrorIs(suite.T(), err, domainerrors.ErrInvalidTransition)
+	// AND no transition should be counted
+	count, gatherErr := testutil.GatherAndCount(suite.registry, "order_status_transitions_total")
+	assert.NoError(suite.T(), gatherErr)
+	assert.Zero(suite.T(), count)
+}
+
+func (suite *UpdateOrderStatusUseCaseTestSuite) Test_UpdateOrderStatus_WithUnknownStatus_ShouldReturnUnknownStatus() {
+	// GIVEN a command with a status outside the lifecycle
+	orderId := uint(800)
+	command := commands.NewUpdateOrderStatusCommand(orderId, 9)
+	suite.givenCurrentStatus(orderId, 1)
+
+	// WHEN attempting to update the status
+	err := suite.useCase.Execute(context.Background(), command)
+
+	// THEN an unknown status error should be returned
+	var unknownErr *domainerrors.UnknownStatusError
+	assert.ErrorAs(suite.T(), err, &unknownErr)
+	assert.Equal(suite.T(), uint(9), unknownErr.Status)
+	suite.mockOrderStatusRepository.AssertNotCalled(suite.T(), "ChangeOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
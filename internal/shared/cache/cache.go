@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Entry is a cached value, or the cached error when the lookup failed with an
+// error worth remembering (negative caching).
+type Entry[V any] struct {
+	Value V
+	Err   error
+}
+
+// Stats holds the cache counters since it was created.
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Stale     int64
+	Evictions int64
+}
+
+type item[K comparable, V any] struct {
+	key       K
+	entry     Entry[V]
+	expiresAt time.Time
+}
+
+// Cache is a size bounded LRU cache whose entries are fresh for a TTL. Expired
+// values are kept until evicted so they can still be served while the source
+// of truth is unavailable.
+type Cache[K comparable, V any] struct {
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxSize     int
+	now         func() time.Time
+
+	mu      sync.Mutex
+	items   map[K]*list.Element
+	recency *list.List
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	stale     atomic.Int64
+	evictions atomic.Int64
+}
+
+// New creates a cache holding at most maxSize entries. Values are fresh for
+// ttl and cached errors for negativeTTL.
+func New[K comparable, V any](ttl time.Duration, negativeTTL time.Duration, maxSize int) *Cache[K, V] {
+	return newWithClock[K, V](ttl, negativeTTL, maxSize, time.Now)
+}
+
+func newWithClock[K comparable, V any](ttl time.Duration, negativeTTL time.Duration, maxSize int, now func() time.Time) *Cache[K, V] {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+
+	return &Cache[K, V]{
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		maxSize:     maxSize,
+		now:         now,
+		items:       make(map[K]*list.Element),
+		recency:     list.New(),
+	}
+}
+
+// Get returns the fresh entry stored for key and counts a hit, or counts a miss.
+func (c *Cache[K, V]) Get(key K) (Entry[V], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.items[key]
+	if !exists || !c.now().Before(element.Value.(*item[K, V]).expiresAt) {
+		c.misses.Add(1)
+		return Entry[V]{}, false
+	}
+
+	c.recency.MoveToFront(element)
+	c.hits.Add(1)
+	return element.Value.(*item[K, V]).entry, true
+}
+
+// Stale returns the value stored for key even if it has expired, and counts
+// it as served stale. Cached errors are never returned.
+func (c *Cache[K, V]) Stale(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.items[key]
+	if !exists || element.Value.(*item[K, V]).entry.Err != nil {
+		var zero V
+		return zero, false
+	}
+
+	c.stale.Add(1)
+	return element.Value.(*item[K, V]).entry.Value, true
+}
+
+// Set stores value for key, fresh for the cache TTL.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.store(key, Entry[V]{Value: value}, c.ttl)
+}
+
+// SetError stores err for key, fresh for the cache negative TTL. A stale value
+// already stored for key is dropped, since the source said it's gone.
+func (c *Cache[K, V]) SetError(key K, err error) {
+	c.store(key, Entry[V]{Err: err}, c.negativeTTL)
+}
+
+// Stats returns the hit, miss, stale and eviction counters.
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Stale:     c.stale.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+// Len returns the number of entries, fresh or expired.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.recency.Len()
+}
+
+func (c *Cache[K, V]) store(key K, entry Entry[V], ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if element, exists := c.items[key]; exists {
+		element.Value = &item[K, V]{key: key, entry: entry, expiresAt: expiresAt}
+		c.recency.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.recency.PushFront(&item[K, V]{key: key, entry: entry, expiresAt: expiresAt})
+
+	// Evict the least recently used entries
+	for c.recency.Len() > c.maxSize {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.items, oldest.Value.(*item[K, V]).key)
+		c.evictions.Add(1)
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestCache(clock *fakeClock, maxSize int) *Cache[uint, string] {
+	return newWithClock[uint, string](time.Minute, 10*time.Second, maxSize, clock.Now)
+}
+
+// Feature: TTL cache
+// Scenario: Serve values while they are fresh
+func Test_Get_WithFreshValue_ShouldHit(t *testing.T) {
+	// GIVEN a cached value
+	clock := &fakeClock{now: time.Now()}
+	c := newTestCache(clock, 10)
+	c.Set(1, "burger")
+
+	// WHEN it is read before the TTL elapses
+	clock.now = clock.now.Add(59 * time.Second)
+	entry, found := c.Get(1)
+
+	// THEN the value should be returned as a hit
+	assert.True(t, found)
+	assert.Equal(t, "burger", entry.Value)
+	assert.NoError(t, entry.Err)
+	assert.Equal(t, Stats{Hits: 1}, c.Stats())
+}
+
+func Test_Get_WithExpiredValue_ShouldMissAndKeepItStale(t *testing.T) {
+	// GIVEN a cached value whose TTL elapsed
+	clock := &fakeClock{now: time.Now()}
+	c := newTestCache(clock, 10)
+	c.Set(1, "burger")
+	clock.now = clock.now.Add(time.Minute)
+
+	// WHEN it is read
+	_, found := c.Get(1)
+
+	// THEN it should be a miss
+	assert.False(t, found)
+	// AND the value should still be available as stale
+	value, found := c.Stale(1)
+	assert.True(t, found)
+	assert.Equal(t, "burger", value)
+	assert.Equal(t, Stats{Misses: 1, Stale: 1}, c.Stats())
+}
+
+// Scenario: Remember errors for the negative TTL
+func Test_SetError_ShouldBeCachedForNegativeTTL(t *testing.T) {
+	// GIVEN a cached not found error
+	clock := &fakeClock{now: time.Now()}
+	c := newTestCache(clock, 10)
+	notFound := errors.New("not found")
+	c.SetError(1, notFound)
+
+	// WHEN it is read within the negative TTL
+	entry, found := c.Get(1)
+
+	// THEN the error should be returned
+	assert.True(t, found)
+	assert.ErrorIs(t, entry.Err, notFound)
+
+	// AND it should expire after the negative TTL
+	clock.now = clock.now.Add(10 * time.Second)
+	_, found = c.Get(1)
+	assert.False(t, found)
+	// AND it should never be served as stale
+	_, found = c.Stale(1)
+	assert.False(t, found)
+}
+
+// Scenario: Bound the cache size
+func Test_Set_WithFullCache_ShouldEvictLeastRecentlyUsed(t *testing.T) {
+	// GIVEN a full cache
+	clock := &fakeClock{now: time.Now()}
+	c := newTestCache(clock, 2)
+	c.Set(1, "burger")
+	c.Set(2, "fries")
+
+	// AND the first entry was used recently
+	c.Get(1)
+
+	// WHEN a new entry is stored
+	c.Set(3, "soda")
+
+	// THEN the least recently used entry should be evicted
+	assert.Equal(t, 2, c.Len())
+	_, found := c.Get(2)
+	assert.False(t, found)
+	_, found = c.Get(1)
+	assert.True(t, found)
+	_, found = c.Get(3)
+	assert.True(t, found)
+	assert.Equal(t, int64(1), c.Stats().Evictions)
+}
@@ -0,0 +1,49 @@
+package cache
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// StatsSource is anything reporting cache counters, such as a Cache or a
+// client caching its responses.
+type StatsSource interface {
+	Stats() Stats
+}
+
+// collector reports the counters of named caches, read on every scrape.
+type collector struct {
+	caches    map[string]StatsSource
+	requests  *prometheus.Desc
+	evictions *prometheus.Desc
+}
+
+// NewCollector creates the collector of the cache_requests_total and
+// cache_evictions_total counters of the given caches, labelled by name.
+func NewCollector(caches map[string]StatsSource) prometheus.Collector {
+	return &collector{
+		caches: caches,
+		requests: prometheus.NewDesc(
+			"cache_requests_total",
+			"Cache lookups by result: hit, miss, or stale when an expired value was served because the source failed.",
+			[]string{"cache", "result"}, nil,
+		),
+		evictions: prometheus.NewDesc(
+			"cache_evictions_total",
+			"Entries evicted to keep the cache within its maximum size.",
+			[]string{"cache"}, nil,
+		),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.requests
+	ch <- c.evictions
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	for name, source := range c.caches {
+		stats := source.Stats()
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(stats.Hits), name, "hit")
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(stats.Misses), name, "miss")
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(stats.Stale), name, "stale")
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions), name)
+	}
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Feature: Cache metrics
+// Scenario: Expose the counters of each cache
+func Test_Collector_ShouldReportCountersByCache(t *testing.T) {
+	// GIVEN a cache of one entry with a hit, a miss, a stale serve and an eviction
+	clock := &fakeClock{now: time.Now()}
+	products := newTestCache(clock, 1)
+	products.Set(1, "burger")
+	products.Get(1)
+	clock.now = clock.now.Add(2 * time.Minute)
+	products.Get(1)
+	products.Stale(1)
+	products.Set(2, "fries")
+
+	// WHEN the collector is scraped
+	collector := NewCollector(map[string]StatsSource{"product": products})
+
+	// THEN each counter should be reported with the cache name
+	expected := `
+# HELP cache_evictions_total Entries evicted to keep the cache within its maximum size.
+# TYPE cache_evictions_total counter
+cache_evictions_total{cache="product"} 1
+# HELP cache_requests_total Cache lookups by result: hit, miss, or stale when an expired value was served because the source failed.
+# TYPE cache_requests_total counter
+cache_requests_total{cache="product",result="hit"} 1
+cache_requests_total{cache="product",result="miss"} 1
+cache_requests_total{cache="product",result="stale"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
@@ -1,77 +1,233 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"time"
 )
 
+// Config is the configuration of the service. Each setting is read, in
+// increasing precedence, from its default, the file named by CONFIG_FILE, the
+// environment variable of the same name or the secret file named by
+// <SETTING>_FILE.
 type Config struct {
 	// Server
-	ServerPort string
+	ServerPort              string
+	ServerReadTimeout       time.Duration
+	ServerReadHeaderTimeout time.Duration
+	ServerWriteTimeout      time.Duration
+	ServerIdleTimeout       time.Duration
+	ServerMaxHeaderBytes    int
+	ServerShutdownTimeout   time.Duration
 
-	// Database
+	// Health Checks
+	ReadinessCheckTimeout     time.Duration
+	ReadinessCacheTTL         time.Duration
+	CustomerServiceHealthPath string
+	ProductServiceHealthPath  string
+	ShutdownDrainDelay        time.Duration
+
+	// Logging
+	LogLevel string
+
+	// Tracing
+	TracingExporter     string
+	TracingServiceName  string
+	TracingOTLPEndpoint string
+	TracingSampleRatio  float64
+
+	// Storage
+	StorageDriver string
+	SQLitePath    string
+
+	// Database (postgres storage driver)
 	DBHost     string
 	DBPort     string
 	DBUser     string
-	DBPassword string
+	DBPassword Secret
 	DBName     string
 	DBSSLMode  string
 
+	// DBMigrationMode tells whether the service applies the pending
+	// migrations on start or only checks that there are none
+	DBMigrationMode string
+
 	// External Services
 	CustomerServiceURL string
 	ProductServiceURL  string
 
+	// Product Client
+	ProductServiceBatchPath     string
+	ProductClientMaxConcurrency int
+
+	// Client Cache
+	CustomerCacheTTL       time.Duration
+	ProductCacheTTL        time.Duration
+	ClientCacheNegativeTTL time.Duration
+	ClientCacheMaxSize     int
+
 	// HTTP Client
 	HTTPClientTimeout      time.Duration
 	HTTPClientRetryCount   int
 	HTTPClientRetryBackoff time.Duration
+	HTTPClientRetryMaxWait time.Duration
+	HTTPClientRetryBudget  float64
+
+	// Circuit Breaker (per downstream host)
+	CircuitBreakerConsecutiveFailures int
+	CircuitBreakerFailureRate         float64
+	CircuitBreakerWindowSize          int
+	CircuitBreakerMinRequests         int
+	CircuitBreakerCooldown            time.Duration
+
+	// Orders
+	OrderPriceMismatchPolicy         string
+	CustomerServiceUnavailablePolicy string
+	IdempotencyKeyTTL                time.Duration
 }
 
+const (
+	// PriceMismatchPolicyReject rejects orders whose client prices disagree with the catalog
+	PriceMismatchPolicyReject = "reject"
+	// PriceMismatchPolicyOverwrite replaces client prices with the catalog prices
+	PriceMismatchPolicyOverwrite = "overwrite"
+
+	// CustomerUnavailablePolicyFailClosed rejects orders when the customer service can't be reached
+	CustomerUnavailablePolicyFailClosed = "fail_closed"
+	// CustomerUnavailablePolicyAccept accepts the order and flags it for later reconciliation
+	CustomerUnavailablePolicyAccept = "accept"
+
+	// StorageDriverPostgres stores orders in PostgreSQL
+	StorageDriverPostgres = "postgres"
+	// StorageDriverSQLite stores orders in a SQLite file, for local development
+	StorageDriverSQLite = "sqlite"
+	// StorageDriverMemory keeps orders in memory, lost on restart
+	StorageDriverMemory = "memory"
+
+	// MigrationModeApply applies the pending migrations on start
+	MigrationModeApply = "apply"
+	// MigrationModeCheck refuses to start unless the migrations were applied beforehand
+	MigrationModeCheck = "check"
+)
+
+// configFileEnv names the environment variable holding the path of the
+// optional configuration file.
+const configFileEnv = "CONFIG_FILE"
+
+// Load reads the configuration, reporting every invalid setting at once.
 func Load() (*Config, error) {
+	return load(os.LookupEnv, os.ReadFile)
+}
+
+func load(lookupEnv func(string) (string, bool), readFile func(string) ([]byte, error)) (*Config, error) {
+	src, err := newSource(lookupEnv, readFile)
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		// Server
-		ServerPort: getEnv("SERVER_PORT", "8080"),
+		ServerPort:              src.getPort("SERVER_PORT", "8080"),
+		ServerReadTimeout:       src.getDuration("SERVER_READ_TIMEOUT_SECONDS", 15, time.Second, 1),
+		ServerReadHeaderTimeout: src.getDuration("SERVER_READ_HEADER_TIMEOUT_SECONDS", 5, time.Second, 1),
+		ServerWriteTimeout:      src.getDuration("SERVER_WRITE_TIMEOUT_SECONDS", 60, time.Second, 1),
+		ServerIdleTimeout:       src.getDuration("SERVER_IDLE_TIMEOUT_SECONDS", 120, time.Second, 1),
+		ServerMaxHeaderBytes:    src.getInt("SERVER_MAX_HEADER_BYTES", 64*1024, 1024, 16*1024*1024),
+		ServerShutdownTimeout:   src.getDuration("SERVER_SHUTDOWN_TIMEOUT_SECONDS", 20, time.Second, 1),
+
+		// Health Checks
+		ReadinessCheckTimeout:     src.getDuration("READINESS_CHECK_TIMEOUT_MS", 1000, time.Millisecond, 1),
+		ReadinessCacheTTL:         src.getDuration("READINESS_CACHE_TTL_MS", 2000, time.Millisecond, 0),
+		CustomerServiceHealthPath: src.getPath("CUSTOMER_SERVICE_HEALTH_PATH", ""),
+		ProductServiceHealthPath:  src.getPath("PRODUCT_SERVICE_HEALTH_PATH", ""),
+		ShutdownDrainDelay:        src.getDuration("SHUTDOWN_DRAIN_SECONDS", 5, time.Second, 0),
+
+		// Logging
+		LogLevel: src.getOneOf("LOG_LEVEL", "info", "debug", "info", "warn", "error"),
 
-		// Database
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", "postgres"),
-		DBName:     getEnv("DB_NAME", "order_db"),
-		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
+		// Tracing
+		TracingExporter:     src.getOneOf("TRACING_EXPORTER", "none", "otlp", "stdout", "none"),
+		TracingServiceName:  src.getString("TRACING_SERVICE_NAME", "order-service"),
+		TracingOTLPEndpoint: src.getURL("TRACING_OTLP_ENDPOINT", ""),
+		TracingSampleRatio:  src.getPercent("TRACING_SAMPLE_PERCENT", 100),
+
+		// Storage
+		StorageDriver: src.getOneOf("STORAGE_DRIVER", StorageDriverPostgres, StorageDriverPostgres, StorageDriverSQLite, StorageDriverMemory),
+		SQLitePath:    src.getString("SQLITE_PATH", "order.db"),
+
+		// Database (postgres storage driver), the connection has no defaults
+		DBHost:     src.getString("DB_HOST", ""),
+		DBPort:     src.getPort("DB_PORT", "5432"),
+		DBUser:     src.getString("DB_USER", ""),
+		DBPassword: src.getSecret("DB_PASSWORD", ""),
+		DBName:     src.getString("DB_NAME", "order_db"),
+		DBSSLMode:  src.getOneOf("DB_SSLMODE", "disable", "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+
+		DBMigrationMode: src.getOneOf("DB_MIGRATION_MODE", MigrationModeApply, MigrationModeApply, MigrationModeCheck),
 
 		// External Services
-		CustomerServiceURL: getEnv("CUSTOMER_SERVICE_URL", "http://localhost:8081"),
-		ProductServiceURL:  getEnv("PRODUCT_SERVICE_URL", "http://localhost:8082"),
+		CustomerServiceURL: src.getURL("CUSTOMER_SERVICE_URL", "http://localhost:8081"),
+		ProductServiceURL:  src.getURL("PRODUCT_SERVICE_URL", "http://localhost:8082"),
+
+		// Product Client
+		ProductServiceBatchPath:     src.getPath("PRODUCT_SERVICE_BATCH_PATH", ""),
+		ProductClientMaxConcurrency: src.getInt("PRODUCT_CLIENT_MAX_CONCURRENCY", 8, 1, 1000),
+
+		// Client Cache
+		CustomerCacheTTL:       src.getDuration("CUSTOMER_CACHE_TTL_SECONDS", 60, time.Second, 0),
+		ProductCacheTTL:        src.getDuration("PRODUCT_CACHE_TTL_SECONDS", 300, time.Second, 0),
+		ClientCacheNegativeTTL: src.getDuration("CLIENT_CACHE_NEGATIVE_TTL_SECONDS", 30, time.Second, 0),
+		ClientCacheMaxSize:     src.getInt("CLIENT_CACHE_MAX_SIZE", 1000, 1, maxInt),
 
 		// HTTP Client
-		HTTPClientTimeout:      time.Duration(getEnvAsInt("HTTP_CLIENT_TIMEOUT_SECONDS", 30)) * time.Second,
-		HTTPClientRetryCount:   getEnvAsInt("HTTP_CLIENT_RETRY_COUNT", 3),
-		HTTPClientRetryBackoff: time.Duration(getEnvAsInt("HTTP_CLIENT_RETRY_BACKOFF_MS", 100)) * time.Millisecond,
+		HTTPClientTimeout:      src.getDuration("HTTP_CLIENT_TIMEOUT_SECONDS", 30, time.Second, 1),
+		HTTPClientRetryCount:   src.getInt("HTTP_CLIENT_RETRY_COUNT", 3, 0, 10),
+		HTTPClientRetryBackoff: src.getDuration("HTTP_CLIENT_RETRY_BACKOFF_MS", 100, time.Millisecond, 1),
+		HTTPClientRetryMaxWait: src.getDuration("HTTP_CLIENT_RETRY_MAX_BACKOFF_MS", 2000, time.Millisecond, 1),
+		HTTPClientRetryBudget:  src.getPercent("HTTP_CLIENT_RETRY_BUDGET_PERCENT", 20),
+
+		// Circuit Breaker (per downstream host)
+		CircuitBreakerConsecutiveFailures: src.getInt("CIRCUIT_BREAKER_CONSECUTIVE_FAILURES", 5, 1, maxInt),
+		CircuitBreakerFailureRate:         src.getPercent("CIRCUIT_BREAKER_FAILURE_RATE_PERCENT", 50),
+		CircuitBreakerWindowSize:          src.getInt("CIRCUIT_BREAKER_WINDOW_SIZE", 20, 1, 10000),
+		CircuitBreakerMinRequests:         src.getInt("CIRCUIT_BREAKER_MIN_REQUESTS", 10, 1, 10000),
+		CircuitBreakerCooldown:            src.getDuration("CIRCUIT_BREAKER_COOLDOWN_SECONDS", 30, time.Second, 1),
+
+		// Orders
+		OrderPriceMismatchPolicy:         src.getOneOf("ORDER_PRICE_MISMATCH_POLICY", PriceMismatchPolicyOverwrite, PriceMismatchPolicyReject, PriceMismatchPolicyOverwrite),
+		CustomerServiceUnavailablePolicy: src.getOneOf("CUSTOMER_SERVICE_UNAVAILABLE_POLICY", CustomerUnavailablePolicyFailClosed, CustomerUnavailablePolicyFailClosed, CustomerUnavailablePolicyAccept),
+		IdempotencyKeyTTL:                src.getDuration("IDEMPOTENCY_KEY_TTL_HOURS", 24, time.Hour, 1),
 	}
 
+	errs := append(src.errs, src.unknownFileKeys()...)
+	errs = append(errs, config.validate()...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
 	return config, nil
 }
 
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+// validate checks the rules involving more than one setting.
+func (c *Config) validate() []error {
+	var errs []error
+	if c.StorageDriver == StorageDriverPostgres {
+		for _, setting := range []struct{ key, value string }{
+			{"DB_HOST", c.DBHost}, {"DB_USER", c.DBUser}, {"DB_PASSWORD", c.DBPassword.Value()},
+		} {
+			if setting.value == "" {
+				errs = append(errs, fmt.Errorf("%s: required with STORAGE_DRIVER=postgres", setting.key))
+			}
+		}
 	}
-	return value
-}
-
-func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := os.Getenv(key)
-	if valueStr == "" {
-		return defaultValue
+	if c.ServerReadHeaderTimeout > c.ServerReadTimeout {
+		errs = append(errs, errors.New("SERVER_READ_HEADER_TIMEOUT_SECONDS must not exceed SERVER_READ_TIMEOUT_SECONDS"))
 	}
-	value, err := strconv.Atoi(valueStr)
-	if err != nil {
-		fmt.Printf("Warning: invalid integer for %s, using default %d\n", key, defaultValue)
-		return defaultValue
+	if c.HTTPClientRetryBackoff > c.HTTPClientRetryMaxWait {
+		errs = append(errs, errors.New("HTTP_CLIENT_RETRY_BACKOFF_MS must not exceed HTTP_CLIENT_RETRY_MAX_BACKOFF_MS"))
+	}
+	if c.CircuitBreakerMinRequests > c.CircuitBreakerWindowSize {
+		errs = append(errs, errors.New("CIRCUIT_BREAKER_MIN_REQUESTS must not exceed CIRCUIT_BREAKER_WINDOW_SIZE"))
 	}
-	return value
+	return errs
 }
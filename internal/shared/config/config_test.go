@@ -0,0 +1,234 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEnv is an environment and a file system held in memory.
+type fakeEnv struct {
+	vars  map[string]string
+	files map[string]string
+}
+
+func (e fakeEnv) lookupEnv(key string) (string, bool) {
+	value, ok := e.vars[key]
+	return value, ok
+}
+
+func (e fakeEnv) readFile(path string) ([]byte, error) {
+	content, ok := e.files[path]
+	if !ok {
+		return nil, fmt.Errorf("open %s: %w", path, fs.ErrNotExist)
+	}
+	return []byte(content), nil
+}
+
+func (e fakeEnv) load() (*Config, error) {
+	return load(e.lookupEnv, e.readFile)
+}
+
+// withDatabase adds to vars the database connection settings, which have no
+// defaults with the postgres storage driver.
+func withDatabase(vars map[string]string) map[string]string {
+	settings := map[string]string{"DB_HOST": "localhost", "DB_USER": "order_user", "DB_PASSWORD": "order_pass"}
+	for key, value := range vars {
+		settings[key] = value
+	}
+	return settings
+}
+
+// Feature: Configuration loading
+// Scenario: Fall back to the defaults
+func Test_Load_WithoutSettings_ShouldUseDefaults(t *testing.T) {
+	// GIVEN an environment with only the database connection
+	env := fakeEnv{vars: withDatabase(nil)}
+
+	// WHEN the configuration is loaded
+	cfg, err := env.load()
+
+	// THEN every other setting should have its default
+	require.NoError(t, err)
+	assert.Equal(t, "8080", cfg.ServerPort)
+	assert.Equal(t, 15*time.Second, cfg.ServerReadTimeout)
+	assert.Equal(t, "5432", cfg.DBPort)
+	assert.Equal(t, "order_db", cfg.DBName)
+	assert.Equal(t, 100*time.Millisecond, cfg.HTTPClientRetryBackoff)
+	assert.Equal(t, 0.2, cfg.HTTPClientRetryBudget)
+	assert.Equal(t, CustomerUnavailablePolicyFailClosed, cfg.CustomerServiceUnavailablePolicy)
+}
+
+func Test_Load_WithPostgresWithoutDatabaseConnection_ShouldFail(t *testing.T) {
+	// GIVEN the postgres storage driver without the database password
+	env := fakeEnv{vars: map[string]string{"DB_HOST": "db.internal", "DB_USER": "order_user"}}
+
+	// WHEN the configuration is loaded
+	_, err := env.load()
+
+	// THEN the missing password should be reported
+	assert.ErrorContains(t, err, "DB_PASSWORD: required with STORAGE_DRIVER=postgres")
+	assert.NotContains(t, err.Error(), "DB_HOST")
+}
+
+func Test_Load_WithoutPostgres_ShouldNotRequireDatabaseConnection(t *testing.T) {
+	// GIVEN the memory storage driver and no database settings
+	env := fakeEnv{vars: map[string]string{"STORAGE_DRIVER": StorageDriverMemory}}
+
+	// WHEN the configuration is loaded
+	cfg, err := env.load()
+
+	// THEN it should be valid
+	require.NoError(t, err)
+	assert.Empty(t, cfg.DBPassword.Value())
+}
+
+// Scenario: Layer the file, the environment and the secret files
+func Test_Load_WithYAMLFile_ShouldBeOverriddenByEnvironment(t *testing.T) {
+	// GIVEN a YAML file and an environment variable setting the same key
+	env := fakeEnv{
+		vars: withDatabase(map[string]string{"CONFIG_FILE": "/etc/order/config.yaml", "DB_HOST": "db.internal"}),
+		files: map[string]string{"/etc/order/config.yaml": `
+DB_HOST: db.from-file
+DB_NAME: orders
+HTTP_CLIENT_RETRY_COUNT: 5
+`},
+	}
+
+	// WHEN the configuration is loaded
+	cfg, err := env.load()
+
+	// THEN the environment should win over the file, which wins over the defaults
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal", cfg.DBHost)
+	assert.Equal(t, "orders", cfg.DBName)
+	assert.Equal(t, 5, cfg.HTTPClientRetryCount)
+}
+
+func Test_Load_WithJSONFile_ShouldReadNumbersAsWritten(t *testing.T) {
+	// GIVEN a JSON file with a large integer
+	env := fakeEnv{
+		vars:  withDatabase(map[string]string{"CONFIG_FILE": "config.json"}),
+		files: map[string]string{"config.json": `{"CLIENT_CACHE_MAX_SIZE": 5000000, "LOG_LEVEL": "debug"}`},
+	}
+
+	// WHEN the configuration is loaded
+	cfg, err := env.load()
+
+	// THEN the values should be read as written
+	require.NoError(t, err)
+	assert.Equal(t, 5000000, cfg.ClientCacheMaxSize)
+	assert.Equal(t, "debug", cfg.LogLevel)
+}
+
+func Test_Load_WithSecretFile_ShouldReadItWithoutTrailingNewline(t *testing.T) {
+	// GIVEN the database password mounted as a Kubernetes secret
+	env := fakeEnv{
+		vars:  map[string]string{"DB_HOST": "localhost", "DB_USER": "order_user", "DB_PASSWORD_FILE": "/var/run/secrets/db/password"},
+		files: map[string]string{"/var/run/secrets/db/password": "s3cr3t\n"},
+	}
+
+	// WHEN the configuration is loaded
+	cfg, err := env.load()
+
+	// THEN the password should be the content of the file
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", cfg.DBPassword.Value())
+}
+
+func Test_Load_WithSettingAndSecretFile_ShouldFail(t *testing.T) {
+	// GIVEN the password set both directly and as a file
+	env := fakeEnv{
+		vars:  map[string]string{"DB_PASSWORD": "plain", "DB_PASSWORD_FILE": "/secret"},
+		files: map[string]string{"/secret": "s3cr3t"},
+	}
+
+	// WHEN the configuration is loaded
+	_, err := env.load()
+
+	// THEN the ambiguity should be reported
+	assert.ErrorContains(t, err, "DB_PASSWORD: set either DB_PASSWORD or DB_PASSWORD_FILE, not both")
+}
+
+// Scenario: Report every invalid setting
+func Test_Load_WithInvalidSettings_ShouldReportThemAll(t *testing.T) {
+	// GIVEN several invalid settings, one of them in the file
+	env := fakeEnv{
+		vars: map[string]string{
+			"CONFIG_FILE":                      "config.yaml",
+			"SERVER_PORT":                      "http",
+			"HTTP_CLIENT_RETRY_COUNT":          "11",
+			"TRACING_EXPORTER":                 "jaeger",
+			"HTTP_CLIENT_RETRY_BACKOFF_MS":     "5000",
+			"HTTP_CLIENT_RETRY_MAX_BACKOFF_MS": "1000",
+		},
+		files: map[string]string{"config.yaml": "CUSTOMER_SERVICE_URL: customer-service:8081\nDB_HOTS: typo\n"},
+	}
+
+	// WHEN the configuration is loaded
+	_, err := env.load()
+
+	// THEN each problem should be reported at once
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `SERVER_PORT: invalid port "http"`)
+	assert.ErrorContains(t, err, "HTTP_CLIENT_RETRY_COUNT: must be between 0 and 10, got 11")
+	assert.ErrorContains(t, err, `TRACING_EXPORTER: invalid value "jaeger" (expected otlp, stdout, none)`)
+	assert.ErrorContains(t, err, `CUSTOMER_SERVICE_URL: invalid URL "customer-service:8081"`)
+	assert.ErrorContains(t, err, "DB_HOTS: unknown setting in CONFIG_FILE")
+	assert.ErrorContains(t, err, "HTTP_CLIENT_RETRY_BACKOFF_MS must not exceed HTTP_CLIENT_RETRY_MAX_BACKOFF_MS")
+}
+
+func Test_Load_WithMissingConfigFile_ShouldFail(t *testing.T) {
+	// GIVEN a configuration file that doesn't exist
+	env := fakeEnv{vars: map[string]string{"CONFIG_FILE": "missing.yaml"}}
+
+	// WHEN the configuration is loaded
+	_, err := env.load()
+
+	// THEN the file should be reported
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.ErrorContains(t, err, "CONFIG_FILE")
+}
+
+// Scenario: Choose the storage driver
+func Test_Load_WithStorageDriver_ShouldAcceptKnownDrivers(t *testing.T) {
+	for _, driver := range []string{StorageDriverPostgres, StorageDriverSQLite, StorageDriverMemory} {
+		// GIVEN a known storage driver
+		env := fakeEnv{vars: withDatabase(map[string]string{"STORAGE_DRIVER": driver})}
+
+		// WHEN the configuration is loaded
+		cfg, err := env.load()
+
+		// THEN it should be selected
+		require.NoError(t, err)
+		assert.Equal(t, driver, cfg.StorageDriver)
+	}
+
+	// AND an unknown one should be rejected
+	_, err := fakeEnv{vars: map[string]string{"STORAGE_DRIVER": "mysql"}}.load()
+	assert.ErrorContains(t, err, `STORAGE_DRIVER: invalid value "mysql" (expected postgres, sqlite, memory)`)
+}
+
+// Feature: Secret redaction
+func Test_Config_WhenPrinted_ShouldRedactSecrets(t *testing.T) {
+	// GIVEN a configuration with a database password
+	env := fakeEnv{vars: withDatabase(map[string]string{"DB_PASSWORD": "s3cr3t"})}
+	cfg, err := env.load()
+	require.NoError(t, err)
+
+	// WHEN it is printed and encoded
+	printed := fmt.Sprintf("%+v %#v %s", cfg, cfg, cfg.DBPassword)
+	encoded, err := json.Marshal(cfg)
+	require.NoError(t, err)
+
+	// THEN the password should never show
+	assert.NotContains(t, printed, "s3cr3t")
+	assert.Contains(t, printed, "[REDACTED]")
+	assert.NotContains(t, string(encoded), "s3cr3t")
+	assert.Contains(t, string(encoded), `"DBPassword":"[REDACTED]"`)
+}
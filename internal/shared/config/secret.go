@@ -0,0 +1,29 @@
+package config
+
+import "encoding/json"
+
+const redacted = "[REDACTED]"
+
+// Secret is a setting that must not leak, such as a password. It is printed
+// as [REDACTED] by fmt, in JSON and in logs; Value returns the actual value.
+type Secret string
+
+// Value returns the secret itself.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const maxInt = math.MaxInt
+
+// source resolves the settings from the configuration file, the environment
+// and the secret files, collecting the invalid ones instead of stopping at the
+// first.
+type source struct {
+	lookupEnv func(string) (string, bool)
+	readFile  func(string) ([]byte, error)
+
+	// file holds the settings of the configuration file, if any
+	file map[string]string
+	// known records the settings read, to spot unknown keys in the file
+	known map[string]bool
+
+	errs []error
+}
+
+func newSource(lookupEnv func(string) (string, bool), readFile func(string) ([]byte, error)) (*source, error) {
+	src := &source{
+		lookupEnv: lookupEnv,
+		readFile:  readFile,
+		file:      map[string]string{},
+		known:     map[string]bool{},
+	}
+
+	if path, ok := lookupEnv(configFileEnv); ok && path != "" {
+		content, err := readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", configFileEnv, err)
+		}
+		if src.file, err = parseConfigFile(path, content); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", configFileEnv, path, err)
+		}
+	}
+
+	return src, nil
+}
+
+// parseConfigFile reads a flat YAML or JSON document whose keys are the names
+// of the environment variables, e.g. {"DB_HOST": "localhost", "DB_PORT": 5432}.
+func parseConfigFile(path string, content []byte) (map[string]string, error) {
+	var document map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &document); err != nil {
+			return nil, err
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		// Keep numbers as written so integers don't turn into 5e+06
+		decoder.UseNumber()
+		if err := decoder.Decode(&document); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q (expected .yaml, .yml or .json)", filepath.Ext(path))
+	}
+
+	settings := make(map[string]string, len(document))
+	for key, value := range document {
+		switch value := value.(type) {
+		case nil:
+			settings[key] = ""
+		case string, bool, int, float64, json.Number:
+			settings[key] = fmt.Sprint(value)
+		default:
+			return nil, fmt.Errorf("%s: only strings, numbers and booleans are supported", key)
+		}
+	}
+	return settings, nil
+}
+
+// lookup returns the value of key from its secret file, the environment or the
+// configuration file, in that order. Empty environment variables are ignored.
+func (s *source) lookup(key string) (string, bool) {
+	s.known[key] = true
+
+	value, _ := s.lookupEnv(key)
+	path, _ := s.lookupEnv(key + "_FILE")
+	switch {
+	case value != "" && path != "":
+		s.errorf("%s: set either %s or %s_FILE, not both", key, key, key)
+		return "", false
+	case path != "":
+		content, err := s.readFile(path)
+		if err != nil {
+			s.errorf("%s_FILE: %v", key, err)
+			return "", false
+		}
+		// Secret files usually end with a newline that isn't part of the value
+		return strings.TrimRight(string(content), "\r\n"), true
+	case value != "":
+		return value, true
+	}
+
+	value, ok := s.file[key]
+	return value, ok
+}
+
+func (s *source) errorf(format string, args ...interface{}) {
+	s.errs = append(s.errs, fmt.Errorf(format, args...))
+}
+
+// unknownFileKeys reports the keys of the configuration file that are not
+// settings, most likely typos that would otherwise be silently ignored.
+func (s *source) unknownFileKeys() []error {
+	var errs []error
+	for key := range s.file {
+		if !s.known[key] {
+			errs = append(errs, fmt.Errorf("%s: unknown setting in %s", key, configFileEnv))
+		}
+	}
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errs
+}
+
+func (s *source) getString(key, defaultValue string) string {
+	if value, ok := s.lookup(key); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func (s *source) getSecret(key, defaultValue string) Secret {
+	return Secret(s.getString(key, defaultValue))
+}
+
+// getOneOf returns the value of key, which must be one of allowed.
+func (s *source) getOneOf(key, defaultValue string, allowed ...string) string {
+	value := s.getString(key, defaultValue)
+	if !slices.Contains(allowed, value) {
+		s.errorf("%s: invalid value %q (expected %s)", key, value, strings.Join(allowed, ", "))
+	}
+	return value
+}
+
+// getInt returns the value of key, which must be an integer between min and max.
+func (s *source) getInt(key string, defaultValue, min, max int) int {
+	raw := s.getString(key, "")
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		s.errorf("%s: invalid integer %q", key, raw)
+		return defaultValue
+	}
+	if value < min || value > max {
+		if max == maxInt {
+			s.errorf("%s: must be at least %d, got %d", key, min, value)
+		} else {
+			s.errorf("%s: must be between %d and %d, got %d", key, min, max, value)
+		}
+	}
+	return value
+}
+
+// getDuration returns the value of key, a whole number of unit of at least min.
+func (s *source) getDuration(key string, defaultValue int, unit time.Duration, min int) time.Duration {
+	return time.Duration(s.getInt(key, defaultValue, min, maxInt)) * unit
+}
+
+// getPercent returns the value of key, a percentage, as a ratio between 0 and 1.
+func (s *source) getPercent(key string, defaultValue int) float64 {
+	return float64(s.getInt(key, defaultValue, 0, 100)) / 100
+}
+
+// getPort returns the value of key, which must be a TCP port number.
+func (s *source) getPort(key, defaultValue string) string {
+	value := s.getString(key, defaultValue)
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		s.errorf("%s: invalid port %q", key, value)
+	}
+	return value
+}
+
+// getURL returns the value of key, which must be empty or an absolute HTTP URL.
+func (s *source) getURL(key, defaultValue string) string {
+	value := s.getString(key, defaultValue)
+	if value == "" {
+		return value
+	}
+	if parsed, err := url.Parse(value); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		s.errorf("%s: invalid URL %q (expected http(s)://host[:port][/path])", key, value)
+	}
+	return value
+}
+
+// getPath returns the value of key, which must be empty or an URL path.
+func (s *source) getPath(key, defaultValue string) string {
+	value := s.getString(key, defaultValue)
+	if value != "" && !strings.HasPrefix(value, "/") {
+		s.errorf("%s: invalid path %q (expected to start with /)", key, value)
+	}
+	return value
+}
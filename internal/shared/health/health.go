@@ -0,0 +1,204 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses reported by the checks and the readiness.
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+	StatusDraining = "draining"
+)
+
+// Check is a dependency checked for readiness. A failing critical check makes
+// the service not ready; other checks are only reported.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a Check.
+type CheckResult struct {
+	Status     string `json:"status"`
+	Critical   bool   `json:"critical"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report is the readiness of the service, with the result of each check.
+type Report struct {
+	Status    string                 `json:"status"`
+	CheckedAt time.Time              `json:"checked_at"`
+	Checks    map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Readiness tells whether the service can take traffic. Check results are
+// cached for a while so frequent probes from several sources don't load the
+// dependencies, and the service reports itself as draining once shutdown
+// starts so the load balancer stops sending requests before it exits.
+type Readiness struct {
+	checks   []Check
+	timeout  time.Duration
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	last      *Report
+	expiresAt time.Time
+
+	draining atomic.Bool
+}
+
+// NewReadiness creates the readiness of a service depending on checks. Each
+// check must complete within timeout and results are reused for cacheTTL.
+func NewReadiness(checks []Check, timeout time.Duration, cacheTTL time.Duration) *Readiness {
+	return newReadinessWithClock(checks, timeout, cacheTTL, time.Now)
+}
+
+func newReadinessWithClock(checks []Check, timeout time.Duration, cacheTTL time.Duration, now func() time.Time) *Readiness {
+	return &Readiness{
+		checks:   checks,
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+		now:      now,
+	}
+}
+
+// Drain makes the service report itself as not ready from now on.
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+}
+
+// Check returns the readiness of the service, running the checks again only
+// when the cached report has expired. Concurrent callers share a single run.
+func (r *Readiness) Check(ctx context.Context) *Report {
+	if r.draining.Load() {
+		return &Report{Status: StatusDraining, CheckedAt: r.now()}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.last != nil && r.now().Before(r.expiresAt) {
+		return r.last
+	}
+
+	// The report is shared with other callers, so it must not be cut short
+	// by the caller that happens to run the checks going away
+	r.last = r.run(context.WithoutCancel(ctx))
+	r.expiresAt = r.now().Add(r.cacheTTL)
+	return r.last
+}
+
+func (r *Readiness) run(ctx context.Context) *Report {
+	report := &Report{
+		Status:    StatusReady,
+		CheckedAt: r.now(),
+		Checks:    make(map[string]CheckResult, len(r.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := r.runCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status == StatusDown && check.Critical {
+				report.Status = StatusNotReady
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (r *Readiness) runCheck(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(ctx)
+	result := CheckResult{
+		Status:     StatusUp,
+		Critical:   check.Critical,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// ServeHTTP answers 200 with the report when the service is ready, 503 otherwise.
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	report := r.Check(req.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusReady {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// LivenessHandler answers 200 as long as the process is able to serve
+// requests. It checks no dependency, so an outage doesn't get the service
+// restarted.
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "alive"})
+	})
+}
+
+// Pinger is a connection pool that can be pinged, such as *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PingCheck checks a connection pool by pinging it.
+func PingCheck(pinger Pinger) func(ctx context.Context) error {
+	return pinger.PingContext
+}
+
+// HTTPCheck checks a service by calling url, expecting a 2xx answer.
+func HTTPCheck(client *http.Client, url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
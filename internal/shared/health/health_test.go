@@ -0,0 +1,181 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock moved forward by hand.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// countedCheck fails with err, counting how many times it ran.
+func countedCheck(name string, critical bool, err error) (Check, *atomic.Int32) {
+	var runs atomic.Int32
+	return Check{
+		Name:     name,
+		Critical: critical,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return err
+		},
+	}, &runs
+}
+
+func probe(t *testing.T, handler http.Handler) (int, Report) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, report
+}
+
+// Feature: Readiness
+// Scenario: Report each dependency
+func Test_Readiness_WithHealthyDependencies_ShouldBeReady(t *testing.T) {
+	// GIVEN a database and a customer service up
+	database, _ := countedCheck("database", true, nil)
+	customers, _ := countedCheck("customer_service", false, nil)
+	readiness := NewReadiness([]Check{database, customers}, time.Second, time.Second)
+
+	// WHEN the readiness is probed
+	code, report := probe(t, readiness)
+
+	// THEN the service should be ready, with each dependency up
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusReady, report.Status)
+	assert.Equal(t, StatusUp, report.Checks["database"].Status)
+	assert.True(t, report.Checks["database"].Critical)
+	assert.Equal(t, StatusUp, report.Checks["customer_service"].Status)
+}
+
+func Test_Readiness_WithDatabaseDown_ShouldNotBeReady(t *testing.T) {
+	// GIVEN a database that can't be reached
+	database, _ := countedCheck("database", true, errors.New("connection refused"))
+	readiness := NewReadiness([]Check{database}, time.Second, time.Second)
+
+	// WHEN the readiness is probed
+	code, report := probe(t, readiness)
+
+	// THEN the service should not be ready, saying why
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusNotReady, report.Status)
+	assert.Equal(t, StatusDown, report.Checks["database"].Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+}
+
+func Test_Readiness_WithOptionalServiceDown_ShouldStayReady(t *testing.T) {
+	// GIVEN a product service down, which orders can do without
+	database, _ := countedCheck("database", true, nil)
+	products, _ := countedCheck("product_service", false, errors.New("unexpected status 503"))
+	readiness := NewReadiness([]Check{database, products}, time.Second, time.Second)
+
+	// WHEN the readiness is probed
+	code, report := probe(t, readiness)
+
+	// THEN the service should stay ready but report the outage
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusReady, report.Status)
+	assert.Equal(t, StatusDown, report.Checks["product_service"].Status)
+}
+
+// Scenario: Bound and cache the checks
+func Test_Readiness_WithSlowDependency_ShouldTimeOut(t *testing.T) {
+	// GIVEN a database that never answers
+	database := Check{Name: "database", Critical: true, Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	readiness := NewReadiness([]Check{database}, 10*time.Millisecond, time.Second)
+
+	// WHEN the readiness is probed
+	code, report := probe(t, readiness)
+
+	// THEN the check should give up after the timeout
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+}
+
+func Test_Readiness_ShouldReuseResultsUntilExpired(t *testing.T) {
+	// GIVEN a readiness caching results for 2 seconds
+	clock := &fakeClock{now: time.Now()}
+	database, runs := countedCheck("database", true, nil)
+	readiness := newReadinessWithClock([]Check{database}, time.Second, 2*time.Second, clock.Now)
+
+	// WHEN it is probed repeatedly within and after the cache period
+	readiness.Check(context.Background())
+	clock.now = clock.now.Add(time.Second)
+	readiness.Check(context.Background())
+	assert.Equal(t, int32(1), runs.Load())
+	clock.now = clock.now.Add(time.Second)
+	readiness.Check(context.Background())
+
+	// THEN the database should only be checked again once the result expired
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+// Scenario: Drain traffic on shutdown
+func Test_Readiness_WhenDraining_ShouldNotBeReady(t *testing.T) {
+	// GIVEN a healthy service that starts shutting down
+	database, runs := countedCheck("database", true, nil)
+	readiness := NewReadiness([]Check{database}, time.Second, time.Second)
+	readiness.Check(context.Background())
+	readiness.Drain()
+
+	// WHEN the readiness is probed
+	code, report := probe(t, readiness)
+
+	// THEN it should be reported as draining without checking the dependencies
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusDraining, report.Status)
+	assert.Empty(t, report.Checks)
+	assert.Equal(t, int32(1), runs.Load())
+}
+
+// Feature: Liveness
+func Test_LivenessHandler_ShouldAnswerAlive(t *testing.T) {
+	// GIVEN the liveness handler
+	handler := LivenessHandler()
+
+	// WHEN it is probed
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	// THEN the process should be reported alive
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"alive"}`, rec.Body.String())
+}
+
+// Feature: Dependency checks
+func Test_HTTPCheck_ShouldExpectSuccessStatus(t *testing.T) {
+	// GIVEN a healthy and an unhealthy service
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(healthy.Close)
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(unhealthy.Close)
+
+	// WHEN both are checked
+	healthyErr := HTTPCheck(http.DefaultClient, healthy.URL+"/health")(context.Background())
+	unhealthyErr := HTTPCheck(http.DefaultClient, unhealthy.URL+"/health")(context.Background())
+
+	// THEN only the unhealthy one should fail
+	assert.NoError(t, healthyErr)
+	assert.EqualError(t, unhealthyErr, "unexpected status 503")
+}
@@ -0,0 +1,277 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by errors.Is when a request was rejected without
+// being sent because the downstream host is known to be failing.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is returned while the circuit of a downstream host is open.
+type CircuitOpenError struct {
+	Host       string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker for %s is open, retry after %s", e.Host, e.RetryAfter.Round(time.Second))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreakerSettings configures when the circuit of a host opens and for how long.
+type CircuitBreakerSettings struct {
+	// ConsecutiveFailures opens the circuit after this many failures in a row.
+	ConsecutiveFailures int
+	// FailureRate opens the circuit when this share (0-1] of the last
+	// WindowSize requests failed, once at least MinRequests were made.
+	FailureRate float64
+	WindowSize  int
+	MinRequests int
+	// Cooldown is how long the circuit stays open before a probe request is let through.
+	Cooldown time.Duration
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuitBreakerClient struct {
+	next     HTTPClient
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// NewCircuitBreakerClient wraps next with one circuit breaker per downstream host.
+func NewCircuitBreakerClient(next HTTPClient, settings CircuitBreakerSettings) HTTPClient {
+	return newCircuitBreakerClient(next, settings, time.Now)
+}
+
+func newCircuitBreakerClient(next HTTPClient, settings CircuitBreakerSettings, now func() time.Time) *circuitBreakerClient {
+	if settings.WindowSize < 1 {
+		settings.WindowSize = 1
+	}
+
+	return &circuitBreakerClient{
+		next:     next,
+		settings: settings,
+		now:      now,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+func (c *circuitBreakerClient) Get(ctx context.Context, url string, response interface{}) error {
+	return c.do(ctx, url, func() error {
+		return c.next.Get(ctx, url, response)
+	})
+}
+
+func (c *circuitBreakerClient) Post(ctx context.Context, url string, body, response interface{}) error {
+	return c.do(ctx, url, func() error {
+		return c.next.Post(ctx, url, body, response)
+	})
+}
+
+func (c *circuitBreakerClient) do(ctx context.Context, rawURL string, call func() error) error {
+	circuit := c.circuit(rawURL)
+	token, err := circuit.allow()
+	if err != nil {
+		return err
+	}
+
+	err = call()
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// The caller gave up, which says nothing about the host
+		circuit.release(token)
+	case isFailure(err):
+		circuit.record(token, true)
+	default:
+		circuit.record(token, false)
+	}
+
+	return err
+}
+
+func (c *circuitBreakerClient) circuit(rawURL string) *circuit {
+	host := hostOf(rawURL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, exists := c.circuits[host]; exists {
+		return existing
+	}
+
+	created := &circuit{
+		host:     host,
+		settings: c.settings,
+		now:      c.now,
+		window:   make([]bool, c.settings.WindowSize),
+	}
+	c.circuits[host] = created
+	return created
+}
+
+// hostOf returns the host (and port) rawURL points to.
+func hostOf(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return rawURL
+}
+
+// isFailure reports whether err means the host is unhealthy. Client errors
+// (4xx other than 429) are valid answers from a healthy host.
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+// circuit tracks the health of a single host.
+type circuit struct {
+	host     string
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mu                  sync.Mutex
+	state               circuitState
+	generation          uint64
+	openedAt            time.Time
+	probing             bool
+	consecutiveFailures int
+
+	// window is a ring buffer holding whether each of the last requests failed
+	window   []bool
+	next     int
+	requests int
+	failures int
+}
+
+// circuitToken identifies the state of the circuit a request was let through in.
+type circuitToken struct {
+	generation uint64
+}
+
+// allow reports whether a request may be sent. While half-open a single probe
+// request is let through at a time.
+func (c *circuit) allow() (circuitToken, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case circuitOpen:
+		elapsed := c.now().Sub(c.openedAt)
+		if elapsed < c.settings.Cooldown {
+			return circuitToken{}, &CircuitOpenError{Host: c.host, RetryAfter: c.settings.Cooldown - elapsed}
+		}
+		c.state = circuitHalfOpen
+		c.generation++
+		c.probing = true
+	case circuitHalfOpen:
+		if c.probing {
+			return circuitToken{}, &CircuitOpenError{Host: c.host}
+		}
+		c.probing = true
+	}
+	return circuitToken{generation: c.generation}, nil
+}
+
+// record stores the outcome of a request let through by allow. Requests let
+// through before the circuit last changed state don't change it.
+func (c *circuit) record(token circuitToken, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if token.generation != c.generation {
+		return
+	}
+
+	if c.state == circuitHalfOpen {
+		c.probing = false
+		if failed {
+			c.open()
+		} else {
+			c.reset()
+		}
+		return
+	}
+
+	if c.requests == len(c.window) && c.window[c.next] {
+		c.failures--
+	}
+	if c.requests < len(c.window) {
+		c.requests++
+	}
+	c.window[c.next] = failed
+	c.next = (c.next + 1) % len(c.window)
+
+	if !failed {
+		c.consecutiveFailures = 0
+		return
+	}
+
+	c.failures++
+	c.consecutiveFailures++
+
+	if c.settings.ConsecutiveFailures > 0 && c.consecutiveFailures >= c.settings.ConsecutiveFailures {
+		c.open()
+		return
+	}
+	if c.settings.FailureRate > 0 && c.requests >= c.settings.MinRequests &&
+		float64(c.failures)/float64(c.requests) >= c.settings.FailureRate {
+		c.open()
+	}
+}
+
+// release gives up a request let through by allow without recording an outcome.
+func (c *circuit) release(token circuitToken) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if token.generation == c.generation && c.state == circuitHalfOpen {
+		c.probing = false
+	}
+}
+
+func (c *circuit) open() {
+	c.reset()
+	c.state = circuitOpen
+	c.openedAt = c.now()
+}
+
+func (c *circuit) reset() {
+	c.state = circuitClosed
+	c.generation++
+	c.probing = false
+	c.consecutiveFailures = 0
+	c.next = 0
+	c.requests = 0
+	c.failures = 0
+	clear(c.window)
+}
@@ -0,0 +1,217 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHTTPClient answers every request with the result of answer.
+type fakeHTTPClient struct {
+	calls  int
+	answer func(url string) error
+}
+
+func (c *fakeHTTPClient) Get(ctx context.Context, url string, response interface{}) error {
+	c.calls++
+	return c.answer(url)
+}
+
+func (c *fakeHTTPClient) Post(ctx context.Context, url string, body, response interface{}) error {
+	c.calls++
+	return c.answer(url)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+var (
+	errUnavailable = &StatusError{StatusCode: http.StatusServiceUnavailable}
+	errNotFound    = &StatusError{StatusCode: http.StatusNotFound}
+)
+
+func newTestBreaker(next HTTPClient, clock *fakeClock) *circuitBreakerClient {
+	return newCircuitBreakerClient(next, CircuitBreakerSettings{
+		ConsecutiveFailures: 3,
+		FailureRate:         0.5,
+		WindowSize:          10,
+		MinRequests:         6,
+		Cooldown:            30 * time.Second,
+	}, clock.Now)
+}
+
+func failTimes(client HTTPClient, url string, times int) {
+	for i := 0; i < times; i++ {
+		_ = client.Get(context.Background(), url, nil)
+	}
+}
+
+// Feature: Circuit breaker
+// Scenario: Stop calling a failing host
+func Test_CircuitBreaker_WithConsecutiveFailures_ShouldOpen(t *testing.T) {
+	// GIVEN a host that keeps failing
+	next := &fakeHTTPClient{answer: func(string) error { return errUnavailable }}
+	client := newTestBreaker(next, &fakeClock{now: time.Now()})
+	failTimes(client, "http://customer:8081/v1/customer/1", 3)
+
+	// WHEN another request is made
+	err := client.Get(context.Background(), "http://customer:8081/v1/customer/2", nil)
+
+	// THEN it should be rejected without reaching the host
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, next.calls)
+	var openErr *CircuitOpenError
+	assert.ErrorAs(t, err, &openErr)
+	assert.Equal(t, "customer:8081", openErr.Host)
+	assert.Equal(t, 30*time.Second, openErr.RetryAfter)
+}
+
+func Test_CircuitBreaker_WithFailureRateAboveThreshold_ShouldOpen(t *testing.T) {
+	// GIVEN a host failing every other request, starting with a success
+	failed := true
+	next := &fakeHTTPClient{answer: func(string) error {
+		failed = !failed
+		if failed {
+			return errUnavailable
+		}
+		return nil
+	}}
+	client := newTestBreaker(next, &fakeClock{now: time.Now()})
+
+	// WHEN the minimum number of requests is reached
+	failTimes(client, "http://product:8082/v1/product/1", 6)
+
+	// THEN the circuit should open
+	err := client.Get(context.Background(), "http://product:8082/v1/product/1", nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 6, next.calls)
+}
+
+func Test_CircuitBreaker_WithClientErrors_ShouldStayClosed(t *testing.T) {
+	// GIVEN a healthy host answering not found
+	next := &fakeHTTPClient{answer: func(string) error { return errNotFound }}
+	client := newTestBreaker(next, &fakeClock{now: time.Now()})
+	failTimes(client, "http://customer:8081/v1/customer/1", 10)
+
+	// WHEN another request is made
+	err := client.Get(context.Background(), "http://customer:8081/v1/customer/1", nil)
+
+	// THEN it should reach the host
+	assert.ErrorIs(t, err, errNotFound)
+	assert.Equal(t, 11, next.calls)
+}
+
+func Test_CircuitBreaker_WithCancelledCaller_ShouldNotCountFailure(t *testing.T) {
+	// GIVEN requests abandoned by their callers
+	next := &fakeHTTPClient{answer: func(string) error { return context.Canceled }}
+	client := newTestBreaker(next, &fakeClock{now: time.Now()})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 5; i++ {
+		_ = client.Get(ctx, "http://customer:8081/v1/customer/1", nil)
+	}
+
+	// WHEN a new request is made
+	next.answer = func(string) error { return nil }
+	err := client.Get(context.Background(), "http://customer:8081/v1/customer/1", nil)
+
+	// THEN it should reach the host
+	assert.NoError(t, err)
+}
+
+func Test_CircuitBreaker_ShouldTrackHostsSeparately(t *testing.T) {
+	// GIVEN a failing customer service and a healthy product service
+	next := &fakeHTTPClient{answer: func(url string) error {
+		if url == "http://customer:8081/v1/customer/1" {
+			return errUnavailable
+		}
+		return nil
+	}}
+	client := newTestBreaker(next, &fakeClock{now: time.Now()})
+	failTimes(client, "http://customer:8081/v1/customer/1", 3)
+
+	// WHEN the product service is called
+	err := client.Get(context.Background(), "http://product:8082/v1/product/1", nil)
+
+	// THEN it should not be affected
+	assert.NoError(t, err)
+}
+
+// Scenario: Probe the host after the cooldown
+func Test_CircuitBreaker_AfterCooldown_ShouldLetOneProbeThrough(t *testing.T) {
+	// GIVEN an open circuit whose cooldown elapsed
+	clock := &fakeClock{now: time.Now()}
+	next := &fakeHTTPClient{answer: func(string) error { return errUnavailable }}
+	client := newTestBreaker(next, clock)
+	failTimes(client, "http://customer:8081/v1/customer/1", 3)
+	clock.now = clock.now.Add(30 * time.Second)
+
+	// WHEN a request arrives while the probe is in flight
+	var concurrentErr error
+	next.answer = func(string) error {
+		concurrentErr = client.Get(context.Background(), "http://customer:8081/v1/customer/2", nil)
+		return nil
+	}
+	probeErr := client.Get(context.Background(), "http://customer:8081/v1/customer/1", nil)
+
+	// THEN only the probe should reach the host
+	assert.NoError(t, probeErr)
+	assert.ErrorIs(t, concurrentErr, ErrCircuitOpen)
+	// AND the successful probe should close the circuit
+	next.answer = func(string) error { return nil }
+	assert.NoError(t, client.Get(context.Background(), "http://customer:8081/v1/customer/2", nil))
+}
+
+func Test_CircuitBreaker_WithFailedProbe_ShouldReopen(t *testing.T) {
+	// GIVEN an open circuit whose cooldown elapsed
+	clock := &fakeClock{now: time.Now()}
+	next := &fakeHTTPClient{answer: func(string) error { return errUnavailable }}
+	client := newTestBreaker(next, clock)
+	failTimes(client, "http://customer:8081/v1/customer/1", 3)
+	clock.now = clock.now.Add(30 * time.Second)
+
+	// WHEN the probe fails
+	probeErr := client.Get(context.Background(), "http://customer:8081/v1/customer/1", nil)
+
+	// THEN the circuit should open for another cooldown
+	assert.ErrorIs(t, probeErr, errUnavailable)
+	err := client.Get(context.Background(), "http://customer:8081/v1/customer/1", nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 4, next.calls)
+}
+
+// Scenario: Ignore requests let through before the circuit changed state
+func Test_CircuitBreaker_WithSlowRequestFromBeforeOpening_ShouldNotCloseHalfOpenCircuit(t *testing.T) {
+	// GIVEN a slow request let through while the circuit was closed
+	clock := &fakeClock{now: time.Now()}
+	next := &fakeHTTPClient{answer: func(string) error { return errUnavailable }}
+	client := newTestBreaker(next, clock)
+	circuit := client.circuit("http://customer:8081/v1/customer/1")
+	slow, err := circuit.allow()
+	assert.NoError(t, err)
+
+	// AND the circuit opened and let a probe through after the cooldown
+	failTimes(client, "http://customer:8081/v1/customer/1", 3)
+	clock.now = clock.now.Add(30 * time.Second)
+	probe, err := circuit.allow()
+	assert.NoError(t, err)
+
+	// WHEN the slow request succeeds
+	circuit.record(slow, false)
+
+	// THEN the circuit should stay half-open waiting for the probe
+	_, err = circuit.allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	// AND the failed probe should open it again
+	circuit.record(probe, true)
+	err = client.Get(context.Background(), "http://customer:8081/v1/customer/1", nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
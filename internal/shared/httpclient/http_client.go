@@ -4,102 +4,195 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/logging"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
 )
 
+// StatusError is returned when the downstream service answers with a non-2xx status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err was caused by a 404 answer from the downstream service.
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
 type HTTPClient interface {
 	Get(ctx context.Context, url string, response interface{}) error
 	Post(ctx context.Context, url string, body, response interface{}) error
 }
 
 type httpClientImpl struct {
-	client  *http.Client
-	retries int
-	backoff time.Duration
+	client *http.Client
+	policy RetryPolicy
+	budget *RetryBudget
+
+	// sleep waits for d or until ctx is done; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
 }
 
-func NewHTTPClient(timeout time.Duration, retries int, backoff time.Duration) HTTPClient {
+// NewHTTPClient creates a client retrying failed requests according to policy,
+// within budget (nil for no budget).
+func NewHTTPClient(timeout time.Duration, policy RetryPolicy, budget *RetryBudget) HTTPClient {
+	return newHTTPClient(timeout, policy, budget)
+}
+
+func newHTTPClient(timeout time.Duration, policy RetryPolicy, budget *RetryBudget) *httpClientImpl {
 	return &httpClientImpl{
 		client: &http.Client{
 			Timeout: timeout,
 		},
-		retries: retries,
-		backoff: backoff,
+		policy: policy,
+		budget: budget,
+		sleep:  sleep,
 	}
 }
 
 func (h *httpClientImpl) Get(ctx context.Context, url string, response interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create GET request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	return h.doWithRetry(req, response)
+	return h.doWithRetry(ctx, http.MethodGet, url, nil, response)
 }
 
 func (h *httpClientImpl) Post(ctx context.Context, url string, body, response interface{}) error {
-	var bodyReader io.Reader
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
+		var err error
+		if jsonBody, err = json.Marshal(body); err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewBuffer(jsonBody)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bodyReader)
-	if err != nil {
-		return fmt.Errorf("failed to create POST request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	return h.doWithRetry(req, response)
+	return h.doWithRetry(ctx, http.MethodPost, url, jsonBody, response)
 }
 
-func (h *httpClientImpl) doWithRetry(req *http.Request, response interface{}) error {
-	var lastErr error
+func (h *httpClientImpl) doWithRetry(ctx context.Context, method string, url string, body []byte, response interface{}) error {
+	h.budget.deposit()
 
-	for attempt := 0; attempt <= h.retries; attempt++ {
-		if attempt > 0 {
-			// Exponential backoff
-			backoffDuration := h.backoff * time.Duration(1<<uint(attempt-1))
-			time.Sleep(backoffDuration)
+	for attempt := 1; ; attempt++ {
+		// The request is rebuilt on every attempt since sending it consumes the body
+		var bodyReader io.Reader
+		if body != nil {
+			bodyReader = bytes.NewReader(body)
 		}
-
-		resp, err := h.client.Do(req)
+		req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 		if err != nil {
-			lastErr = fmt.Errorf("HTTP request failed (attempt %d/%d): %w", attempt+1, h.retries+1, err)
-			continue
+			return fmt.Errorf("failed to create %s request: %w", method, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if requestID := logging.RequestID(ctx); requestID != "" {
+			req.Header.Set(logging.RequestIDHeader, requestID)
 		}
 
-		defer resp.Body.Close()
+		resp, err := h.send(req, attempt)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return decodeResponse(resp, response)
+		}
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var attemptErr error
+		if err != nil {
+			attemptErr = fmt.Errorf("HTTP request failed (attempt %d): %w", attempt, err)
+		} else {
 			bodyBytes, _ := io.ReadAll(resp.Body)
-			lastErr = fmt.Errorf("HTTP request failed with status %d (attempt %d/%d): %s",
-				resp.StatusCode, attempt+1, h.retries+1, string(bodyBytes))
-
-			// Don't retry on 4xx errors (client errors)
-			if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-				return lastErr
-			}
-			continue
+			resp.Body.Close()
+			attemptErr = fmt.Errorf("HTTP request failed with %w (attempt %d)",
+				&StatusError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}, attempt)
 		}
 
-		if response != nil {
-			if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
-				return fmt.Errorf("failed to decode response: %w", err)
-			}
+		if ctx.Err() != nil {
+			return attemptErr
 		}
 
-		return nil
+		delay, retry := h.policy.Backoff(req, attempt, resp, err)
+		if !retry || !h.budget.withdraw() {
+			return attemptErr
+		}
+
+		logging.FromContext(ctx).Info("retrying request",
+			zap.String("method", method),
+			zap.String("url", url),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(attemptErr),
+		)
+		if err := h.sleep(ctx, delay); err != nil {
+			return fmt.Errorf("%w; retry abandoned: %w", attemptErr, err)
+		}
+	}
+}
+
+// send makes one attempt at req in a client span, passing the trace context on
+// to the downstream service in the traceparent header.
+func (h *httpClientImpl) send(req *http.Request, attempt int) (*http.Response, error) {
+	ctx, span := tracing.Start(req.Context(), req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(req.URL.String()),
+			semconv.ServerAddress(req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+	if attempt > 1 {
+		span.SetAttributes(semconv.HTTPRequestResendCount(attempt - 1))
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := h.client.Do(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
 	}
 
-	return lastErr
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+func decodeResponse(resp *http.Response, response interface{}) error {
+	defer resp.Body.Close()
+
+	if response != nil {
+		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	// Drain what's left so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// sleep waits for d, returning early with the context error when ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
@@ -0,0 +1,348 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/logging"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing/tracingtest"
+)
+
+// recordedClient is an httpClientImpl whose waits return immediately and are recorded.
+type recordedClient struct {
+	*httpClientImpl
+	waits []time.Duration
+}
+
+func newTestPolicy(maxRetries int) *ExponentialBackoffPolicy {
+	policy := NewExponentialBackoffPolicy(maxRetries, 100*time.Millisecond, 2*time.Second)
+	// Always wait the longest delay allowed so waits are predictable
+	policy.jitter = func(d time.Duration) time.Duration { return d }
+	return policy
+}
+
+func newRecordedClient(policy RetryPolicy, budget *RetryBudget) *recordedClient {
+	client := &recordedClient{httpClientImpl: newHTTPClient(time.Second, policy, budget)}
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		client.waits = append(client.waits, d)
+		return ctx.Err()
+	}
+	return client
+}
+
+// answerInOrder serves the given handlers one per request, repeating the last one.
+func answerInOrder(t *testing.T, handlers ...http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1))
+		handlers[min(call, len(handlers))-1](w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func status(code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+	}
+}
+
+func ok(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+type productDto struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// Feature: HTTP client retries
+// Scenario: Retry transient failures
+func Test_Get_WithUnavailableThenOK_ShouldRetryAndSucceed(t *testing.T) {
+	// GIVEN a service unavailable on the first two requests
+	server, calls := answerInOrder(t, status(http.StatusServiceUnavailable), status(http.StatusBadGateway), ok(`{"id":1,"name":"burger"}`))
+	client := newRecordedClient(newTestPolicy(3), nil)
+
+	// WHEN a product is requested
+	var product productDto
+	err := client.Get(context.Background(), server.URL, &product)
+
+	// THEN it should succeed after backing off exponentially
+	require.NoError(t, err)
+	assert.Equal(t, productDto{ID: 1, Name: "burger"}, product)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, client.waits)
+}
+
+func Test_Get_WithPersistentFailure_ShouldStopAfterMaxRetries(t *testing.T) {
+	// GIVEN a service that keeps failing
+	server, calls := answerInOrder(t, status(http.StatusInternalServerError))
+	client := newRecordedClient(newTestPolicy(2), nil)
+
+	// WHEN a product is requested
+	err := client.Get(context.Background(), server.URL, nil)
+
+	// THEN the last status should be returned after the retries
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusInternalServerError, statusErr.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func Test_Get_WithNotFound_ShouldNotRetry(t *testing.T) {
+	// GIVEN a service answering not found
+	server, calls := answerInOrder(t, status(http.StatusNotFound))
+	client := newRecordedClient(newTestPolicy(3), nil)
+
+	// WHEN a product is requested
+	err := client.Get(context.Background(), server.URL, nil)
+
+	// THEN the error should be returned without retrying
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func Test_Get_WithDroppedConnection_ShouldRetry(t *testing.T) {
+	// GIVEN a service dropping the connection on the first request
+	dropConnection := func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		conn.Close()
+	}
+	server, calls := answerInOrder(t, dropConnection, ok(`{"id":1}`))
+	client := newRecordedClient(newTestPolicy(3), nil)
+
+	// WHEN a product is requested
+	err := client.Get(context.Background(), server.URL, nil)
+
+	// THEN it should be retried
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// Scenario: Only retry requests that can't be applied twice
+func Test_Post_WithUnavailable_ShouldResendTheWholeBody(t *testing.T) {
+	// GIVEN a service unavailable on the first request
+	var bodies []string
+	record := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			next(w, r)
+		}
+	}
+	server, _ := answerInOrder(t, record(status(http.StatusServiceUnavailable)), record(ok(`{}`)))
+	client := newRecordedClient(newTestPolicy(3), nil)
+
+	// WHEN a product batch is posted
+	err := client.Post(context.Background(), server.URL, map[string][]uint{"ids": {1, 2}}, nil)
+
+	// THEN both attempts should carry the full body
+	require.NoError(t, err)
+	assert.Equal(t, []string{`{"ids":[1,2]}`, `{"ids":[1,2]}`}, bodies)
+}
+
+func Test_Post_WithInternalServerError_ShouldNotRetry(t *testing.T) {
+	// GIVEN a service that may have processed the request before failing
+	server, calls := answerInOrder(t, status(http.StatusInternalServerError))
+	client := newRecordedClient(newTestPolicy(3), nil)
+
+	// WHEN a request is posted
+	err := client.Post(context.Background(), server.URL, map[string]string{}, nil)
+
+	// THEN it should not be sent again
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// Scenario: Honor Retry-After
+func Test_Get_WithRetryAfter_ShouldWaitTheAskedDelay(t *testing.T) {
+	// GIVEN a service rate limiting the first request
+	rateLimited := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	server, calls := answerInOrder(t, rateLimited, ok(`{}`))
+	client := newRecordedClient(newTestPolicy(3), nil)
+
+	// WHEN a product is requested
+	err := client.Get(context.Background(), server.URL, nil)
+
+	// THEN it should wait as long as the service asked
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, []time.Duration{time.Second}, client.waits)
+}
+
+func Test_Get_WithRetryAfterBeyondMaxDelay_ShouldGiveUp(t *testing.T) {
+	// GIVEN a service asking to wait longer than the client would
+	unavailable := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	server, calls := answerInOrder(t, unavailable)
+	client := newRecordedClient(newTestPolicy(3), nil)
+
+	// WHEN a product is requested
+	err := client.Get(context.Background(), server.URL, nil)
+
+	// THEN it should fail without waiting
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Empty(t, client.waits)
+}
+
+// Scenario: Stop waiting when the caller gives up
+func Test_Get_WithCancelledContextWhileWaiting_ShouldReturnPromptly(t *testing.T) {
+	// GIVEN a failing service and a policy waiting a long time between attempts
+	server, calls := answerInOrder(t, status(http.StatusServiceUnavailable))
+	policy := NewExponentialBackoffPolicy(3, time.Hour, time.Hour)
+	client := newHTTPClient(time.Second, policy, nil)
+
+	// WHEN the caller gives up during the wait
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := client.Get(ctx, server.URL, nil)
+
+	// THEN the request should fail right away with both causes
+	assert.Less(t, time.Since(start), time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var statusErr *StatusError
+	assert.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// Scenario: Limit retries to a share of the requests
+func Test_Get_WithExhaustedBudget_ShouldNotRetry(t *testing.T) {
+	// GIVEN a failing service and a budget allowing a single retry
+	server, calls := answerInOrder(t, status(http.StatusServiceUnavailable))
+	client := newRecordedClient(newTestPolicy(3), NewRetryBudget(0, 1))
+
+	// WHEN two products are requested
+	_ = client.Get(context.Background(), server.URL, nil)
+	_ = client.Get(context.Background(), server.URL, nil)
+
+	// THEN only one retry should be made across both requests
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func Test_RetryBudget_ShouldEarnRetriesFromRequests(t *testing.T) {
+	// GIVEN an empty budget allowing retries for half of the requests
+	budget := NewRetryBudget(0.5, 10)
+	budget.tokens = 0
+
+	// WHEN two requests are made
+	budget.deposit()
+	budget.deposit()
+
+	// THEN a single retry should be allowed
+	assert.True(t, budget.withdraw())
+	assert.False(t, budget.withdraw())
+}
+
+func Test_ExponentialBackoffPolicy_ShouldCapTheDelay(t *testing.T) {
+	// GIVEN the policy used by the service
+	policy := NewExponentialBackoffPolicy(100, 100*time.Millisecond, 2*time.Second)
+	req := httptest.NewRequest(http.MethodGet, "http://product:8082/v1/product/1", nil)
+	resp := &http.Response{StatusCode: http.StatusBadGateway}
+
+	for attempt := 1; attempt <= 100; attempt++ {
+		// WHEN the delay before the next attempt is asked
+		delay, retry := policy.Backoff(req, attempt, resp, nil)
+
+		// THEN it should be jittered below the cap
+		assert.True(t, retry)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.Less(t, delay, 2*time.Second)
+	}
+}
+
+func Test_ExponentialBackoffPolicy_WithIdempotencyKey_ShouldRetryPost(t *testing.T) {
+	// GIVEN a post carrying an idempotency key
+	policy := newTestPolicy(3)
+	req := httptest.NewRequest(http.MethodPost, "http://order:8080/v1/order", nil)
+	req.Header.Set("Idempotency-Key", "b0f1")
+
+	// WHEN it failed with a server error
+	_, retry := policy.Backoff(req, 1, &http.Response{StatusCode: http.StatusInternalServerError}, nil)
+
+	// THEN it should be retried
+	assert.True(t, retry)
+}
+
+// Scenario: Correlate downstream requests
+func Test_Get_WithRequestID_ShouldForwardIt(t *testing.T) {
+	// GIVEN a request being handled with an ID
+	var forwarded string
+	server, _ := answerInOrder(t, func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get(logging.RequestIDHeader)
+	})
+	client := newRecordedClient(newTestPolicy(3), nil)
+	ctx := logging.WithRequestID(context.Background(), "checkout-42")
+
+	// WHEN a downstream service is called
+	err := client.Get(ctx, server.URL, nil)
+
+	// THEN the ID should be sent along
+	require.NoError(t, err)
+	assert.Equal(t, "checkout-42", forwarded)
+}
+
+// Feature: Tracing
+// Scenario: Trace each attempt and propagate the trace downstream
+func Test_Get_WithTrace_ShouldCreateClientSpanPerAttempt(t *testing.T) {
+	// GIVEN a service unavailable on the first request and a traced caller
+	recorder := tracingtest.NewRecorder(t)
+	var traceparents []string
+	server, _ := answerInOrder(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			traceparents = append(traceparents, r.Header.Get("traceparent"))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			traceparents = append(traceparents, r.Header.Get("traceparent"))
+		},
+	)
+	client := newRecordedClient(newTestPolicy(3), nil)
+	ctx, parent := tracing.Start(context.Background(), "GET /v1/order/{id}")
+
+	// WHEN a downstream service is called
+	err := client.Get(ctx, server.URL, nil)
+	parent.End()
+
+	// THEN each attempt should have its own client span in the caller's trace
+	require.NoError(t, err)
+	var attempts []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.SpanKind() == trace.SpanKindClient {
+			attempts = append(attempts, span)
+		}
+	}
+	require.Len(t, attempts, 2)
+	for i, attempt := range attempts {
+		assert.Equal(t, parent.SpanContext().TraceID(), attempt.SpanContext().TraceID())
+		assert.Equal(t, parent.SpanContext().SpanID(), attempt.Parent().SpanID())
+		// AND the downstream service should see the attempt as its parent
+		assert.Equal(t, "00-"+attempt.SpanContext().TraceID().String()+"-"+attempt.SpanContext().SpanID().String()+"-01", traceparents[i])
+	}
+	assert.Equal(t, codes.Error, attempts[0].Status().Code)
+	assert.Contains(t, attempts[1].Attributes(), semconv.HTTPRequestResendCount(1))
+	assert.Contains(t, attempts[1].Attributes(), semconv.HTTPResponseStatusCode(http.StatusOK))
+}
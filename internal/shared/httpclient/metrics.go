@@ -0,0 +1,76 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Outcomes of a downstream request, as labeled in the metrics.
+const (
+	outcomeSuccess     = "success"
+	outcomeClientError = "client_error"
+	outcomeServerError = "server_error"
+	outcomeCircuitOpen = "circuit_open"
+	outcomeCanceled    = "canceled"
+	outcomeError       = "error"
+)
+
+type instrumentedClient struct {
+	next     HTTPClient
+	duration *prometheus.HistogramVec
+}
+
+// NewInstrumentedClient wraps next, observing the latency and outcome of the
+// requests sent to each downstream host, retries included.
+func NewInstrumentedClient(next HTTPClient, registerer prometheus.Registerer) HTTPClient {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "downstream_request_duration_seconds",
+		Help:    "Latency of the requests sent to downstream services, by host and outcome.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"host", "method", "outcome"})
+	registerer.MustRegister(duration)
+
+	return &instrumentedClient{next: next, duration: duration}
+}
+
+func (c *instrumentedClient) Get(ctx context.Context, url string, response interface{}) error {
+	start := time.Now()
+	err := c.next.Get(ctx, url, response)
+	c.observe(ctx, http.MethodGet, url, start, err)
+	return err
+}
+
+func (c *instrumentedClient) Post(ctx context.Context, url string, body, response interface{}) error {
+	start := time.Now()
+	err := c.next.Post(ctx, url, body, response)
+	c.observe(ctx, http.MethodPost, url, start, err)
+	return err
+}
+
+func (c *instrumentedClient) observe(ctx context.Context, method string, url string, start time.Time, err error) {
+	c.duration.WithLabelValues(hostOf(url), method, outcome(ctx, err)).Observe(time.Since(start).Seconds())
+}
+
+func outcome(ctx context.Context, err error) string {
+	if err == nil {
+		return outcomeSuccess
+	}
+
+	var statusErr *StatusError
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return outcomeCircuitOpen
+	case errors.As(err, &statusErr) && statusErr.StatusCode < http.StatusInternalServerError && statusErr.StatusCode != http.StatusTooManyRequests:
+		return outcomeClientError
+	case errors.As(err, &statusErr):
+		return outcomeServerError
+	case ctx.Err() != nil:
+		return outcomeCanceled
+	default:
+		return outcomeError
+	}
+}
@@ -0,0 +1,73 @@
+package httpclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// observedRequests returns how many downstream requests were observed by host and outcome.
+func observedRequests(t *testing.T, registry *prometheus.Registry) map[string]uint64 {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	observed := make(map[string]uint64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			observed[labels["host"]+" "+labels["outcome"]] += metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return observed
+}
+
+// Feature: Downstream metrics
+// Scenario: Observe requests by host and outcome
+func Test_InstrumentedClient_ShouldObserveOutcomeByHost(t *testing.T) {
+	// GIVEN a customer service answering each kind of failure
+	answers := map[string]error{
+		"http://customer:8081/v1/customer/1": nil,
+		"http://customer:8081/v1/customer/2": errNotFound,
+		"http://customer:8081/v1/customer/3": errUnavailable,
+		"http://customer:8081/v1/customer/4": &CircuitOpenError{Host: "customer:8081"},
+		"http://product:8082/v1/product/1":   nil,
+	}
+	next := &fakeHTTPClient{answer: func(url string) error { return answers[url] }}
+	registry := prometheus.NewRegistry()
+	client := NewInstrumentedClient(next, registry)
+
+	// WHEN each URL is requested
+	for url := range answers {
+		_ = client.Get(context.Background(), url, nil)
+	}
+
+	// THEN every request should be observed under its host and outcome
+	assert.Equal(t, map[string]uint64{
+		"customer:8081 success":      1,
+		"customer:8081 client_error": 1,
+		"customer:8081 server_error": 1,
+		"customer:8081 circuit_open": 1,
+		"product:8082 success":       1,
+	}, observedRequests(t, registry))
+}
+
+func Test_InstrumentedClient_WithCancelledCaller_ShouldObserveCanceled(t *testing.T) {
+	// GIVEN a request abandoned by its caller
+	next := &fakeHTTPClient{answer: func(string) error { return context.Canceled }}
+	registry := prometheus.NewRegistry()
+	client := NewInstrumentedClient(next, registry)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// WHEN it returns
+	_ = client.Get(ctx, "http://product:8082/v1/product/1", nil)
+
+	// THEN it should not be observed as a downstream error
+	assert.Equal(t, map[string]uint64{"product:8082 canceled": 1}, observedRequests(t, registry))
+}
@@ -0,0 +1,155 @@
+package httpclient
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy decides whether a failed attempt is retried and how long to wait first.
+type RetryPolicy interface {
+	// Backoff is called after attempt (starting at 1) of req failed, either with
+	// a non-2xx resp (whose body is already closed) or with the transport err.
+	// It returns the delay before the next attempt, or false to give up.
+	Backoff(req *http.Request, attempt int, resp *http.Response, err error) (time.Duration, bool)
+}
+
+// ExponentialBackoffPolicy retries with full-jitter exponential backoff:
+// attempt n waits a random delay in [0, min(MaxDelay, BaseDelay*2^(n-1))).
+// A Retry-After header on 429 and 503 answers is honored instead, unless it
+// asks to wait longer than MaxDelay.
+//
+// Requests are only retried when doing so can't apply them twice: idempotent
+// methods always, other methods only when they carry an Idempotency-Key header
+// or the answer (429 or 503) says the request was not processed.
+type ExponentialBackoffPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	// jitter returns a random duration in [0, d); replaced in tests
+	jitter func(d time.Duration) time.Duration
+}
+
+func NewExponentialBackoffPolicy(maxRetries int, baseDelay time.Duration, maxDelay time.Duration) *ExponentialBackoffPolicy {
+	return &ExponentialBackoffPolicy{
+		MaxRetries: maxRetries,
+		BaseDelay:  baseDelay,
+		MaxDelay:   maxDelay,
+		jitter: func(d time.Duration) time.Duration {
+			if d <= 0 {
+				return 0
+			}
+			return rand.N(d)
+		},
+	}
+}
+
+func (p *ExponentialBackoffPolicy) Backoff(req *http.Request, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt > p.MaxRetries {
+		return 0, false
+	}
+
+	if resp == nil {
+		// The request may have reached the server before the connection failed
+		return p.exponential(attempt), isIdempotent(req)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// The server rejected the request without processing it
+		if delay, ok := retryAfter(resp); ok {
+			return delay, delay <= p.MaxDelay
+		}
+		return p.exponential(attempt), true
+	case http.StatusRequestTimeout, http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return p.exponential(attempt), isIdempotent(req)
+	default:
+		return 0, false
+	}
+}
+
+func (p *ExponentialBackoffPolicy) exponential(attempt int) time.Duration {
+	delay := p.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if exponential := p.BaseDelay << shift; exponential > 0 && exponential < p.MaxDelay {
+			delay = exponential
+		}
+	}
+	return p.jitter(delay)
+}
+
+// isIdempotent reports whether sending req twice has the same effect as sending it once.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get("Idempotency-Key") != ""
+	}
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// RetryBudget caps retries to a share of the requests made by a client, so a
+// struggling downstream service isn't flooded with retries. Every request
+// earns Ratio of a retry, up to MaxTokens saved; a retry spends a whole one.
+type RetryBudget struct {
+	ratio     float64
+	maxTokens float64
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+// NewRetryBudget creates a budget allowing retries for ratio of the requests,
+// starting with (and saving up to) maxTokens retries.
+func NewRetryBudget(ratio float64, maxTokens int) *RetryBudget {
+	return &RetryBudget{
+		ratio:     ratio,
+		maxTokens: float64(maxTokens),
+		tokens:    float64(maxTokens),
+	}
+}
+
+func (b *RetryBudget) deposit() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.ratio, b.maxTokens)
+}
+
+func (b *RetryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader carries the request ID from the caller and to downstream services.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from callers.
+const maxRequestIDLength = 128
+
+// Middleware tags each request with the X-Request-ID sent by the caller, or a
+// generated one, echoes it in the response, stores a logger tagged with it (and
+// the trace ID, when the request is traced) in the request context and logs
+// the request once it is handled.
+func Middleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !isValidRequestID(requestID) {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			requestLogger := logger.With(zap.String("request_id", requestID))
+			if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.IsValid() {
+				requestLogger = requestLogger.With(zap.String("trace_id", spanContext.TraceID().String()))
+			}
+			ctx := WithLogger(WithRequestID(r.Context(), requestID), requestLogger)
+
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			requestLogger.Info("request handled",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", status),
+				zap.Int("bytes", ww.BytesWritten()),
+				zap.Duration("duration", time.Since(start)),
+				zap.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// isValidRequestID rejects IDs that are empty, too long or could forge log
+// lines or headers (anything but printable ASCII).
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < '!' || requestID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,126 @@
+package logging_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/logging"
+)
+
+// serve handles a request through the middleware and returns the request ID
+// seen by the handler, the response and the log entries.
+func serve(req *http.Request) (string, *httptest.ResponseRecorder, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.DebugLevel)
+	var handlerRequestID string
+	handler := logging.Middleware(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerRequestID = logging.RequestID(r.Context())
+		logging.FromContext(r.Context()).Warn("customer enrichment skipped")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return handlerRequestID, rec, logs
+}
+
+// Feature: Request correlation
+// Scenario: Keep the caller request ID
+func Test_Middleware_WithRequestID_ShouldKeepIt(t *testing.T) {
+	// GIVEN a request carrying an ID
+	req := httptest.NewRequest(http.MethodGet, "/v1/order/1", nil)
+	req.Header.Set(logging.RequestIDHeader, "checkout-42")
+
+	// WHEN it is handled
+	requestID, rec, logs := serve(req)
+
+	// THEN the ID should be available to the handler and echoed back
+	assert.Equal(t, "checkout-42", requestID)
+	assert.Equal(t, "checkout-42", rec.Header().Get(logging.RequestIDHeader))
+
+	// AND every log line should carry it
+	require.Equal(t, 2, logs.Len())
+	for _, entry := range logs.All() {
+		assert.Equal(t, "checkout-42", entry.ContextMap()["request_id"])
+	}
+	access := logs.FilterMessage("request handled").All()
+	require.Len(t, access, 1)
+	assert.Equal(t, int64(http.StatusAccepted), access[0].ContextMap()["status"])
+	assert.Equal(t, "/v1/order/1", access[0].ContextMap()["path"])
+}
+
+// Scenario: Generate a request ID when missing or invalid
+func Test_Middleware_WithoutRequestID_ShouldGenerateOne(t *testing.T) {
+	// GIVEN a request without an ID
+	req := httptest.NewRequest(http.MethodGet, "/v1/order", nil)
+
+	// WHEN it is handled
+	requestID, rec, _ := serve(req)
+
+	// THEN an ID should be generated and echoed back
+	assert.Len(t, requestID, 32)
+	assert.Equal(t, requestID, rec.Header().Get(logging.RequestIDHeader))
+}
+
+func Test_Middleware_WithInvalidRequestID_ShouldReplaceIt(t *testing.T) {
+	for name, requestID := range map[string]string{
+		"too long":      strings.Repeat("a", 129),
+		"with spaces":   "forged entry",
+		"non ascii":     "pedido-ç",
+		"control chars": "id\x1b[31m",
+	} {
+		t.Run(name, func(t *testing.T) {
+			// GIVEN a request carrying an unusable ID
+			req := httptest.NewRequest(http.MethodGet, "/v1/order", nil)
+			req.Header.Set(logging.RequestIDHeader, requestID)
+
+			// WHEN it is handled
+			handlerRequestID, _, _ := serve(req)
+
+			// THEN a new ID should be used instead
+			assert.NotEqual(t, requestID, handlerRequestID)
+			assert.Len(t, handlerRequestID, 32)
+		})
+	}
+}
+
+func Test_FromContext_OutsideRequest_ShouldReturnGlobalLogger(t *testing.T) {
+	// GIVEN a context not created by the middleware
+	ctx := context.Background()
+
+	// WHEN its logger is asked
+	logger := logging.FromContext(ctx)
+
+	// THEN the global logger should be returned
+	assert.Same(t, zap.L(), logger)
+	assert.Empty(t, logging.RequestID(ctx))
+}
+
+// Scenario: Correlate logs with the trace
+func Test_Middleware_WithTracedRequest_ShouldLogTraceID(t *testing.T) {
+	// GIVEN a request handled in a trace
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/v1/order/1", nil).WithContext(ctx)
+
+	// WHEN it is handled
+	_, _, logs := serve(req)
+
+	// THEN every log line should carry the trace ID
+	require.Equal(t, 2, logs.Len())
+	for _, entry := range logs.All() {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry.ContextMap()["trace_id"])
+	}
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels requests that matched no route, so unknown paths
+// don't create a series each.
+const unmatchedRoute = "unmatched"
+
+// NewRegistry creates the registry exposed on /metrics, with the Go runtime
+// and process metrics already registered.
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+// Handler serves the metrics gathered by gatherer in the Prometheus format.
+func Handler(gatherer prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
+
+// HTTPMetrics records the latency of the requests served by the API.
+type HTTPMetrics struct {
+	duration *prometheus.HistogramVec
+}
+
+func NewHTTPMetrics(registerer prometheus.Registerer) *HTTPMetrics {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_server_request_duration_seconds",
+		Help:    "Latency of the HTTP requests served, by route pattern and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	registerer.MustRegister(duration)
+
+	return &HTTPMetrics{duration: duration}
+}
+
+// Middleware observes each request under the chi route pattern it matched
+// (e.g. /v1/order/{id}) rather than its path, keeping the label set bounded.
+func (m *HTTPMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := unmatchedRoute
+		if routeContext := chi.RouteContext(r.Context()); routeContext != nil && routeContext.RoutePattern() != "" {
+			route = routeContext.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		m.duration.WithLabelValues(r.Method, route, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,74 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/metrics"
+)
+
+func newTestRouter() *chi.Mux {
+	registry := metrics.NewRegistry()
+	router := chi.NewRouter()
+	router.Use(metrics.NewHTTPMetrics(registry).Middleware)
+	router.Get("/v1/order/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	router.Handle("/metrics", metrics.Handler(registry))
+	return router
+}
+
+func scrape(t *testing.T, handler http.Handler) string {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+// Feature: Metrics
+// Scenario: Observe requests by route pattern
+func Test_Middleware_ShouldObserveRoutePattern(t *testing.T) {
+	// GIVEN a router serving orders
+	router := newTestRouter()
+
+	// WHEN two different orders are requested
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/order/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/order/2", nil))
+
+	// THEN both should be counted under the route pattern and status
+	body := scrape(t, router)
+	assert.Contains(t, body, `http_server_request_duration_seconds_count{method="GET",route="/v1/order/{id}",status="404"} 2`)
+	assert.NotContains(t, body, `route="/v1/order/1"`)
+}
+
+func Test_Middleware_WithUnknownPath_ShouldUseUnmatchedRoute(t *testing.T) {
+	// GIVEN a router serving orders
+	router := newTestRouter()
+
+	// WHEN an unknown path is requested
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/wp-admin/login.php", nil))
+
+	// THEN it should not create a series for the path
+	body := scrape(t, router)
+	assert.Contains(t, body, `route="unmatched"`)
+	assert.NotContains(t, body, "wp-admin")
+}
+
+func Test_NewRegistry_ShouldExposeRuntimeMetrics(t *testing.T) {
+	// GIVEN a new registry
+	registry := metrics.NewRegistry()
+
+	// WHEN it is gathered
+	count, err := testutil.GatherAndCount(registry, "go_goroutines")
+
+	// THEN the Go runtime metrics should be included
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Contains(t, scrape(t, metrics.Handler(registry)), "# TYPE go_goroutines gauge")
+}
@@ -0,0 +1,176 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Currency is the ISO 4217 currency of every amount handled by the service.
+// It is neither stored nor accepted by the API.
+const Currency = "BRL"
+
+// minorUnitsPerMajor is the number of minor units (cents) in a major unit.
+const minorUnitsPerMajor = 100
+
+var (
+	ErrInvalidAmount = errors.New("invalid money amount")
+	ErrOverflow      = errors.New("money amount overflow")
+)
+
+// Money is an exact amount of money in Currency, stored as integer minor units
+// (cents). The zero value is zero, so values can be compared with == and used
+// as struct fields directly.
+//
+// It is persisted as a bigint column of minor units and serialized to JSON as
+// a decimal number (34.99), keeping the format of the former float fields.
+type Money struct {
+	amount int64
+}
+
+// FromMinor returns an amount in minor units.
+func FromMinor(amount int64) Money {
+	return Money{amount: amount}
+}
+
+// Parse parses a decimal amount ("34.99", "100", "1e2").
+// Amounts with more precision than the minor unit are rejected instead of rounded.
+func Parse(value string) (Money, error) {
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	rat.Mul(rat, big.NewRat(minorUnitsPerMajor, 1))
+	if !rat.IsInt() || !rat.Num().IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	return FromMinor(rat.Num().Int64()), nil
+}
+
+// MustParse is like Parse but panics on invalid input. Intended for constants and tests.
+func MustParse(value string) Money {
+	m, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Amount returns the amount in minor units.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// Add returns the sum of both amounts, or ErrOverflow if it does not fit in
+// an int64 of minor units.
+func (m Money) Add(other Money) (Money, error) {
+	sum := m.amount + other.amount
+	if (other.amount > 0 && sum < m.amount) || (other.amount < 0 && sum > m.amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrOverflow, m.Decimal(), other.Decimal())
+	}
+	return Money{amount: sum}, nil
+}
+
+// Multiply returns the amount multiplied by a quantity, or ErrOverflow if the
+// product does not fit in an int64 of minor units.
+func (m Money) Multiply(quantity int64) (Money, error) {
+	product := m.amount * quantity
+	if quantity != 0 && (product/quantity != m.amount || (quantity == -1 && m.amount == math.MinInt64)) {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrOverflow, m.Decimal(), quantity)
+	}
+	return Money{amount: product}, nil
+}
+
+// Decimal formats the amount as a decimal number with trailing zeros removed
+// ("34.99", "100.5", "100"), matching the JSON output of float amounts.
+func (m Money) Decimal() string {
+	amount := m.amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	major := amount / minorUnitsPerMajor
+	minor := amount % minorUnitsPerMajor
+	switch {
+	case minor == 0:
+		return fmt.Sprintf("%s%d", sign, major)
+	case minor%10 == 0:
+		return fmt.Sprintf("%s%d.%d", sign, major, minor/10)
+	default:
+		return fmt.Sprintf("%s%d.%02d", sign, major, minor)
+	}
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + Currency
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON accepts a decimal JSON number or a quoted decimal string.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	value := string(data)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// GormDataType stores money as a bigint column of minor units.
+func (Money) GormDataType() string {
+	return "bigint"
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.amount, nil
+}
+
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = Money{}
+	case int64:
+		*m = FromMinor(v)
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into Money", ErrInvalidAmount, value)
+	}
+	return nil
+}
+
+func (m *Money) scanString(value string) error {
+	amount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	*m = FromMinor(amount)
+	return nil
+}
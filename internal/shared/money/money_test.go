@@ -0,0 +1,178 @@
+package money_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/money"
+)
+
+// Feature: Money value type
+// Scenario: Parse decimal amounts exactly
+func Test_Parse(t *testing.T) {
+	testCases := []struct {
+		value string
+		minor int64
+	}{
+		{"34.99", 3499},
+		{"100", 10000},
+		{"100.5", 10050},
+		{"0.1", 10},
+		{"1e2", 10000},
+		{"-2.50", -250},
+		{"34.990", 3499},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			// WHEN the amount is parsed
+			m, err := money.Parse(tc.value)
+
+			// THEN the minor units should be exact
+			assert.NoError(t, err)
+			assert.Equal(t, tc.minor, m.Amount())
+		})
+	}
+}
+
+func Test_Parse_WithInvalidAmount_ShouldReturnError(t *testing.T) {
+	for _, value := range []string{"", "abc", "34.999", "1e-3"} {
+		// WHEN an invalid or over-precise amount is parsed
+		_, err := money.Parse(value)
+
+		// THEN an invalid amount error should be returned
+		assert.ErrorIs(t, err, money.ErrInvalidAmount, value)
+	}
+}
+
+// Scenario: Sums do not accumulate rounding errors
+func Test_Add_ShouldBeExact(t *testing.T) {
+	// GIVEN a price that is not representable as a float
+	price := money.MustParse("34.99")
+
+	// WHEN it is summed many times
+	total := money.Money{}
+	for i := 0; i < 1000; i++ {
+		var err error
+		total, err = total.Add(price)
+		assert.NoError(t, err)
+	}
+
+	// THEN the total should be exact
+	assert.Equal(t, money.MustParse("34990"), total)
+	product, err := price.Multiply(1000)
+	assert.NoError(t, err)
+	assert.Equal(t, product, total)
+}
+
+// Scenario: Amounts that do not fit in minor units are rejected
+func Test_Add_WithOverflow_ShouldReturnError(t *testing.T) {
+	for _, tc := range []struct{ a, b int64 }{
+		{math.MaxInt64, 1},
+		{math.MinInt64, -1},
+	} {
+		// WHEN amounts whose sum does not fit are added
+		_, err := money.FromMinor(tc.a).Add(money.FromMinor(tc.b))
+
+		// THEN an overflow error should be returned
+		assert.ErrorIs(t, err, money.ErrOverflow)
+	}
+}
+
+func Test_Multiply_WithOverflow_ShouldReturnError(t *testing.T) {
+	for _, tc := range []struct{ amount, quantity int64 }{
+		{math.MaxInt64 / 2, 3},
+		{math.MinInt64, -1},
+		{-2, math.MinInt64},
+	} {
+		// WHEN an amount is multiplied beyond what fits
+		_, err := money.FromMinor(tc.amount).Multiply(tc.quantity)
+
+		// THEN an overflow error should be returned
+		assert.ErrorIs(t, err, money.ErrOverflow)
+	}
+
+	// AND products that fit should be exact
+	product, err := money.FromMinor(-3499).Multiply(3)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromMinor(-10497), product)
+}
+
+// Scenario: The zero value is zero
+func Test_ZeroValue_ShouldBeZero(t *testing.T) {
+	assert.Equal(t, money.Money{}, money.FromMinor(0))
+	assert.True(t, money.Money{}.IsZero())
+	assert.Equal(t, "0 BRL", money.Money{}.String())
+}
+
+// Scenario: JSON keeps the decimal number format of the former float fields
+func Test_MarshalJSON_ShouldKeepDecimalFormat(t *testing.T) {
+	testCases := map[string]int64{
+		"34.99": 3499,
+		"100.5": 10050,
+		"100":   10000,
+		"0":     0,
+		"0.05":  5,
+		"-1.5":  -150,
+	}
+
+	for expected, minor := range testCases {
+		body, err := json.Marshal(money.FromMinor(minor))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(body))
+	}
+}
+
+func Test_UnmarshalJSON_ShouldAcceptNumbersAndStrings(t *testing.T) {
+	var payload struct {
+		Price money.Money `json:"price"`
+		Total money.Money `json:"total"`
+		Unset money.Money `json:"unset"`
+	}
+
+	// WHEN a payload with a number, a string and a null is decoded
+	err := json.Unmarshal([]byte(`{"price": 34.99, "total": "104.95", "unset": null}`), &payload)
+
+	// THEN every amount should be decoded exactly
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3499), payload.Price.Amount())
+	assert.Equal(t, int64(10495), payload.Total.Amount())
+	assert.True(t, payload.Unset.IsZero())
+}
+
+func Test_UnmarshalJSON_WithOverPreciseAmount_ShouldReturnError(t *testing.T) {
+	var m money.Money
+
+	err := json.Unmarshal([]byte(`34.999`), &m)
+
+	assert.ErrorIs(t, err, money.ErrInvalidAmount)
+}
+
+// Scenario: Persist as integer minor units
+func Test_ValueAndScan_ShouldRoundTripMinorUnits(t *testing.T) {
+	// WHEN an amount is written to the database
+	value, err := money.MustParse("34.99").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3499), value)
+
+	// AND read back from the driver representations
+	for _, raw := range []interface{}{int64(3499), []byte("3499"), "3499"} {
+		var m money.Money
+		assert.NoError(t, m.Scan(raw))
+		assert.Equal(t, money.MustParse("34.99"), m)
+	}
+}
+
+func Test_Scan_WithFloat_ShouldReturnError(t *testing.T) {
+	// GIVEN a column that still holds floats (not migrated)
+	var m money.Money
+
+	// WHEN it is scanned
+	err := m.Scan(float64(34.99))
+
+	// THEN the value should be rejected instead of silently rounded
+	assert.ErrorIs(t, err, money.ErrInvalidAmount)
+}
@@ -0,0 +1,150 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing/tracingtest"
+)
+
+func newTestRouter(status int) *chi.Mux {
+	router := chi.NewRouter()
+	router.Use(tracing.Middleware)
+	router.Get("/v1/order/{id}", func(w http.ResponseWriter, r *http.Request) {
+		// A span started by the handler, as the use cases do
+		_, span := tracing.Start(r.Context(), "GetOrderUseCase.Execute")
+		span.End()
+		w.WriteHeader(status)
+	})
+	return router
+}
+
+// Feature: Tracing
+// Scenario: Name server spans by route pattern
+func Test_Middleware_ShouldNameSpanByRoutePattern(t *testing.T) {
+	// GIVEN a traced router serving orders
+	recorder := tracingtest.NewRecorder(t)
+	router := newTestRouter(http.StatusOK)
+
+	// WHEN an order is requested
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/order/42", nil))
+
+	// THEN the server span should be named after the route, not the path
+	server := tracingtest.SpanNamed(recorder, "GET /v1/order/{id}")
+	require.NotNil(t, server)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Contains(t, server.Attributes(), semconv.HTTPRoute("/v1/order/{id}"))
+	assert.Contains(t, server.Attributes(), semconv.URLPath("/v1/order/42"))
+	assert.Contains(t, server.Attributes(), semconv.HTTPResponseStatusCode(http.StatusOK))
+	assert.Equal(t, codes.Unset, server.Status().Code)
+	// AND the spans of the handler should be its children
+	useCase := tracingtest.SpanNamed(recorder, "GetOrderUseCase.Execute")
+	require.NotNil(t, useCase)
+	assert.Equal(t, server.SpanContext().SpanID(), useCase.Parent().SpanID())
+}
+
+// Scenario: Continue the caller's trace
+func Test_Middleware_WithTraceparent_ShouldContinueTrace(t *testing.T) {
+	// GIVEN a traced router serving orders
+	recorder := tracingtest.NewRecorder(t)
+	router := newTestRouter(http.StatusOK)
+
+	// WHEN an order is requested by a traced caller
+	request := httptest.NewRequest(http.MethodGet, "/v1/order/42", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	// THEN the server span should join the caller's trace
+	server := tracingtest.SpanNamed(recorder, "GET /v1/order/{id}")
+	require.NotNil(t, server)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.True(t, server.Parent().IsRemote())
+}
+
+// Scenario: Mark server errors only
+func Test_Middleware_WithServerError_ShouldMarkSpanAsError(t *testing.T) {
+	for _, tc := range []struct {
+		status   int
+		expected codes.Code
+	}{
+		{http.StatusNotFound, codes.Unset},
+		{http.StatusServiceUnavailable, codes.Error},
+	} {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			// GIVEN a traced router answering with the status
+			recorder := tracingtest.NewRecorder(t)
+			router := newTestRouter(tc.status)
+
+			// WHEN an order is requested
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/order/42", nil))
+
+			// THEN only server errors should fail the span
+			server := tracingtest.SpanNamed(recorder, "GET /v1/order/{id}")
+			require.NotNil(t, server)
+			assert.Equal(t, tc.expected, server.Status().Code)
+		})
+	}
+}
+
+// Feature: Tracer provider
+// Scenario: Choose the exporter
+func Test_NewTracerProvider_WithUnknownExporter_ShouldFail(t *testing.T) {
+	// GIVEN an exporter that doesn't exist
+	settings := tracing.Settings{Exporter: "zipkin", ServiceName: "order-service", SampleRatio: 1}
+
+	// WHEN the tracer provider is created
+	provider, err := tracing.NewTracerProvider(context.Background(), settings)
+
+	// THEN it should be rejected
+	assert.Nil(t, provider)
+	assert.ErrorContains(t, err, `invalid tracing exporter "zipkin"`)
+}
+
+func Test_NewTracerProvider_WithNoExporter_ShouldRecordNothing(t *testing.T) {
+	// GIVEN tracing disabled
+	provider, err := tracing.NewTracerProvider(context.Background(), tracing.Settings{Exporter: tracing.ExporterNone, ServiceName: "order-service"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	// WHEN a span is started
+	_, span := provider.Tracer("test").Start(context.Background(), "GetOrderUseCase.Execute")
+	defer span.End()
+
+	// THEN it should not be recorded
+	assert.False(t, span.IsRecording())
+	assert.False(t, span.SpanContext().IsSampled())
+}
+
+func Test_NewTracerProvider_WithNoExporter_ShouldKeepCallerSamplingDecision(t *testing.T) {
+	// GIVEN tracing disabled
+	provider, err := tracing.NewTracerProvider(context.Background(), tracing.Settings{Exporter: tracing.ExporterNone, ServiceName: "order-service"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	// AND a caller that sampled its trace
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled, Remote: true,
+	}))
+
+	// WHEN a span is started in the caller's trace
+	_, span := provider.Tracer("test").Start(ctx, "GetOrderUseCase.Execute")
+	defer span.End()
+
+	// THEN it should stay sampled, so the trace is propagated intact
+	assert.Equal(t, traceID, span.SpanContext().TraceID())
+	assert.True(t, span.SpanContext().IsSampled())
+}
@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans created by this service.
+const instrumentationName = "github.com/viniciuscluna/tc-fiap-50"
+
+const (
+	// ExporterOTLP sends the spans to an OpenTelemetry collector over OTLP/HTTP
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes the spans to stdout, one JSON document per span
+	ExporterStdout = "stdout"
+	// ExporterNone records nothing; incoming trace context is still propagated
+	ExporterNone = "none"
+)
+
+// Settings configures the tracer provider.
+type Settings struct {
+	// Exporter is one of ExporterOTLP, ExporterStdout or ExporterNone
+	Exporter string
+	// ServiceName is reported as service.name on every span
+	ServiceName string
+	// OTLPEndpoint is the collector URL (e.g. http://otel-collector:4318); when
+	// empty the OTEL_EXPORTER_OTLP_* variables or the exporter default apply
+	OTLPEndpoint string
+	// SampleRatio is the share of new traces recorded (0 to 1); traces started
+	// by the caller follow the caller's sampling decision
+	SampleRatio float64
+}
+
+// NewTracerProvider creates the tracer provider exporting spans as configured
+// by settings. It must be shut down to flush the spans still buffered.
+func NewTracerProvider(ctx context.Context, settings Settings) (*sdktrace.TracerProvider, error) {
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(settings.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	switch settings.Exporter {
+	case ExporterOTLP:
+		var exporterOptions []otlptracehttp.Option
+		if settings.OTLPEndpoint != "" {
+			exporterOptions = append(exporterOptions, otlptracehttp.WithEndpointURL(settings.OTLPEndpoint))
+		}
+		exporter, err := otlptracehttp.New(ctx, exporterOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		options = append(options,
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(settings.SampleRatio))),
+		)
+	case ExporterStdout:
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		options = append(options,
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(settings.SampleRatio))),
+		)
+	case ExporterNone:
+		// Nothing is exported, but the caller's sampling decision is kept so
+		// the services called next still record its trace
+		options = append(options, sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.NeverSample())))
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %q (expected %s, %s or %s)",
+			settings.Exporter, ExporterOTLP, ExporterStdout, ExporterNone)
+	}
+
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// Propagator reads and writes the W3C traceparent, tracestate and baggage headers.
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// Start starts a span as a child of the span in ctx, if any, using the global
+// tracer provider.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End records err, if any, as the failure of span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
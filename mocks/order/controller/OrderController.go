@@ -3,8 +3,11 @@
 package mocks
 
 import (
-	mock "github.com/stretchr/testify/mock"
+	context "context"
+
 	dto "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // MockOrderController is an autogenerated mock type for the OrderController type
@@ -20,9 +23,9 @@ func (_m *MockOrderController) EXPECT() *MockOrderController_Expecter {
 	return &MockOrderController_Expecter{mock: &_m.Mock}
 }
 
-// Add provides a mock function with given fields: addOrderRequest
-func (_m *MockOrderController) Add(addOrderRequest *dto.AddOrderDto) (string, error) {
-	ret := _m.Called(addOrderRequest)
+// Add provides a mock function with given fields: ctx, addOrderRequest, idempotencyKey, requestHash
+func (_m *MockOrderController) Add(ctx context.Context, addOrderRequest *dto.AddOrderDto, idempotencyKey string, requestHash string) (string, error) {
+	ret := _m.Called(ctx, addOrderRequest, idempotencyKey, requestHash)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Add")
@@ -30,17 +33,17 @@ func (_m *MockOrderController) Add(addOrderRequest *dto.AddOrderDto) (string, er
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*dto.AddOrderDto) (string, error)); ok {
-		return rf(addOrderRequest)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.AddOrderDto, string, string) (string, error)); ok {
+		return rf(ctx, addOrderRequest, idempotencyKey, requestHash)
 	}
-	if rf, ok := ret.Get(0).(func(*dto.AddOrderDto) string); ok {
-		r0 = rf(addOrderRequest)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.AddOrderDto, string, string) string); ok {
+		r0 = rf(ctx, addOrderRequest, idempotencyKey, requestHash)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*dto.AddOrderDto) error); ok {
-		r1 = rf(addOrderRequest)
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.AddOrderDto, string, string) error); ok {
+		r1 = rf(ctx, addOrderRequest, idempotencyKey, requestHash)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -54,14 +57,17 @@ type MockOrderController_Add_Call struct {
 }
 
 // Add is a helper method to define mock.On call
+//   - ctx context.Context
 //   - addOrderRequest *dto.AddOrderDto
-func (_e *MockOrderController_Expecter) Add(addOrderRequest interface{}) *MockOrderController_Add_Call {
-	return &MockOrderController_Add_Call{Call: _e.mock.On("Add", addOrderRequest)}
+//   - idempotencyKey string
+//   - requestHash string
+func (_e *MockOrderController_Expecter) Add(ctx interface{}, addOrderRequest interface{}, idempotencyKey interface{}, requestHash interface{}) *MockOrderController_Add_Call {
+	return &MockOrderController_Add_Call{Call: _e.mock.On("Add", ctx, addOrderRequest, idempotencyKey, requestHash)}
 }
 
-func (_c *MockOrderController_Add_Call) Run(run func(addOrderRequest *dto.AddOrderDto)) *MockOrderController_Add_Call {
+func (_c *MockOrderController_Add_Call) Run(run func(ctx context.Context, addOrderRequest *dto.AddOrderDto, idempotencyKey string, requestHash string)) *MockOrderController_Add_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*dto.AddOrderDto))
+		run(args[0].(context.Context), args[1].(*dto.AddOrderDto), args[2].(string), args[3].(string))
 	})
 	return _c
 }
@@ -71,14 +77,62 @@ func (_c *MockOrderController_Add_Call) Return(_a0 string, _a1 error) *MockOrder
 	return _c
 }
 
-func (_c *MockOrderController_Add_Call) RunAndReturn(run func(*dto.AddOrderDto) (string, error)) *MockOrderController_Add_Call {
+func (_c *MockOrderController_Add_Call) RunAndReturn(run func(context.Context, *dto.AddOrderDto, string, string) (string, error)) *MockOrderController_Add_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelOrder provides a mock function with given fields: ctx, orderId, cancelOrderRequest
+func (_m *MockOrderController) CancelOrder(ctx context.Context, orderId uint, cancelOrderRequest *dto.CancelOrderRequestDto) error {
+	ret := _m.Called(ctx, orderId, cancelOrderRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *dto.CancelOrderRequestDto) error); ok {
+		r0 = rf(ctx, orderId, cancelOrderRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOrderController_CancelOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelOrder'
+type MockOrderController_CancelOrder_Call struct {
+	*mock.Call
+}
+
+// CancelOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderId uint
+//   - cancelOrderRequest *dto.CancelOrderRequestDto
+func (_e *MockOrderController_Expecter) CancelOrder(ctx interface{}, orderId interface{}, cancelOrderRequest interface{}) *MockOrderController_CancelOrder_Call {
+	return &MockOrderController_CancelOrder_Call{Call: _e.mock.On("CancelOrder", ctx, orderId, cancelOrderRequest)}
+}
+
+func (_c *MockOrderController_CancelOrder_Call) Run(run func(ctx context.Context, orderId uint, cancelOrderRequest *dto.CancelOrderRequestDto)) *MockOrderController_CancelOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(*dto.CancelOrderRequestDto))
+	})
+	return _c
+}
+
+func (_c *MockOrderController_CancelOrder_Call) Return(_a0 error) *MockOrderController_CancelOrder_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOrderController_CancelOrder_Call) RunAndReturn(run func(context.Context, uint, *dto.CancelOrderRequestDto) error) *MockOrderController_CancelOrder_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOrder provides a mock function with given fields: orderId
-func (_m *MockOrderController) GetOrder(orderId uint) (*dto.GetOrderResponseDto, error) {
-	ret := _m.Called(orderId)
+// GetOrder provides a mock function with given fields: ctx, orderId
+func (_m *MockOrderController) GetOrder(ctx context.Context, orderId uint) (*dto.GetOrderResponseDto, error) {
+	ret := _m.Called(ctx, orderId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrder")
@@ -86,19 +140,19 @@ func (_m *MockOrderController) GetOrder(orderId uint) (*dto.GetOrderResponseDto,
 
 	var r0 *dto.GetOrderResponseDto
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (*dto.GetOrderResponseDto, error)); ok {
-		return rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*dto.GetOrderResponseDto, error)); ok {
+		return rf(ctx, orderId)
 	}
-	if rf, ok := ret.Get(0).(func(uint) *dto.GetOrderResponseDto); ok {
-		r0 = rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *dto.GetOrderResponseDto); ok {
+		r0 = rf(ctx, orderId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*dto.GetOrderResponseDto)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(orderId)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, orderId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -112,14 +166,15 @@ type MockOrderController_GetOrder_Call struct {
 }
 
 // GetOrder is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orderId uint
-func (_e *MockOrderController_Expecter) GetOrder(orderId interface{}) *MockOrderController_GetOrder_Call {
-	return &MockOrderController_GetOrder_Call{Call: _e.mock.On("GetOrder", orderId)}
+func (_e *MockOrderController_Expecter) GetOrder(ctx interface{}, orderId interface{}) *MockOrderController_GetOrder_Call {
+	return &MockOrderController_GetOrder_Call{Call: _e.mock.On("GetOrder", ctx, orderId)}
 }
 
-func (_c *MockOrderController_GetOrder_Call) Run(run func(orderId uint)) *MockOrderController_GetOrder_Call {
+func (_c *MockOrderController_GetOrder_Call) Run(run func(ctx context.Context, orderId uint)) *MockOrderController_GetOrder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint))
+		run(args[0].(context.Context), args[1].(uint))
 	})
 	return _c
 }
@@ -129,14 +184,14 @@ func (_c *MockOrderController_GetOrder_Call) Return(_a0 *dto.GetOrderResponseDto
 	return _c
 }
 
-func (_c *MockOrderController_GetOrder_Call) RunAndReturn(run func(uint) (*dto.GetOrderResponseDto, error)) *MockOrderController_GetOrder_Call {
+func (_c *MockOrderController_GetOrder_Call) RunAndReturn(run func(context.Context, uint) (*dto.GetOrderResponseDto, error)) *MockOrderController_GetOrder_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOrderStatus provides a mock function with given fields: orderId
-func (_m *MockOrderController) GetOrderStatus(orderId uint) (*dto.GetOrderStatusResponseDto, error) {
-	ret := _m.Called(orderId)
+// GetOrderStatus provides a mock function with given fields: ctx, orderId
+func (_m *MockOrderController) GetOrderStatus(ctx context.Context, orderId uint) (*dto.GetOrderStatusResponseDto, error) {
+	ret := _m.Called(ctx, orderId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrderStatus")
@@ -144,19 +199,19 @@ func (_m *MockOrderController) GetOrderStatus(orderId uint) (*dto.GetOrderStatus
 
 	var r0 *dto.GetOrderStatusResponseDto
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (*dto.GetOrderStatusResponseDto, error)); ok {
-		return rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*dto.GetOrderStatusResponseDto, error)); ok {
+		return rf(ctx, orderId)
 	}
-	if rf, ok := ret.Get(0).(func(uint) *dto.GetOrderStatusResponseDto); ok {
-		r0 = rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *dto.GetOrderStatusResponseDto); ok {
+		r0 = rf(ctx, orderId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*dto.GetOrderStatusResponseDto)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(orderId)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, orderId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -170,14 +225,15 @@ type MockOrderController_GetOrderStatus_Call struct {
 }
 
 // GetOrderStatus is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orderId uint
-func (_e *MockOrderController_Expecter) GetOrderStatus(orderId interface{}) *MockOrderController_GetOrderStatus_Call {
-	return &MockOrderController_GetOrderStatus_Call{Call: _e.mock.On("GetOrderStatus", orderId)}
+func (_e *MockOrderController_Expecter) GetOrderStatus(ctx interface{}, orderId interface{}) *MockOrderController_GetOrderStatus_Call {
+	return &MockOrderController_GetOrderStatus_Call{Call: _e.mock.On("GetOrderStatus", ctx, orderId)}
 }
 
-func (_c *MockOrderController_GetOrderStatus_Call) Run(run func(orderId uint)) *MockOrderController_GetOrderStatus_Call {
+func (_c *MockOrderController_GetOrderStatus_Call) Run(run func(ctx context.Context, orderId uint)) *MockOrderController_GetOrderStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint))
+		run(args[0].(context.Context), args[1].(uint))
 	})
 	return _c
 }
@@ -187,14 +243,14 @@ func (_c *MockOrderController_GetOrderStatus_Call) Return(_a0 *dto.GetOrderStatu
 	return _c
 }
 
-func (_c *MockOrderController_GetOrderStatus_Call) RunAndReturn(run func(uint) (*dto.GetOrderStatusResponseDto, error)) *MockOrderController_GetOrderStatus_Call {
+func (_c *MockOrderController_GetOrderStatus_Call) RunAndReturn(run func(context.Context, uint) (*dto.GetOrderStatusResponseDto, error)) *MockOrderController_GetOrderStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOrders provides a mock function with no fields
-func (_m *MockOrderController) GetOrders() (*dto.GetOrdersResponseDto, error) {
-	ret := _m.Called()
+// GetOrders provides a mock function with given fields: ctx
+func (_m *MockOrderController) GetOrders(ctx context.Context) (*dto.GetOrdersResponseDto, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrders")
@@ -202,19 +258,19 @@ func (_m *MockOrderController) GetOrders() (*dto.GetOrdersResponseDto, error) {
 
 	var r0 *dto.GetOrdersResponseDto
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (*dto.GetOrdersResponseDto, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) (*dto.GetOrdersResponseDto, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() *dto.GetOrdersResponseDto); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) *dto.GetOrdersResponseDto); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*dto.GetOrdersResponseDto)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -228,13 +284,14 @@ type MockOrderController_GetOrders_Call struct {
 }
 
 // GetOrders is a helper method to define mock.On call
-func (_e *MockOrderController_Expecter) GetOrders() *MockOrderController_GetOrders_Call {
-	return &MockOrderController_GetOrders_Call{Call: _e.mock.On("GetOrders")}
+//   - ctx context.Context
+func (_e *MockOrderController_Expecter) GetOrders(ctx interface{}) *MockOrderController_GetOrders_Call {
+	return &MockOrderController_GetOrders_Call{Call: _e.mock.On("GetOrders", ctx)}
 }
 
-func (_c *MockOrderController_GetOrders_Call) Run(run func()) *MockOrderController_GetOrders_Call {
+func (_c *MockOrderController_GetOrders_Call) Run(run func(ctx context.Context)) *MockOrderController_GetOrders_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context))
 	})
 	return _c
 }
@@ -244,22 +301,22 @@ func (_c *MockOrderController_GetOrders_Call) Return(_a0 *dto.GetOrdersResponseD
 	return _c
 }
 
-func (_c *MockOrderController_GetOrders_Call) RunAndReturn(run func() (*dto.GetOrdersResponseDto, error)) *MockOrderController_GetOrders_Call {
+func (_c *MockOrderController_GetOrders_Call) RunAndReturn(run func(context.Context) (*dto.GetOrdersResponseDto, error)) *MockOrderController_GetOrders_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateOrderStatus provides a mock function with given fields: orderId, updateOrderStatusRequest
-func (_m *MockOrderController) UpdateOrderStatus(orderId uint, updateOrderStatusRequest *dto.UpdateOrderStatusRequestDto) error {
-	ret := _m.Called(orderId, updateOrderStatusRequest)
+// UpdateOrderStatus provides a mock function with given fields: ctx, orderId, updateOrderStatusRequest
+func (_m *MockOrderController) UpdateOrderStatus(ctx context.Context, orderId uint, updateOrderStatusRequest *dto.UpdateOrderStatusRequestDto) error {
+	ret := _m.Called(ctx, orderId, updateOrderStatusRequest)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateOrderStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint, *dto.UpdateOrderStatusRequestDto) error); ok {
-		r0 = rf(orderId, updateOrderStatusRequest)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *dto.UpdateOrderStatusRequestDto) error); ok {
+		r0 = rf(ctx, orderId, updateOrderStatusRequest)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -273,15 +330,16 @@ type MockOrderController_UpdateOrderStatus_Call struct {
 }
 
 // UpdateOrderStatus is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orderId uint
 //   - updateOrderStatusRequest *dto.UpdateOrderStatusRequestDto
-func (_e *MockOrderController_Expecter) UpdateOrderStatus(orderId interface{}, updateOrderStatusRequest interface{}) *MockOrderController_UpdateOrderStatus_Call {
-	return &MockOrderController_UpdateOrderStatus_Call{Call: _e.mock.On("UpdateOrderStatus", orderId, updateOrderStatusRequest)}
+func (_e *MockOrderController_Expecter) UpdateOrderStatus(ctx interface{}, orderId interface{}, updateOrderStatusRequest interface{}) *MockOrderController_UpdateOrderStatus_Call {
+	return &MockOrderController_UpdateOrderStatus_Call{Call: _e.mock.On("UpdateOrderStatus", ctx, orderId, updateOrderStatusRequest)}
 }
 
-func (_c *MockOrderController_UpdateOrderStatus_Call) Run(run func(orderId uint, updateOrderStatusRequest *dto.UpdateOrderStatusRequestDto)) *MockOrderController_UpdateOrderStatus_Call {
+func (_c *MockOrderController_UpdateOrderStatus_Call) Run(run func(ctx context.Context, orderId uint, updateOrderStatusRequest *dto.UpdateOrderStatusRequestDto)) *MockOrderController_UpdateOrderStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint), args[1].(*dto.UpdateOrderStatusRequestDto))
+		run(args[0].(context.Context), args[1].(uint), args[2].(*dto.UpdateOrderStatusRequestDto))
 	})
 	return _c
 }
@@ -291,7 +349,7 @@ func (_c *MockOrderController_UpdateOrderStatus_Call) Return(_a0 error) *MockOrd
 	return _c
 }
 
-func (_c *MockOrderController_UpdateOrderStatus_Call) RunAndReturn(run func(uint, *dto.UpdateOrderStatusRequestDto) error) *MockOrderController_UpdateOrderStatus_Call {
+func (_c *MockOrderController_UpdateOrderStatus_Call) RunAndReturn(run func(context.Context, uint, *dto.UpdateOrderStatusRequestDto) error) *MockOrderController_UpdateOrderStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
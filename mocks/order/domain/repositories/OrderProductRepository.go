@@ -3,8 +3,11 @@
 package mocks
 
 import (
-	mock "github.com/stretchr/testify/mock"
+	context "context"
+
 	entities "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // MockOrderProductRepository is an autogenerated mock type for the OrderProductRepository type
@@ -20,17 +23,17 @@ func (_m *MockOrderProductRepository) EXPECT() *MockOrderProductRepository_Expec
 	return &MockOrderProductRepository_Expecter{mock: &_m.Mock}
 }
 
-// AddOrderProduct provides a mock function with given fields: orderProduct
-func (_m *MockOrderProductRepository) AddOrderProduct(orderProduct *entities.OrderProductEntity) error {
-	ret := _m.Called(orderProduct)
+// AddOrderProduct provides a mock function with given fields: ctx, orderProduct
+func (_m *MockOrderProductRepository) AddOrderProduct(ctx context.Context, orderProduct *entities.OrderProductEntity) error {
+	ret := _m.Called(ctx, orderProduct)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddOrderProduct")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entities.OrderProductEntity) error); ok {
-		r0 = rf(orderProduct)
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.OrderProductEntity) error); ok {
+		r0 = rf(ctx, orderProduct)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -44,14 +47,15 @@ type MockOrderProductRepository_AddOrderProduct_Call struct {
 }
 
 // AddOrderProduct is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orderProduct *entities.OrderProductEntity
-func (_e *MockOrderProductRepository_Expecter) AddOrderProduct(orderProduct interface{}) *MockOrderProductRepository_AddOrderProduct_Call {
-	return &MockOrderProductRepository_AddOrderProduct_Call{Call: _e.mock.On("AddOrderProduct", orderProduct)}
+func (_e *MockOrderProductRepository_Expecter) AddOrderProduct(ctx interface{}, orderProduct interface{}) *MockOrderProductRepository_AddOrderProduct_Call {
+	return &MockOrderProductRepository_AddOrderProduct_Call{Call: _e.mock.On("AddOrderProduct", ctx, orderProduct)}
 }
 
-func (_c *MockOrderProductRepository_AddOrderProduct_Call) Run(run func(orderProduct *entities.OrderProductEntity)) *MockOrderProductRepository_AddOrderProduct_Call {
+func (_c *MockOrderProductRepository_AddOrderProduct_Call) Run(run func(ctx context.Context, orderProduct *entities.OrderProductEntity)) *MockOrderProductRepository_AddOrderProduct_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*entities.OrderProductEntity))
+		run(args[0].(context.Context), args[1].(*entities.OrderProductEntity))
 	})
 	return _c
 }
@@ -61,7 +65,7 @@ func (_c *MockOrderProductRepository_AddOrderProduct_Call) Return(_a0 error) *Mo
 	return _c
 }
 
-func (_c *MockOrderProductRepository_AddOrderProduct_Call) RunAndReturn(run func(*entities.OrderProductEntity) error) *MockOrderProductRepository_AddOrderProduct_Call {
+func (_c *MockOrderProductRepository_AddOrderProduct_Call) RunAndReturn(run func(context.Context, *entities.OrderProductEntity) error) *MockOrderProductRepository_AddOrderProduct_Call {
 	_c.Call.Return(run)
 	return _c
 }
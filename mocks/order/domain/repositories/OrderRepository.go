@@ -3,8 +3,11 @@
 package mocks
 
 import (
-	mock "github.com/stretchr/testify/mock"
+	context "context"
+
 	entities "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // MockOrderRepository is an autogenerated mock type for the OrderRepository type
@@ -20,9 +23,9 @@ func (_m *MockOrderRepository) EXPECT() *MockOrderRepository_Expecter {
 	return &MockOrderRepository_Expecter{mock: &_m.Mock}
 }
 
-// AddOrder provides a mock function with given fields: order
-func (_m *MockOrderRepository) AddOrder(order *entities.OrderEntity) (*entities.OrderEntity, error) {
-	ret := _m.Called(order)
+// AddOrder provides a mock function with given fields: ctx, order
+func (_m *MockOrderRepository) AddOrder(ctx context.Context, order *entities.OrderEntity) (*entities.OrderEntity, error) {
+	ret := _m.Called(ctx, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddOrder")
@@ -30,19 +33,19 @@ func (_m *MockOrderRepository) AddOrder(order *entities.OrderEntity) (*entities.
 
 	var r0 *entities.OrderEntity
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entities.OrderEntity) (*entities.OrderEntity, error)); ok {
-		return rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.OrderEntity) (*entities.OrderEntity, error)); ok {
+		return rf(ctx, order)
 	}
-	if rf, ok := ret.Get(0).(func(*entities.OrderEntity) *entities.OrderEntity); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.OrderEntity) *entities.OrderEntity); ok {
+		r0 = rf(ctx, order)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entities.OrderEntity)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entities.OrderEntity) error); ok {
-		r1 = rf(order)
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.OrderEntity) error); ok {
+		r1 = rf(ctx, order)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -56,14 +59,15 @@ type MockOrderRepository_AddOrder_Call struct {
 }
 
 // AddOrder is a helper method to define mock.On call
+//   - ctx context.Context
 //   - order *entities.OrderEntity
-func (_e *MockOrderRepository_Expecter) AddOrder(order interface{}) *MockOrderRepository_AddOrder_Call {
-	return &MockOrderRepository_AddOrder_Call{Call: _e.mock.On("AddOrder", order)}
+func (_e *MockOrderRepository_Expecter) AddOrder(ctx interface{}, order interface{}) *MockOrderRepository_AddOrder_Call {
+	return &MockOrderRepository_AddOrder_Call{Call: _e.mock.On("AddOrder", ctx, order)}
 }
 
-func (_c *MockOrderRepository_AddOrder_Call) Run(run func(order *entities.OrderEntity)) *MockOrderRepository_AddOrder_Call {
+func (_c *MockOrderRepository_AddOrder_Call) Run(run func(ctx context.Context, order *entities.OrderEntity)) *MockOrderRepository_AddOrder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*entities.OrderEntity))
+		run(args[0].(context.Context), args[1].(*entities.OrderEntity))
 	})
 	return _c
 }
@@ -73,14 +77,14 @@ func (_c *MockOrderRepository_AddOrder_Call) Return(_a0 *entities.OrderEntity, _
 	return _c
 }
 
-func (_c *MockOrderRepository_AddOrder_Call) RunAndReturn(run func(*entities.OrderEntity) (*entities.OrderEntity, error)) *MockOrderRepository_AddOrder_Call {
+func (_c *MockOrderRepository_AddOrder_Call) RunAndReturn(run func(context.Context, *entities.OrderEntity) (*entities.OrderEntity, error)) *MockOrderRepository_AddOrder_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOrder provides a mock function with given fields: orderId
-func (_m *MockOrderRepository) GetOrder(orderId uint) (*entities.OrderEntity, error) {
-	ret := _m.Called(orderId)
+// GetOrder provides a mock function with given fields: ctx, orderId
+func (_m *MockOrderRepository) GetOrder(ctx context.Context, orderId uint) (*entities.OrderEntity, error) {
+	ret := _m.Called(ctx, orderId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrder")
@@ -88,19 +92,19 @@ func (_m *MockOrderRepository) GetOrder(orderId uint) (*entities.OrderEntity, er
 
 	var r0 *entities.OrderEntity
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (*entities.OrderEntity, error)); ok {
-		return rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.OrderEntity, error)); ok {
+		return rf(ctx, orderId)
 	}
-	if rf, ok := ret.Get(0).(func(uint) *entities.OrderEntity); ok {
-		r0 = rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.OrderEntity); ok {
+		r0 = rf(ctx, orderId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entities.OrderEntity)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(orderId)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, orderId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -114,14 +118,15 @@ type MockOrderRepository_GetOrder_Call struct {
 }
 
 // GetOrder is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orderId uint
-func (_e *MockOrderRepository_Expecter) GetOrder(orderId interface{}) *MockOrderRepository_GetOrder_Call {
-	return &MockOrderRepository_GetOrder_Call{Call: _e.mock.On("GetOrder", orderId)}
+func (_e *MockOrderRepository_Expecter) GetOrder(ctx interface{}, orderId interface{}) *MockOrderRepository_GetOrder_Call {
+	return &MockOrderRepository_GetOrder_Call{Call: _e.mock.On("GetOrder", ctx, orderId)}
 }
 
-func (_c *MockOrderRepository_GetOrder_Call) Run(run func(orderId uint)) *MockOrderRepository_GetOrder_Call {
+func (_c *MockOrderRepository_GetOrder_Call) Run(run func(ctx context.Context, orderId uint)) *MockOrderRepository_GetOrder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint))
+		run(args[0].(context.Context), args[1].(uint))
 	})
 	return _c
 }
@@ -131,14 +136,14 @@ func (_c *MockOrderRepository_GetOrder_Call) Return(_a0 *entities.OrderEntity, _
 	return _c
 }
 
-func (_c *MockOrderRepository_GetOrder_Call) RunAndReturn(run func(uint) (*entities.OrderEntity, error)) *MockOrderRepository_GetOrder_Call {
+func (_c *MockOrderRepository_GetOrder_Call) RunAndReturn(run func(context.Context, uint) (*entities.OrderEntity, error)) *MockOrderRepository_GetOrder_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOrders provides a mock function with no fields
-func (_m *MockOrderRepository) GetOrders() ([]*entities.OrderEntity, error) {
-	ret := _m.Called()
+// GetOrders provides a mock function with given fields: ctx
+func (_m *MockOrderRepository) GetOrders(ctx context.Context) ([]*entities.OrderEntity, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrders")
@@ -146,19 +151,19 @@ func (_m *MockOrderRepository) GetOrders() ([]*entities.OrderEntity, error) {
 
 	var r0 []*entities.OrderEntity
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]*entities.OrderEntity, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entities.OrderEntity, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []*entities.OrderEntity); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*entities.OrderEntity); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*entities.OrderEntity)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -172,13 +177,14 @@ type MockOrderRepository_GetOrders_Call struct {
 }
 
 // GetOrders is a helper method to define mock.On call
-func (_e *MockOrderRepository_Expecter) GetOrders() *MockOrderRepository_GetOrders_Call {
-	return &MockOrderRepository_GetOrders_Call{Call: _e.mock.On("GetOrders")}
+//   - ctx context.Context
+func (_e *MockOrderRepository_Expecter) GetOrders(ctx interface{}) *MockOrderRepository_GetOrders_Call {
+	return &MockOrderRepository_GetOrders_Call{Call: _e.mock.On("GetOrders", ctx)}
 }
 
-func (_c *MockOrderRepository_GetOrders_Call) Run(run func()) *MockOrderRepository_GetOrders_Call {
+func (_c *MockOrderRepository_GetOrders_Call) Run(run func(ctx context.Context)) *MockOrderRepository_GetOrders_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context))
 	})
 	return _c
 }
@@ -188,7 +194,7 @@ func (_c *MockOrderRepository_GetOrders_Call) Return(_a0 []*entities.OrderEntity
 	return _c
 }
 
-func (_c *MockOrderRepository_GetOrders_Call) RunAndReturn(run func() ([]*entities.OrderEntity, error)) *MockOrderRepository_GetOrders_Call {
+func (_c *MockOrderRepository_GetOrders_Call) RunAndReturn(run func(context.Context) ([]*entities.OrderEntity, error)) *MockOrderRepository_GetOrders_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -3,8 +3,11 @@
 package mocks
 
 import (
-	mock "github.com/stretchr/testify/mock"
+	context "context"
+
 	entities "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // MockOrderStatusRepository is an autogenerated mock type for the OrderStatusRepository type
@@ -20,17 +23,17 @@ func (_m *MockOrderStatusRepository) EXPECT() *MockOrderStatusRepository_Expecte
 	return &MockOrderStatusRepository_Expecter{mock: &_m.Mock}
 }
 
-// AddOrderStatus provides a mock function with given fields: orderStatus
-func (_m *MockOrderStatusRepository) AddOrderStatus(orderStatus *entities.OrderStatusEntity) error {
-	ret := _m.Called(orderStatus)
+// AddOrderStatus provides a mock function with given fields: ctx, orderStatus
+func (_m *MockOrderStatusRepository) AddOrderStatus(ctx context.Context, orderStatus *entities.OrderStatusEntity) error {
+	ret := _m.Called(ctx, orderStatus)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddOrderStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entities.OrderStatusEntity) error); ok {
-		r0 = rf(orderStatus)
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.OrderStatusEntity) error); ok {
+		r0 = rf(ctx, orderStatus)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -44,14 +47,15 @@ type MockOrderStatusRepository_AddOrderStatus_Call struct {
 }
 
 // AddOrderStatus is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orderStatus *entities.OrderStatusEntity
-func (_e *MockOrderStatusRepository_Expecter) AddOrderStatus(orderStatus interface{}) *MockOrderStatusRepository_AddOrderStatus_Call {
-	return &MockOrderStatusRepository_AddOrderStatus_Call{Call: _e.mock.On("AddOrderStatus", orderStatus)}
+func (_e *MockOrderStatusRepository_Expecter) AddOrderStatus(ctx interface{}, orderStatus interface{}) *MockOrderStatusRepository_AddOrderStatus_Call {
+	return &MockOrderStatusRepository_AddOrderStatus_Call{Call: _e.mock.On("AddOrderStatus", ctx, orderStatus)}
 }
 
-func (_c *MockOrderStatusRepository_AddOrderStatus_Call) Run(run func(orderStatus *entities.OrderStatusEntity)) *MockOrderStatusRepository_AddOrderStatus_Call {
+func (_c *MockOrderStatusRepository_AddOrderStatus_Call) Run(run func(ctx context.Context, orderStatus *entities.OrderStatusEntity)) *MockOrderStatusRepository_AddOrderStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*entities.OrderStatusEntity))
+		run(args[0].(context.Context), args[1].(*entities.OrderStatusEntity))
 	})
 	return _c
 }
@@ -61,14 +65,120 @@ func (_c *MockOrderStatusRepository_AddOrderStatus_Call) Return(_a0 error) *Mock
 	return _c
 }
 
-func (_c *MockOrderStatusRepository_AddOrderStatus_Call) RunAndReturn(run func(*entities.OrderStatusEntity) error) *MockOrderStatusRepository_AddOrderStatus_Call {
+func (_c *MockOrderStatusRepository_AddOrderStatus_Call) RunAndReturn(run func(context.Context, *entities.OrderStatusEntity) error) *MockOrderStatusRepository_AddOrderStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ChangeOrderStatus provides a mock function with given fields: ctx, orderStatus, from
+func (_m *MockOrderStatusRepository) ChangeOrderStatus(ctx context.Context, orderStatus *entities.OrderStatusEntity, from uint) error {
+	ret := _m.Called(ctx, orderStatus, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeOrderStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.OrderStatusEntity, uint) error); ok {
+		r0 = rf(ctx, orderStatus, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOrderStatusRepository_ChangeOrderStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangeOrderStatus'
+type MockOrderStatusRepository_ChangeOrderStatus_Call struct {
+	*mock.Call
+}
+
+// ChangeOrderStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderStatus *entities.OrderStatusEntity
+//   - from uint
+func (_e *MockOrderStatusRepository_Expecter) ChangeOrderStatus(ctx interface{}, orderStatus interface{}, from interface{}) *MockOrderStatusRepository_ChangeOrderStatus_Call {
+	return &MockOrderStatusRepository_ChangeOrderStatus_Call{Call: _e.mock.On("ChangeOrderStatus", ctx, orderStatus, from)}
+}
+
+func (_c *MockOrderStatusRepository_ChangeOrderStatus_Call) Run(run func(ctx context.Context, orderStatus *entities.OrderStatusEntity, from uint)) *MockOrderStatusRepository_ChangeOrderStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entities.OrderStatusEntity), args[2].(uint))
+	})
+	return _c
+}
+
+func (_c *MockOrderStatusRepository_ChangeOrderStatus_Call) Return(_a0 error) *MockOrderStatusRepository_ChangeOrderStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOrderStatusRepository_ChangeOrderStatus_Call) RunAndReturn(run func(context.Context, *entities.OrderStatusEntity, uint) error) *MockOrderStatusRepository_ChangeOrderStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountOrdersByStatus provides a mock function with given fields: ctx
+func (_m *MockOrderStatusRepository) CountOrdersByStatus(ctx context.Context) (map[uint]int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountOrdersByStatus")
+	}
+
+	var r0 map[uint]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[uint]int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[uint]int64); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOrderStatusRepository_CountOrdersByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountOrdersByStatus'
+type MockOrderStatusRepository_CountOrdersByStatus_Call struct {
+	*mock.Call
+}
+
+// CountOrdersByStatus is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockOrderStatusRepository_Expecter) CountOrdersByStatus(ctx interface{}) *MockOrderStatusRepository_CountOrdersByStatus_Call {
+	return &MockOrderStatusRepository_CountOrdersByStatus_Call{Call: _e.mock.On("CountOrdersByStatus", ctx)}
+}
+
+func (_c *MockOrderStatusRepository_CountOrdersByStatus_Call) Run(run func(ctx context.Context)) *MockOrderStatusRepository_CountOrdersByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockOrderStatusRepository_CountOrdersByStatus_Call) Return(_a0 map[uint]int64, _a1 error) *MockOrderStatusRepository_CountOrdersByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOrderStatusRepository_CountOrdersByStatus_Call) RunAndReturn(run func(context.Context) (map[uint]int64, error)) *MockOrderStatusRepository_CountOrdersByStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOrderStatus provides a mock function with given fields: orderId
-func (_m *MockOrderStatusRepository) GetOrderStatus(orderId uint) (*entities.OrderStatusEntity, error) {
-	ret := _m.Called(orderId)
+// GetOrderStatus provides a mock function with given fields: ctx, orderId
+func (_m *MockOrderStatusRepository) GetOrderStatus(ctx context.Context, orderId uint) (*entities.OrderStatusEntity, error) {
+	ret := _m.Called(ctx, orderId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrderStatus")
@@ -76,19 +186,19 @@ func (_m *MockOrderStatusRepository) GetOrderStatus(orderId uint) (*entities.Ord
 
 	var r0 *entities.OrderStatusEntity
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (*entities.OrderStatusEntity, error)); ok {
-		return rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.OrderStatusEntity, error)); ok {
+		return rf(ctx, orderId)
 	}
-	if rf, ok := ret.Get(0).(func(uint) *entities.OrderStatusEntity); ok {
-		r0 = rf(orderId)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.OrderStatusEntity); ok {
+		r0 = rf(ctx, orderId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entities.OrderStatusEntity)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(orderId)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, orderId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -102,14 +212,15 @@ type MockOrderStatusRepository_GetOrderStatus_Call struct {
 }
 
 // GetOrderStatus is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orderId uint
-func (_e *MockOrderStatusRepository_Expecter) GetOrderStatus(orderId interface{}) *MockOrderStatusRepository_GetOrderStatus_Call {
-	return &MockOrderStatusRepository_GetOrderStatus_Call{Call: _e.mock.On("GetOrderStatus", orderId)}
+func (_e *MockOrderStatusRepository_Expecter) GetOrderStatus(ctx interface{}, orderId interface{}) *MockOrderStatusRepository_GetOrderStatus_Call {
+	return &MockOrderStatusRepository_GetOrderStatus_Call{Call: _e.mock.On("GetOrderStatus", ctx, orderId)}
 }
 
-func (_c *MockOrderStatusRepository_GetOrderStatus_Call) Run(run func(orderId uint)) *MockOrderStatusRepository_GetOrderStatus_Call {
+func (_c *MockOrderStatusRepository_GetOrderStatus_Call) Run(run func(ctx context.Context, orderId uint)) *MockOrderStatusRepository_GetOrderStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint))
+		run(args[0].(context.Context), args[1].(uint))
 	})
 	return _c
 }
@@ -119,7 +230,7 @@ func (_c *MockOrderStatusRepository_GetOrderStatus_Call) Return(_a0 *entities.Or
 	return _c
 }
 
-func (_c *MockOrderStatusRepository_GetOrderStatus_Call) RunAndReturn(run func(uint) (*entities.OrderStatusEntity, error)) *MockOrderStatusRepository_GetOrderStatus_Call {
+func (_c *MockOrderStatusRepository_GetOrderStatus_Call) RunAndReturn(run func(context.Context, uint) (*entities.OrderStatusEntity, error)) *MockOrderStatusRepository_GetOrderStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
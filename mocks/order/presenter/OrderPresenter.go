@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	entities "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	dto "github.com/viniciuscluna/tc-fiap-50/internal/order/infrastructure/api/dto"
 
@@ -22,17 +24,17 @@ func (_m *MockOrderPresenter) EXPECT() *MockOrderPresenter_Expecter {
 	return &MockOrderPresenter_Expecter{mock: &_m.Mock}
 }
 
-// Present provides a mock function with given fields: order
-func (_m *MockOrderPresenter) Present(order *entities.OrderEntity) *dto.GetOrderResponseDto {
-	ret := _m.Called(order)
+// Present provides a mock function with given fields: ctx, order
+func (_m *MockOrderPresenter) Present(ctx context.Context, order *entities.OrderEntity) *dto.GetOrderResponseDto {
+	ret := _m.Called(ctx, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Present")
 	}
 
 	var r0 *dto.GetOrderResponseDto
-	if rf, ok := ret.Get(0).(func(*entities.OrderEntity) *dto.GetOrderResponseDto); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.OrderEntity) *dto.GetOrderResponseDto); ok {
+		r0 = rf(ctx, order)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*dto.GetOrderResponseDto)
@@ -48,14 +50,15 @@ type MockOrderPresenter_Present_Call struct {
 }
 
 // Present is a helper method to define mock.On call
+//   - ctx context.Context
 //   - order *entities.OrderEntity
-func (_e *MockOrderPresenter_Expecter) Present(order interface{}) *MockOrderPresenter_Present_Call {
-	return &MockOrderPresenter_Present_Call{Call: _e.mock.On("Present", order)}
+func (_e *MockOrderPresenter_Expecter) Present(ctx interface{}, order interface{}) *MockOrderPresenter_Present_Call {
+	return &MockOrderPresenter_Present_Call{Call: _e.mock.On("Present", ctx, order)}
 }
 
-func (_c *MockOrderPresenter_Present_Call) Run(run func(order *entities.OrderEntity)) *MockOrderPresenter_Present_Call {
+func (_c *MockOrderPresenter_Present_Call) Run(run func(ctx context.Context, order *entities.OrderEntity)) *MockOrderPresenter_Present_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*entities.OrderEntity))
+		run(args[0].(context.Context), args[1].(*entities.OrderEntity))
 	})
 	return _c
 }
@@ -65,7 +68,7 @@ func (_c *MockOrderPresenter_Present_Call) Return(_a0 *dto.GetOrderResponseDto)
 	return _c
 }
 
-func (_c *MockOrderPresenter_Present_Call) RunAndReturn(run func(*entities.OrderEntity) *dto.GetOrderResponseDto) *MockOrderPresenter_Present_Call {
+func (_c *MockOrderPresenter_Present_Call) RunAndReturn(run func(context.Context, *entities.OrderEntity) *dto.GetOrderResponseDto) *MockOrderPresenter_Present_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -118,17 +121,17 @@ func (_c *MockOrderPresenter_PresentMultipleStatus_Call) RunAndReturn(run func([
 	return _c
 }
 
-// PresentOrders provides a mock function with given fields: orders
-func (_m *MockOrderPresenter) PresentOrders(orders []*entities.OrderEntity) *dto.GetOrdersResponseDto {
-	ret := _m.Called(orders)
+// PresentOrders provides a mock function with given fields: ctx, orders
+func (_m *MockOrderPresenter) PresentOrders(ctx context.Context, orders []*entities.OrderEntity) *dto.GetOrdersResponseDto {
+	ret := _m.Called(ctx, orders)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PresentOrders")
 	}
 
 	var r0 *dto.GetOrdersResponseDto
-	if rf, ok := ret.Get(0).(func([]*entities.OrderEntity) *dto.GetOrdersResponseDto); ok {
-		r0 = rf(orders)
+	if rf, ok := ret.Get(0).(func(context.Context, []*entities.OrderEntity) *dto.GetOrdersResponseDto); ok {
+		r0 = rf(ctx, orders)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*dto.GetOrdersResponseDto)
@@ -144,14 +147,15 @@ type MockOrderPresenter_PresentOrders_Call struct {
 }
 
 // PresentOrders is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orders []*entities.OrderEntity
-func (_e *MockOrderPresenter_Expecter) PresentOrders(orders interface{}) *MockOrderPresenter_PresentOrders_Call {
-	return &MockOrderPresenter_PresentOrders_Call{Call: _e.mock.On("PresentOrders", orders)}
+func (_e *MockOrderPresenter_Expecter) PresentOrders(ctx interface{}, orders interface{}) *MockOrderPresenter_PresentOrders_Call {
+	return &MockOrderPresenter_PresentOrders_Call{Call: _e.mock.On("PresentOrders", ctx, orders)}
 }
 
-func (_c *MockOrderPresenter_PresentOrders_Call) Run(run func(orders []*entities.OrderEntity)) *MockOrderPresenter_PresentOrders_Call {
+func (_c *MockOrderPresenter_PresentOrders_Call) Run(run func(ctx context.Context, orders []*entities.OrderEntity)) *MockOrderPresenter_PresentOrders_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].([]*entities.OrderEntity))
+		run(args[0].(context.Context), args[1].([]*entities.OrderEntity))
 	})
 	return _c
 }
@@ -161,22 +165,22 @@ func (_c *MockOrderPresenter_PresentOrders_Call) Return(_a0 *dto.GetOrdersRespon
 	return _c
 }
 
-func (_c *MockOrderPresenter_PresentOrders_Call) RunAndReturn(run func([]*entities.OrderEntity) *dto.GetOrdersResponseDto) *MockOrderPresenter_PresentOrders_Call {
+func (_c *MockOrderPresenter_PresentOrders_Call) RunAndReturn(run func(context.Context, []*entities.OrderEntity) *dto.GetOrdersResponseDto) *MockOrderPresenter_PresentOrders_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PresentProducts provides a mock function with given fields: orderProducts
-func (_m *MockOrderPresenter) PresentProducts(orderProducts []*entities.OrderProductEntity) []*dto.OrderProductDto {
-	ret := _m.Called(orderProducts)
+// PresentProducts provides a mock function with given fields: ctx, orderProducts
+func (_m *MockOrderPresenter) PresentProducts(ctx context.Context, orderProducts []*entities.OrderProductEntity) []*dto.OrderProductDto {
+	ret := _m.Called(ctx, orderProducts)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PresentProducts")
 	}
 
 	var r0 []*dto.OrderProductDto
-	if rf, ok := ret.Get(0).(func([]*entities.OrderProductEntity) []*dto.OrderProductDto); ok {
-		r0 = rf(orderProducts)
+	if rf, ok := ret.Get(0).(func(context.Context, []*entities.OrderProductEntity) []*dto.OrderProductDto); ok {
+		r0 = rf(ctx, orderProducts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*dto.OrderProductDto)
@@ -192,14 +196,15 @@ type MockOrderPresenter_PresentProducts_Call struct {
 }
 
 // PresentProducts is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orderProducts []*entities.OrderProductEntity
-func (_e *MockOrderPresenter_Expecter) PresentProducts(orderProducts interface{}) *MockOrderPresenter_PresentProducts_Call {
-	return &MockOrderPresenter_PresentProducts_Call{Call: _e.mock.On("PresentProducts", orderProducts)}
+func (_e *MockOrderPresenter_Expecter) PresentProducts(ctx interface{}, orderProducts interface{}) *MockOrderPresenter_PresentProducts_Call {
+	return &MockOrderPresenter_PresentProducts_Call{Call: _e.mock.On("PresentProducts", ctx, orderProducts)}
 }
 
-func (_c *MockOrderPresenter_PresentProducts_Call) Run(run func(orderProducts []*entities.OrderProductEntity)) *MockOrderPresenter_PresentProducts_Call {
+func (_c *MockOrderPresenter_PresentProducts_Call) Run(run func(ctx context.Context, orderProducts []*entities.OrderProductEntity)) *MockOrderPresenter_PresentProducts_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].([]*entities.OrderProductEntity))
+		run(args[0].(context.Context), args[1].([]*entities.OrderProductEntity))
 	})
 	return _c
 }
@@ -209,7 +214,7 @@ func (_c *MockOrderPresenter_PresentProducts_Call) Return(_a0 []*dto.OrderProduc
 	return _c
 }
 
-func (_c *MockOrderPresenter_PresentProducts_Call) RunAndReturn(run func([]*entities.OrderProductEntity) []*dto.OrderProductDto) *MockOrderPresenter_PresentProducts_Call {
+func (_c *MockOrderPresenter_PresentProducts_Call) RunAndReturn(run func(context.Context, []*entities.OrderProductEntity) []*dto.OrderProductDto) *MockOrderPresenter_PresentProducts_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -3,8 +3,11 @@
 package mocks
 
 import (
-	mock "github.com/stretchr/testify/mock"
+	context "context"
+
 	commands "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // MockAddOrderUseCase is an autogenerated mock type for the AddOrderUseCase type
@@ -20,9 +23,9 @@ func (_m *MockAddOrderUseCase) EXPECT() *MockAddOrderUseCase_Expecter {
 	return &MockAddOrderUseCase_Expecter{mock: &_m.Mock}
 }
 
-// Execute provides a mock function with given fields: command
-func (_m *MockAddOrderUseCase) Execute(command *commands.AddOrderCommand) (string, error) {
-	ret := _m.Called(command)
+// Execute provides a mock function with given fields: ctx, command
+func (_m *MockAddOrderUseCase) Execute(ctx context.Context, command *commands.AddOrderCommand) (string, error) {
+	ret := _m.Called(ctx, command)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Execute")
@@ -30,17 +33,17 @@ func (_m *MockAddOrderUseCase) Execute(command *commands.AddOrderCommand) (strin
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*commands.AddOrderCommand) (string, error)); ok {
-		return rf(command)
+	if rf, ok := ret.Get(0).(func(context.Context, *commands.AddOrderCommand) (string, error)); ok {
+		return rf(ctx, command)
 	}
-	if rf, ok := ret.Get(0).(func(*commands.AddOrderCommand) string); ok {
-		r0 = rf(command)
+	if rf, ok := ret.Get(0).(func(context.Context, *commands.AddOrderCommand) string); ok {
+		r0 = rf(ctx, command)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*commands.AddOrderCommand) error); ok {
-		r1 = rf(command)
+	if rf, ok := ret.Get(1).(func(context.Context, *commands.AddOrderCommand) error); ok {
+		r1 = rf(ctx, command)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -54,14 +57,15 @@ type MockAddOrderUseCase_Execute_Call struct {
 }
 
 // Execute is a helper method to define mock.On call
+//   - ctx context.Context
 //   - command *commands.AddOrderCommand
-func (_e *MockAddOrderUseCase_Expecter) Execute(command interface{}) *MockAddOrderUseCase_Execute_Call {
-	return &MockAddOrderUseCase_Execute_Call{Call: _e.mock.On("Execute", command)}
+func (_e *MockAddOrderUseCase_Expecter) Execute(ctx interface{}, command interface{}) *MockAddOrderUseCase_Execute_Call {
+	return &MockAddOrderUseCase_Execute_Call{Call: _e.mock.On("Execute", ctx, command)}
 }
 
-func (_c *MockAddOrderUseCase_Execute_Call) Run(run func(command *commands.AddOrderCommand)) *MockAddOrderUseCase_Execute_Call {
+func (_c *MockAddOrderUseCase_Execute_Call) Run(run func(ctx context.Context, command *commands.AddOrderCommand)) *MockAddOrderUseCase_Execute_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*commands.AddOrderCommand))
+		run(args[0].(context.Context), args[1].(*commands.AddOrderCommand))
 	})
 	return _c
 }
@@ -71,7 +75,7 @@ func (_c *MockAddOrderUseCase_Execute_Call) Return(_a0 string, _a1 error) *MockA
 	return _c
 }
 
-func (_c *MockAddOrderUseCase_Execute_Call) RunAndReturn(run func(*commands.AddOrderCommand) (string, error)) *MockAddOrderUseCase_Execute_Call {
+func (_c *MockAddOrderUseCase_Execute_Call) RunAndReturn(run func(context.Context, *commands.AddOrderCommand) (string, error)) *MockAddOrderUseCase_Execute_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	entities "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
 	commands "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
 
@@ -22,9 +24,9 @@ func (_m *MockGetOrderStatusUseCase) EXPECT() *MockGetOrderStatusUseCase_Expecte
 	return &MockGetOrderStatusUseCase_Expecter{mock: &_m.Mock}
 }
 
-// Execute provides a mock function with given fields: command
-func (_m *MockGetOrderStatusUseCase) Execute(command *commands.GetOrderStatusCommand) (*entities.OrderStatusEntity, error) {
-	ret := _m.Called(command)
+// Execute provides a mock function with given fields: ctx, command
+func (_m *MockGetOrderStatusUseCase) Execute(ctx context.Context, command *commands.GetOrderStatusCommand) (*entities.OrderStatusEntity, error) {
+	ret := _m.Called(ctx, command)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Execute")
@@ -32,19 +34,19 @@ func (_m *MockGetOrderStatusUseCase) Execute(command *commands.GetOrderStatusCom
 
 	var r0 *entities.OrderStatusEntity
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*commands.GetOrderStatusCommand) (*entities.OrderStatusEntity, error)); ok {
-		return rf(command)
+	if rf, ok := ret.Get(0).(func(context.Context, *commands.GetOrderStatusCommand) (*entities.OrderStatusEntity, error)); ok {
+		return rf(ctx, command)
 	}
-	if rf, ok := ret.Get(0).(func(*commands.GetOrderStatusCommand) *entities.OrderStatusEntity); ok {
-		r0 = rf(command)
+	if rf, ok := ret.Get(0).(func(context.Context, *commands.GetOrderStatusCommand) *entities.OrderStatusEntity); ok {
+		r0 = rf(ctx, command)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entities.OrderStatusEntity)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*commands.GetOrderStatusCommand) error); ok {
-		r1 = rf(command)
+	if rf, ok := ret.Get(1).(func(context.Context, *commands.GetOrderStatusCommand) error); ok {
+		r1 = rf(ctx, command)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -58,14 +60,15 @@ type MockGetOrderStatusUseCase_Execute_Call struct {
 }
 
 // Execute is a helper method to define mock.On call
+//   - ctx context.Context
 //   - command *commands.GetOrderStatusCommand
-func (_e *MockGetOrderStatusUseCase_Expecter) Execute(command interface{}) *MockGetOrderStatusUseCase_Execute_Call {
-	return &MockGetOrderStatusUseCase_Execute_Call{Call: _e.mock.On("Execute", command)}
+func (_e *MockGetOrderStatusUseCase_Expecter) Execute(ctx interface{}, command interface{}) *MockGetOrderStatusUseCase_Execute_Call {
+	return &MockGetOrderStatusUseCase_Execute_Call{Call: _e.mock.On("Execute", ctx, command)}
 }
 
-func (_c *MockGetOrderStatusUseCase_Execute_Call) Run(run func(command *commands.GetOrderStatusCommand)) *MockGetOrderStatusUseCase_Execute_Call {
+func (_c *MockGetOrderStatusUseCase_Execute_Call) Run(run func(ctx context.Context, command *commands.GetOrderStatusCommand)) *MockGetOrderStatusUseCase_Execute_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*commands.GetOrderStatusCommand))
+		run(args[0].(context.Context), args[1].(*commands.GetOrderStatusCommand))
 	})
 	return _c
 }
@@ -75,7 +78,7 @@ func (_c *MockGetOrderStatusUseCase_Execute_Call) Return(_a0 *entities.OrderStat
 	return _c
 }
 
-func (_c *MockGetOrderStatusUseCase_Execute_Call) RunAndReturn(run func(*commands.GetOrderStatusCommand) (*entities.OrderStatusEntity, error)) *MockGetOrderStatusUseCase_Execute_Call {
+func (_c *MockGetOrderStatusUseCase_Execute_Call) RunAndReturn(run func(context.Context, *commands.GetOrderStatusCommand) (*entities.OrderStatusEntity, error)) *MockGetOrderStatusUseCase_Execute_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -3,8 +3,11 @@
 package mocks
 
 import (
-	mock "github.com/stretchr/testify/mock"
+	context "context"
+
 	commands "github.com/viniciuscluna/tc-fiap-50/internal/order/usecase/commands"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // MockUpdateOrderStatusUseCase is an autogenerated mock type for the UpdateOrderStatusUseCase type
@@ -20,17 +23,17 @@ func (_m *MockUpdateOrderStatusUseCase) EXPECT() *MockUpdateOrderStatusUseCase_E
 	return &MockUpdateOrderStatusUseCase_Expecter{mock: &_m.Mock}
 }
 
-// Execute provides a mock function with given fields: command
-func (_m *MockUpdateOrderStatusUseCase) Execute(command *commands.UpdateOrderStatusCommand) error {
-	ret := _m.Called(command)
+// Execute provides a mock function with given fields: ctx, command
+func (_m *MockUpdateOrderStatusUseCase) Execute(ctx context.Context, command *commands.UpdateOrderStatusCommand) error {
+	ret := _m.Called(ctx, command)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Execute")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*commands.UpdateOrderStatusCommand) error); ok {
-		r0 = rf(command)
+	if rf, ok := ret.Get(0).(func(context.Context, *commands.UpdateOrderStatusCommand) error); ok {
+		r0 = rf(ctx, command)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -44,14 +47,15 @@ type MockUpdateOrderStatusUseCase_Execute_Call struct {
 }
 
 // Execute is a helper method to define mock.On call
+//   - ctx context.Context
 //   - command *commands.UpdateOrderStatusCommand
-func (_e *MockUpdateOrderStatusUseCase_Expecter) Execute(command interface{}) *MockUpdateOrderStatusUseCase_Execute_Call {
-	return &MockUpdateOrderStatusUseCase_Execute_Call{Call: _e.mock.On("Execute", command)}
+func (_e *MockUpdateOrderStatusUseCase_Expecter) Execute(ctx interface{}, command interface{}) *MockUpdateOrderStatusUseCase_Execute_Call {
+	return &MockUpdateOrderStatusUseCase_Execute_Call{Call: _e.mock.On("Execute", ctx, command)}
 }
 
-func (_c *MockUpdateOrderStatusUseCase_Execute_Call) Run(run func(command *commands.UpdateOrderStatusCommand)) *MockUpdateOrderStatusUseCase_Execute_Call {
+func (_c *MockUpdateOrderStatusUseCase_Execute_Call) Run(run func(ctx context.Context, command *commands.UpdateOrderStatusCommand)) *MockUpdateOrderStatusUseCase_Execute_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*commands.UpdateOrderStatusCommand))
+		run(args[0].(context.Context), args[1].(*commands.UpdateOrderStatusCommand))
 	})
 	return _c
 }
@@ -61,7 +65,7 @@ func (_c *MockUpdateOrderStatusUseCase_Execute_Call) Return(_a0 error) *MockUpda
 	return _c
 }
 
-func (_c *MockUpdateOrderStatusUseCase_Execute_Call) RunAndReturn(run func(*commands.UpdateOrderStatusCommand) error) *MockUpdateOrderStatusUseCase_Execute_Call {
+func (_c *MockUpdateOrderStatusUseCase_Execute_Call) RunAndReturn(run func(context.Context, *commands.UpdateOrderStatusCommand) error) *MockUpdateOrderStatusUseCase_Execute_Call {
 	_c.Call.Return(run)
 	return _c
 }
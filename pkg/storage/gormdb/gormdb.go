@@ -0,0 +1,24 @@
+// Package gormdb opens the GORM connections of the SQL storage drivers, with
+// the query logging, metrics and tracing shared by all of them.
+package gormdb
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+// Open connects to the database of dialector, logging its queries with the
+// logger of the request running them.
+func Open(dialector gorm.Dialector) (*gorm.DB, error) {
+	return gorm.Open(dialector, &gorm.Config{Logger: newQueryLogger()})
+}
+
+// Instrument observes every statement of db with metrics and traces.
+func Instrument(db *gorm.DB, registerer prometheus.Registerer) error {
+	for _, plugin := range []gorm.Plugin{newQueryMetrics(registerer), newQueryTracing()} {
+		if err := db.Use(plugin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
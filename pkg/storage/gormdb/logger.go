@@ -0,0 +1,80 @@
+package gormdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/logging"
+)
+
+// slowQueryThreshold is how long a query may take before it is logged as slow.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// queryLogger sends GORM logs to the logger of the request running the query,
+// so they carry its request ID. Failed and slow queries are logged as
+// warnings, every query at debug level.
+type queryLogger struct {
+	level gormLogger.LogLevel
+}
+
+func newQueryLogger() gormLogger.Interface {
+	return &queryLogger{level: gormLogger.Warn}
+}
+
+func (l *queryLogger) LogMode(level gormLogger.LogLevel) gormLogger.Interface {
+	return &queryLogger{level: level}
+}
+
+func (l *queryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormLogger.Info {
+		logging.FromContext(ctx).Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *queryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormLogger.Warn {
+		logging.FromContext(ctx).Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *queryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormLogger.Error {
+		logging.FromContext(ctx).Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormLogger.Silent {
+		return
+	}
+
+	logger := logging.FromContext(ctx)
+	elapsed := time.Since(begin)
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	slow := elapsed > slowQueryThreshold
+	if !failed && !slow && !logger.Core().Enabled(zap.DebugLevel) {
+		return
+	}
+
+	sql, rows := fc()
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("duration", elapsed),
+	}
+
+	switch {
+	case failed && l.level >= gormLogger.Error:
+		logger.Warn("query failed", append(fields, zap.Error(err))...)
+	case slow && l.level >= gormLogger.Warn:
+		logger.Warn("slow query", fields...)
+	default:
+		logger.Debug("query", fields...)
+	}
+}
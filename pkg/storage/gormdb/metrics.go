@@ -0,0 +1,75 @@
+package gormdb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// queryMetrics is a GORM plugin observing the latency and outcome of every
+// statement, by operation and table.
+type queryMetrics struct {
+	duration *prometheus.HistogramVec
+}
+
+func newQueryMetrics(registerer prometheus.Registerer) *queryMetrics {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Latency of the database statements, by operation, table and outcome.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "outcome"})
+	registerer.MustRegister(duration)
+
+	return &queryMetrics{duration: duration}
+}
+
+func (m *queryMetrics) Name() string {
+	return "query_metrics"
+}
+
+func (m *queryMetrics) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("metrics:before_create", m.start),
+		callbacks.Create().After("gorm:create").Register("metrics:after_create", m.observe("create")),
+		callbacks.Query().Before("gorm:query").Register("metrics:before_query", m.start),
+		callbacks.Query().After("gorm:query").Register("metrics:after_query", m.observe("query")),
+		callbacks.Update().Before("gorm:update").Register("metrics:before_update", m.start),
+		callbacks.Update().After("gorm:update").Register("metrics:after_update", m.observe("update")),
+		callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", m.start),
+		callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", m.observe("delete")),
+		callbacks.Row().Before("gorm:row").Register("metrics:before_row", m.start),
+		callbacks.Row().After("gorm:row").Register("metrics:after_row", m.observe("row")),
+		callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", m.start),
+		callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", m.observe("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *queryMetrics) start(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (m *queryMetrics) observe(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+
+		outcome := "success"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			outcome = "error"
+		}
+
+		m.duration.WithLabelValues(operation, db.Statement.Table, outcome).Observe(time.Since(value.(time.Time)).Seconds())
+	}
+}
@@ -0,0 +1,55 @@
+package gormdb
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	orderEntities "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+)
+
+// observedQueries returns how many statements were observed by operation, table and outcome.
+func observedQueries(t *testing.T, registry *prometheus.Registry) map[string]uint64 {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	observed := make(map[string]uint64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			observed[labels["operation"]+" "+labels["table"]+" "+labels["outcome"]] += metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return observed
+}
+
+// Feature: Database metrics
+// Scenario: Observe statements by operation and table
+func Test_QueryMetrics_ShouldObserveStatements(t *testing.T) {
+	// GIVEN an instrumented database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&orderEntities.OrderEntity{}))
+	registry := prometheus.NewRegistry()
+	require.NoError(t, db.Use(newQueryMetrics(registry)))
+
+	// WHEN an order is created, found, missed and queried with broken SQL
+	require.NoError(t, db.Create(&orderEntities.OrderEntity{}).Error)
+	require.NoError(t, db.First(&orderEntities.OrderEntity{}, 1).Error)
+	assert.ErrorIs(t, db.First(&orderEntities.OrderEntity{}, 2).Error, gorm.ErrRecordNotFound)
+	assert.Error(t, db.Where("missing_column = ?", 1).Find(&[]orderEntities.OrderEntity{}).Error)
+
+	// THEN each statement should be observed, not found being a success
+	assert.Equal(t, map[string]uint64{
+		"create order success": 1,
+		"query order success":  2,
+		"query order error":    1,
+	}, observedQueries(t, registry))
+}
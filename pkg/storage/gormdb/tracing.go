@@ -0,0 +1,93 @@
+package gormdb
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+)
+
+const querySpanKey = "tracing:query_span"
+
+// queryTracing is a GORM plugin running every statement in a client span,
+// child of the span in the statement context.
+type queryTracing struct{}
+
+func newQueryTracing() *queryTracing {
+	return &queryTracing{}
+}
+
+func (t *queryTracing) Name() string {
+	return "query_tracing"
+}
+
+func (t *queryTracing) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", t.start("create")),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", t.end("create")),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", t.start("query")),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", t.end("query")),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", t.start("update")),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", t.end("update")),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", t.start("delete")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", t.end("delete")),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", t.start("row")),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", t.end("row")),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", t.start("raw")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", t.end("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *queryTracing) start(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := tracing.Start(db.Statement.Context, operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(dbSystem(db.Dialector.Name()), semconv.DBOperationName(operation)),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(querySpanKey, span)
+	}
+}
+
+func (t *queryTracing) end(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(querySpanKey)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		defer span.End()
+
+		// The table is only known once the statement is built
+		if db.Statement.Table != "" {
+			span.SetName(operation + " " + db.Statement.Table)
+			span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+		}
+		// The SQL has placeholders instead of the values, which may be personal data
+		span.SetAttributes(semconv.DBQueryText(db.Statement.SQL.String()))
+
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			span.RecordError(db.Error)
+			span.SetStatus(codes.Error, db.Error.Error())
+		}
+	}
+}
+
+// dbSystem returns the db.system attribute of the GORM dialector in use.
+func dbSystem(dialector string) attribute.KeyValue {
+	if dialector == "postgres" {
+		return semconv.DBSystemPostgreSQL
+	}
+	return semconv.DBSystemKey.String(dialector)
+}
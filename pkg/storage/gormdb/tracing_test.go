@@ -0,0 +1,52 @@
+package gormdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	orderEntities "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/tracing/tracingtest"
+)
+
+// Feature: Database tracing
+// Scenario: Trace statements as children of the request
+func Test_QueryTracing_ShouldTraceStatements(t *testing.T) {
+	// GIVEN an instrumented database and a traced request
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&orderEntities.OrderEntity{}))
+	require.NoError(t, db.Use(newQueryTracing()))
+	recorder := tracingtest.NewRecorder(t)
+	ctx, parent := tracing.Start(context.Background(), "GetOrderUseCase.Execute")
+
+	// WHEN an order is created, found and queried with broken SQL
+	require.NoError(t, db.WithContext(ctx).Create(&orderEntities.OrderEntity{}).Error)
+	require.NoError(t, db.WithContext(ctx).First(&orderEntities.OrderEntity{}, 1).Error)
+	assert.Error(t, db.WithContext(ctx).Where("missing_column = ?", 1).Find(&[]orderEntities.OrderEntity{}).Error)
+	parent.End()
+
+	// THEN each statement should be a client span of the request
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	for _, span := range spans[:3] {
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Contains(t, span.Attributes(), semconv.DBCollectionName("order"))
+	}
+	assert.Equal(t, "create order", spans[0].Name())
+	assert.Equal(t, "query order", spans[1].Name())
+	// AND the SQL should be recorded without the values
+	assert.Contains(t, spans[1].Attributes(), semconv.DBQueryText("SELECT * FROM `order` WHERE `order`.`id` = ? ORDER BY `order`.`id` LIMIT 1"))
+	// AND only the broken statement should fail
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
@@ -0,0 +1,324 @@
+// Package migrate applies versioned SQL migrations to a database, recording
+// the applied versions in the schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const schemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// ErrSchemaOutdated is returned by Check when the database doesn't have the
+// schema the service expects.
+var ErrSchemaOutdated = errors.New("database schema is not up to date")
+
+// Migration is a change of the schema, with the SQL applying and reverting it.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status tells whether a migration has been applied, and when.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Locker keeps other processes from migrating the same database at the same
+// time. The lock is held by the connection the migrations run on.
+type Locker interface {
+	Lock(ctx context.Context, conn *sql.Conn) error
+	Unlock(ctx context.Context, conn *sql.Conn) error
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load reads the migrations of fsys, named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, e.g. 0001_create_orders.up.sql. Every migration
+// must have both files.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%s: expected <version>_<name>.up.sql or <version>_<name>.down.sql", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 0)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("%s: invalid version %q", entry.Name(), match[1])
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("%s: version %d is already used by %q", entry.Name(), version, migration.Name)
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s: both the up and the down files are required", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return int(a.Version) - int(b.Version) })
+	return migrations, nil
+}
+
+// Migrator moves the schema of a database between the versions of its
+// migrations. Each migration runs in a transaction together with its record in
+// schema_migrations, so a failing migration leaves the schema untouched.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	locker     Locker
+	logger     *zap.Logger
+}
+
+// New creates a migrator applying migrations, sorted by version, to db. A nil
+// locker doesn't guard against concurrent migrations.
+func New(db *sql.DB, migrations []Migration, locker Locker, logger *zap.Logger) *Migrator {
+	return &Migrator{db: db, migrations: migrations, locker: locker, logger: logger}
+}
+
+// Latest returns the version of the last migration, 0 when there is none.
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.Goto(ctx, m.Latest())
+}
+
+// Down reverts the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; !ok {
+				continue
+			}
+			if err := m.revert(ctx, conn, m.migrations[i]); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// Goto applies or reverts migrations until version is the last one applied.
+// Version 0 reverts every migration.
+func (m *Migrator) Goto(ctx context.Context, version uint) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == version }) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; ok && migration.Version > version {
+				if err := m.revert(ctx, conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+				if err := m.apply(ctx, conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Status returns every migration with whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := m.createTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: appliedAt})
+	}
+	return statuses, nil
+}
+
+// Check returns ErrSchemaOutdated unless exactly the known migrations have
+// been applied. Unlike the other methods it never writes to the database, not
+// even to create schema_migrations, so it fails on a database never migrated.
+func (m *Migrator) Check(ctx context.Context) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	var pending []uint
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration.Version)
+		}
+		delete(applied, migration.Version)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: pending migrations %v", ErrSchemaOutdated, pending)
+	}
+	if len(applied) > 0 {
+		unknown := slices.Sorted(maps.Keys(applied))
+		return fmt.Errorf("%w: unknown migrations %v applied, by a newer release?", ErrSchemaOutdated, unknown)
+	}
+	return nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, migrate func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.locker != nil {
+		if err := m.locker.Lock(ctx, conn); err != nil {
+			return fmt.Errorf("failed to acquire the migration lock: %w", err)
+		}
+		defer func() {
+			// The lock must be released even if ctx is done, or the
+			// connection would go back to the pool still holding it
+			if err := m.locker.Unlock(context.WithoutCancel(ctx), conn); err != nil {
+				m.logger.Error("Failed to release the migration lock", zap.Error(err))
+			}
+		}()
+	}
+
+	if err := m.createTable(ctx, conn); err != nil {
+		return err
+	}
+	return migrate(conn)
+}
+
+func (m *Migrator) createTable(ctx context.Context, conn *sql.Conn) error {
+	if _, err := conn.ExecContext(ctx, schemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// applied returns when each applied migration was applied.
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[uint]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[uint]time.Time{}
+	for rows.Next() {
+		var version uint
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	m.logger.Info("Applying migration", zap.Uint("version", migration.Version), zap.String("name", migration.Name))
+	return m.run(ctx, conn, migration, migration.Up,
+		`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	m.logger.Info("Reverting migration", zap.Uint("version", migration.Version), zap.String("name", migration.Name))
+	return m.run(ctx, conn, migration, migration.Down,
+		`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+}
+
+// run executes statements and records the change in a single transaction.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, migration Migration, statements string, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return tx.Commit()
+}
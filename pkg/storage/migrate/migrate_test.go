@@ -0,0 +1,239 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var testFiles = fstest.MapFS{
+	"0001_create_orders.up.sql":     {Data: []byte(`CREATE TABLE orders (id INTEGER PRIMARY KEY);`)},
+	"0001_create_orders.down.sql":   {Data: []byte(`DROP TABLE orders;`)},
+	"0002_add_order_note.up.sql":    {Data: []byte(`ALTER TABLE orders ADD COLUMN note TEXT;`)},
+	"0002_add_order_note.down.sql":  {Data: []byte(`ALTER TABLE orders DROP COLUMN note;`)},
+	"0003_create_products.up.sql":   {Data: []byte(`CREATE TABLE products (id INTEGER PRIMARY KEY); CREATE INDEX idx_products_id ON products (id);`)},
+	"0003_create_products.down.sql": {Data: []byte(`DROP TABLE products;`)},
+}
+
+// countingLock records how many times the migration lock was taken and released.
+type countingLock struct {
+	locked, unlocked int
+}
+
+func (l *countingLock) Lock(ctx context.Context, conn *sql.Conn) error {
+	l.locked++
+	return nil
+}
+
+func (l *countingLock) Unlock(ctx context.Context, conn *sql.Conn) error {
+	l.unlocked++
+	return nil
+}
+
+// newTestDB opens a sqlite database in a file, shared by every connection of
+// the pool unlike :memory:.
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return sqlDB
+}
+
+func newTestMigrator(t *testing.T, db *sql.DB, files fstest.MapFS, locker Locker) *Migrator {
+	migrations, err := Load(files)
+	require.NoError(t, err)
+	return New(db, migrations, locker, zap.NewNop())
+}
+
+func appliedVersions(t *testing.T, migrator *Migrator) []uint {
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+
+	var versions []uint
+	for _, status := range statuses {
+		if status.Applied {
+			versions = append(versions, status.Version)
+		}
+	}
+	return versions
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = $1`, table).Scan(&count))
+	return count > 0
+}
+
+// Feature: Loading migrations
+func Test_Load_ShouldSortMigrationsByVersion(t *testing.T) {
+	// GIVEN migration files listed in any order
+	// WHEN they are loaded
+	migrations, err := Load(testFiles)
+
+	// THEN they should be paired and sorted by version
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	assert.Equal(t, uint(1), migrations[0].Version)
+	assert.Equal(t, "create_orders", migrations[0].Name)
+	assert.Equal(t, `DROP TABLE orders;`, migrations[0].Down)
+	assert.Equal(t, uint(3), migrations[2].Version)
+}
+
+func Test_Load_WithInvalidFiles_ShouldFail(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files fstest.MapFS
+		err   string
+	}{
+		{"missing down", fstest.MapFS{
+			"0001_create_orders.up.sql": {Data: []byte(`CREATE TABLE orders (id INTEGER);`)},
+		}, "migration 1_create_orders: both the up and the down files are required"},
+		{"duplicate version", fstest.MapFS{
+			"0001_create_orders.up.sql":   {Data: []byte(`SELECT 1;`)},
+			"0001_create_orders.down.sql": {Data: []byte(`SELECT 1;`)},
+			"0001_create_carts.up.sql":    {Data: []byte(`SELECT 1;`)},
+		}, "version 1 is already used"},
+		{"unexpected name", fstest.MapFS{
+			"create_orders.sql": {Data: []byte(`SELECT 1;`)},
+		}, "create_orders.sql: expected <version>_<name>.up.sql"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(tc.files)
+
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+// Feature: Migrating the schema
+// Scenario: Apply the pending migrations
+func Test_Up_ShouldApplyPendingMigrationsOnce(t *testing.T) {
+	// GIVEN a new database
+	db := newTestDB(t)
+	lock := &countingLock{}
+	migrator := newTestMigrator(t, db, testFiles, lock)
+
+	// WHEN it is migrated twice
+	require.NoError(t, migrator.Up(context.Background()))
+	require.NoError(t, migrator.Up(context.Background()))
+
+	// THEN every migration should have been applied, under the lock
+	assert.Equal(t, []uint{1, 2, 3}, appliedVersions(t, migrator))
+	assert.True(t, tableExists(t, db, "products"))
+	assert.Equal(t, 2, lock.locked)
+	assert.Equal(t, 2, lock.unlocked)
+	assert.NoError(t, migrator.Check(context.Background()))
+}
+
+func Test_Up_WithFailingMigration_ShouldKeepPreviousVersion(t *testing.T) {
+	// GIVEN a migration that fails halfway
+	db := newTestDB(t)
+	files := fstest.MapFS{
+		"0001_create_orders.up.sql":     testFiles["0001_create_orders.up.sql"],
+		"0001_create_orders.down.sql":   testFiles["0001_create_orders.down.sql"],
+		"0002_create_products.up.sql":   {Data: []byte(`CREATE TABLE products (id INTEGER); CREATE TABLE orders (id INTEGER);`)},
+		"0002_create_products.down.sql": {Data: []byte(`DROP TABLE products;`)},
+	}
+	migrator := newTestMigrator(t, db, files, nil)
+
+	// WHEN the database is migrated
+	err := migrator.Up(context.Background())
+
+	// THEN the failing migration should be rolled back entirely
+	assert.ErrorContains(t, err, "migration 2_create_products")
+	assert.Equal(t, []uint{1}, appliedVersions(t, migrator))
+	assert.False(t, tableExists(t, db, "products"))
+}
+
+// Scenario: Revert migrations
+func Test_Down_ShouldRevertLastMigrations(t *testing.T) {
+	// GIVEN a migrated database
+	db := newTestDB(t)
+	migrator := newTestMigrator(t, db, testFiles, nil)
+	require.NoError(t, migrator.Up(context.Background()))
+
+	// WHEN the last two migrations are reverted
+	err := migrator.Down(context.Background(), 2)
+
+	// THEN only the first one should remain
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1}, appliedVersions(t, migrator))
+	assert.False(t, tableExists(t, db, "products"))
+	assert.True(t, tableExists(t, db, "orders"))
+}
+
+func Test_Goto_ShouldMoveToVersion(t *testing.T) {
+	// GIVEN a database at version 1
+	db := newTestDB(t)
+	migrator := newTestMigrator(t, db, testFiles, nil)
+	require.NoError(t, migrator.Goto(context.Background(), 1))
+	assert.Equal(t, []uint{1}, appliedVersions(t, migrator))
+
+	// WHEN it is moved to version 3, then back to 0
+	require.NoError(t, migrator.Goto(context.Background(), 3))
+	assert.Equal(t, []uint{1, 2, 3}, appliedVersions(t, migrator))
+	require.NoError(t, migrator.Goto(context.Background(), 0))
+
+	// THEN every migration should have been reverted
+	assert.Empty(t, appliedVersions(t, migrator))
+	assert.False(t, tableExists(t, db, "orders"))
+	assert.ErrorContains(t, migrator.Goto(context.Background(), 7), "unknown migration version 7")
+}
+
+// Scenario: Check the schema on start
+func Test_Check_WithPendingMigrations_ShouldFailWithoutMigrating(t *testing.T) {
+	// GIVEN a database one migration behind
+	db := newTestDB(t)
+	migrator := newTestMigrator(t, db, testFiles, nil)
+	require.NoError(t, migrator.Goto(context.Background(), 2))
+
+	// WHEN the schema is checked
+	err := migrator.Check(context.Background())
+
+	// THEN it should be reported outdated and left as is
+	assert.ErrorIs(t, err, ErrSchemaOutdated)
+	assert.ErrorContains(t, err, "pending migrations [3]")
+	assert.False(t, tableExists(t, db, "products"))
+}
+
+func Test_Check_WithNewerSchema_ShouldFail(t *testing.T) {
+	// GIVEN a database migrated by a newer release
+	db := newTestDB(t)
+	require.NoError(t, newTestMigrator(t, db, testFiles, nil).Up(context.Background()))
+	older := fstest.MapFS{
+		"0001_create_orders.up.sql":   testFiles["0001_create_orders.up.sql"],
+		"0001_create_orders.down.sql": testFiles["0001_create_orders.down.sql"],
+	}
+
+	// WHEN an older release checks the schema
+	err := newTestMigrator(t, db, older, nil).Check(context.Background())
+
+	// THEN the unknown migrations should be reported
+	assert.ErrorIs(t, err, ErrSchemaOutdated)
+	assert.ErrorContains(t, err, "unknown migrations [2 3]")
+}
+
+func Test_Check_WithNewDatabase_ShouldFailWithoutWriting(t *testing.T) {
+	// GIVEN a database never migrated
+	db := newTestDB(t)
+	migrator := newTestMigrator(t, db, testFiles, nil)
+
+	// WHEN the schema is checked
+	err := migrator.Check(context.Background())
+
+	// THEN it should fail without creating schema_migrations
+	assert.ErrorContains(t, err, "failed to read schema_migrations")
+	assert.False(t, tableExists(t, db, "schema_migrations"))
+}
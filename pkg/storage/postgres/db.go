@@ -1,51 +1,58 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"os"
+	"strings"
 
-	orderEntities "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/gormdb"
+	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func NewPostgresDB() *gorm.DB {
-	dsn := newDBConfig()
-	db := newDB(dsn)
-	migrate(db)
+func NewPostgresDB(cfg *config.Config, logger *zap.Logger, registerer prometheus.Registerer) *gorm.DB {
+	db, err := Open(cfg)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	if err := gormdb.Instrument(db, registerer); err != nil {
+		logger.Fatal("Failed to instrument database", zap.Error(err))
+	}
+	if err := migrateOnStart(cfg, logger, db); err != nil {
+		logger.Fatal("Failed to migrate database", zap.Error(err))
+	}
 	return db
 }
 
-func newDBConfig() string {
-	host := os.Getenv("DB_HOST")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	port := os.Getenv("DB_PORT")
-	sslmode := os.Getenv("DB_SSLMODE")
-
-	if host == "" || user == "" || password == "" || dbname == "" || port == "" || sslmode == "" {
-		log.Fatalf("Database environment variables are not properly set")
-	}
-
-	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s", host, user, password, dbname, port, sslmode)
+// Open connects to the configured database.
+func Open(cfg *config.Config) (*gorm.DB, error) {
+	return gormdb.Open(postgres.Open(dsn(cfg)))
 }
 
-func newDB(dsn string) *gorm.DB {
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// migrateOnStart applies the pending migrations, or with the check migration
+// mode only makes sure they were applied beforehand by cmd/migrate.
+func migrateOnStart(cfg *config.Config, logger *zap.Logger, db *gorm.DB) error {
+	migrator, err := NewMigrator(db, logger)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		return err
 	}
-	return db
+	if cfg.DBMigrationMode == config.MigrationModeCheck {
+		return migrator.Check(context.Background())
+	}
+	return migrator.Up(context.Background())
 }
 
-func migrate(db *gorm.DB) {
-	// Only migrate order-related entities (isolated microservice database)
-	if err := db.AutoMigrate(
-		&orderEntities.OrderEntity{},
-		&orderEntities.OrderProductEntity{},
-		&orderEntities.OrderStatusEntity{}); err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
-	}
+// dsn builds the connection string of the configured database. Values are
+// quoted so passwords may contain spaces and quotes.
+func dsn(cfg *config.Config) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		quoteDSNValue(cfg.DBHost), quoteDSNValue(cfg.DBUser), quoteDSNValue(cfg.DBPassword.Value()),
+		quoteDSNValue(cfg.DBName), quoteDSNValue(cfg.DBPort), quoteDSNValue(cfg.DBSSLMode))
+}
+
+func quoteDSNValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
 }
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+)
+
+// Feature: Database connection
+// Scenario: Connect with any password
+func Test_DSN_WithSpecialCharacters_ShouldKeepValuesIntact(t *testing.T) {
+	// GIVEN a password with spaces, quotes and backslashes
+	cfg := &config.Config{
+		DBHost:     "db.internal",
+		DBPort:     "5432",
+		DBUser:     "order_user",
+		DBPassword: config.Secret(`p@ss w'or\d`),
+		DBName:     "order_db",
+		DBSSLMode:  "require",
+	}
+
+	// WHEN the connection string is built and parsed by the driver
+	parsed, err := pgx.ParseConfig(dsn(cfg))
+
+	// THEN each value should reach the driver unchanged
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal", parsed.Host)
+	assert.Equal(t, uint16(5432), parsed.Port)
+	assert.Equal(t, "order_user", parsed.User)
+	assert.Equal(t, `p@ss w'or\d`, parsed.Password)
+	assert.Equal(t, "order_db", parsed.Database)
+}
+
+// Feature: Database migrations
+func Test_Migrations_ShouldBeNumberedWithoutGaps(t *testing.T) {
+	// GIVEN the embedded migrations
+	// WHEN they are loaded
+	migrations, err := Migrations()
+
+	// THEN each version should follow the previous one
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, uint(i+1), migration.Version, migration.Name)
+	}
+}
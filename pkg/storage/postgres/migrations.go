@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/migrate"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey identifies the advisory lock taken while migrating, shared
+// by every replica and by cmd/migrate.
+const migrationLockKey = 5050_0001
+
+// Migrations returns the migrations of the order database.
+func Migrations() ([]migrate.Migration, error) {
+	files, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return migrate.Load(files)
+}
+
+// NewMigrator creates the migrator of the order database. Concurrent migrators
+// wait for each other through a Postgres advisory lock.
+func NewMigrator(db *gorm.DB, logger *zap.Logger) (*migrate.Migrator, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqlDB, migrations, advisoryLock{key: migrationLockKey}, logger), nil
+}
+
+// advisoryLock is a session level Postgres advisory lock, released when the
+// connection holding it closes if not before.
+type advisoryLock struct {
+	key int64
+}
+
+func (l advisoryLock) Lock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, l.key)
+	return err
+}
+
+func (l advisoryLock) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key)
+	return err
+}
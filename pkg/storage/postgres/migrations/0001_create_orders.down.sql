@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "order_status";
+DROP TABLE IF EXISTS "order_product";
+DROP TABLE IF EXISTS "order";
@@ -0,0 +1,30 @@
+-- Baseline schema, exactly as GORM AutoMigrate created it before versioned
+-- migrations. IF NOT EXISTS makes it a no-op on those databases; everything
+-- added to the entities since is in the following migrations.
+CREATE TABLE IF NOT EXISTS "order" (
+    "id" bigserial PRIMARY KEY,
+    "created_at" timestamptz DEFAULT current_timestamp,
+    "total_amount" decimal DEFAULT 0,
+    "customer_id" bigint
+);
+CREATE INDEX IF NOT EXISTS "idx_order_customer_id" ON "order" ("customer_id");
+
+CREATE TABLE IF NOT EXISTS "order_product" (
+    "id" bigserial PRIMARY KEY,
+    "order_id" bigint,
+    "product_id" bigint,
+    "price" decimal NOT NULL,
+    "quantity" bigint NOT NULL,
+    CONSTRAINT "fk_order_products" FOREIGN KEY ("order_id") REFERENCES "order" ("id") ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_order_product_order_id" ON "order_product" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_order_product_product_id" ON "order_product" ("product_id");
+
+CREATE TABLE IF NOT EXISTS "order_status" (
+    "id" bigserial PRIMARY KEY,
+    "created_at" timestamptz DEFAULT current_timestamp,
+    "current_status" bigint NOT NULL,
+    "order_id" bigint,
+    CONSTRAINT "fk_order_status" FOREIGN KEY ("order_id") REFERENCES "order" ("id") ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_order_status_order_id" ON "order_status" ("order_id");
@@ -0,0 +1,6 @@
+-- Convert the bigint cents back to the decimal money of the baseline
+-- (3499 -> 34.99).
+ALTER TABLE "order" ALTER COLUMN "total_amount" DROP DEFAULT;
+ALTER TABLE "order" ALTER COLUMN "total_amount" TYPE decimal USING ROUND("total_amount" / 100.0, 2);
+ALTER TABLE "order" ALTER COLUMN "total_amount" SET DEFAULT 0;
+ALTER TABLE "order_product" ALTER COLUMN "price" TYPE decimal USING ROUND("price" / 100.0, 2);
@@ -0,0 +1,19 @@
+-- The baseline stored money as decimal (float in the entities): convert it to
+-- bigint cents (34.99 -> 3499). Columns already converted are left untouched.
+DO $$
+DECLARE
+    money_column record;
+BEGIN
+    FOR money_column IN
+        SELECT table_name, column_name
+        FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND (table_name, column_name) IN (('order', 'total_amount'), ('order_product', 'price'))
+          AND data_type IN ('real', 'double precision', 'numeric')
+    LOOP
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN %I DROP DEFAULT', money_column.table_name, money_column.column_name);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN %I TYPE bigint USING ROUND(%I * 100)::bigint',
+            money_column.table_name, money_column.column_name, money_column.column_name);
+    END LOOP;
+END $$;
+ALTER TABLE "order" ALTER COLUMN "total_amount" SET DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_order_current_status";
+ALTER TABLE "order" DROP COLUMN IF EXISTS "status_updated_at";
+ALTER TABLE "order" DROP COLUMN IF EXISTS "current_status";
@@ -0,0 +1,18 @@
+-- Copy the current status of each order onto the order, so the active orders
+-- are found through an index instead of the whole status history. The service
+-- keeps both columns up to date in the transaction of each status insert.
+ALTER TABLE "order" ADD COLUMN IF NOT EXISTS "current_status" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "order" ADD COLUMN IF NOT EXISTS "status_updated_at" timestamptz;
+
+-- Backfill from the latest status entry of each order
+UPDATE "order"
+SET "current_status" = latest."current_status",
+    "status_updated_at" = latest."created_at"
+FROM (
+    SELECT DISTINCT ON ("order_id") "order_id", "current_status", "created_at"
+    FROM "order_status"
+    ORDER BY "order_id", "id" DESC
+) AS latest
+WHERE latest."order_id" = "order"."id";
+
+CREATE INDEX IF NOT EXISTS "idx_order_current_status" ON "order" ("current_status", "created_at");
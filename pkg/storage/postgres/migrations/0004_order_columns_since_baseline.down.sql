@@ -0,0 +1,7 @@
+-- customer_id stays nullable: guest orders have none.
+DROP TABLE IF EXISTS "idempotency_key";
+ALTER TABLE "order_status" DROP COLUMN IF EXISTS "changed_by";
+ALTER TABLE "order_status" DROP COLUMN IF EXISTS "reason_code";
+DROP INDEX IF EXISTS "idx_order_customer_reconciliation_pending";
+ALTER TABLE "order" DROP COLUMN IF EXISTS "customer_reconciliation_pending";
+ALTER TABLE "order" DROP COLUMN IF EXISTS "guest_name";
@@ -0,0 +1,20 @@
+-- Columns and tables the entities gained after the baseline: guest orders
+-- without a customer, deferred customer verification, cancellation reasons
+-- and idempotency keys. None of the earlier migrations use them. IF NOT
+-- EXISTS keeps it a no-op where AutoMigrate already added them.
+ALTER TABLE "order" ALTER COLUMN "customer_id" DROP NOT NULL;
+ALTER TABLE "order" ADD COLUMN IF NOT EXISTS "guest_name" varchar(100);
+ALTER TABLE "order" ADD COLUMN IF NOT EXISTS "customer_reconciliation_pending" boolean DEFAULT false;
+CREATE INDEX IF NOT EXISTS "idx_order_customer_reconciliation_pending" ON "order" ("customer_reconciliation_pending");
+
+ALTER TABLE "order_status" ADD COLUMN IF NOT EXISTS "reason_code" varchar(50);
+ALTER TABLE "order_status" ADD COLUMN IF NOT EXISTS "changed_by" varchar(100);
+
+CREATE TABLE IF NOT EXISTS "idempotency_key" (
+    "key" varchar(255) PRIMARY KEY,
+    "request_hash" varchar(64) NOT NULL,
+    "order_id" bigint NOT NULL,
+    "created_at" timestamptz DEFAULT current_timestamp,
+    "expires_at" timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "idx_idempotency_key_expires_at" ON "idempotency_key" ("expires_at");
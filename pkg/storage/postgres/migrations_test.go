@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/gormdb"
+)
+
+// The entities as they were before versioned migrations, to create the
+// baseline schema the way GORM AutoMigrate did.
+type baselineOrder struct {
+	ID          uint                    `gorm:"primaryKey"`
+	CreatedAt   time.Time               `gorm:"default:current_timestamp"`
+	TotalAmount float32                 `gorm:"default:0"`
+	CustomerId  uint                    `gorm:"index"`
+	Products    []*baselineOrderProduct `gorm:"foreignKey:OrderId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Status      []*baselineOrderStatus  `gorm:"foreignKey:OrderId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (baselineOrder) TableName() string { return "order" }
+
+type baselineOrderProduct struct {
+	ID        uint    `gorm:"primaryKey"`
+	OrderId   uint    `gorm:"index"`
+	ProductId uint    `gorm:"index"`
+	Price     float32 `gorm:"not null"`
+	Quantity  uint    `gorm:"not null"`
+}
+
+func (baselineOrderProduct) TableName() string { return "order_product" }
+
+type baselineOrderStatus struct {
+	ID            uint      `gorm:"primaryKey"`
+	CreatedAt     time.Time `gorm:"default:current_timestamp"`
+	CurrentStatus uint      `gorm:"not null"`
+	OrderId       uint      `gorm:"index"`
+}
+
+func (baselineOrderStatus) TableName() string { return "order_status" }
+
+// openTestSchema connects to the database of the DB_* settings when
+// TEST_POSTGRES is set, in a schema of its own dropped after the test.
+func openTestSchema(t *testing.T, schema string) *gorm.DB {
+	if os.Getenv("TEST_POSTGRES") == "" {
+		t.Skip("TEST_POSTGRES not set")
+	}
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	admin, err := Open(cfg)
+	require.NoError(t, err)
+	require.NoError(t, admin.Exec(`DROP SCHEMA IF EXISTS `+schema+` CASCADE`).Error)
+	require.NoError(t, admin.Exec(`CREATE SCHEMA `+schema).Error)
+
+	db, err := gormdb.Open(postgres.Open(dsn(cfg) + " search_path=" + schema))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+		_ = admin.Exec(`DROP SCHEMA IF EXISTS ` + schema + ` CASCADE`).Error
+		if sqlDB, err := admin.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	return db
+}
+
+type schemaColumn struct {
+	TableName  string
+	ColumnName string
+	DataType   string
+	IsNullable string
+}
+
+func schemaColumns(t *testing.T, db *gorm.DB, schema string) []schemaColumn {
+	var columns []schemaColumn
+	require.NoError(t, db.Raw(`SELECT table_name, column_name, data_type, is_nullable
+		FROM information_schema.columns WHERE table_schema = ?
+		ORDER BY table_name, column_name`, schema).Scan(&columns).Error)
+	return columns
+}
+
+func schemaIndexes(t *testing.T, db *gorm.DB, schema string) []string {
+	var indexes []string
+	require.NoError(t, db.Raw(`SELECT indexname FROM pg_indexes WHERE schemaname = ? ORDER BY indexname`, schema).
+		Scan(&indexes).Error)
+	return indexes
+}
+
+// Scenario: Upgrade a database created by AutoMigrate
+func Test_Migrations_FromBaselineSchema_ShouldMatchNewDatabase(t *testing.T) {
+	// GIVEN a database created by AutoMigrate before versioned migrations, with an order
+	baseline := openTestSchema(t, "migrations_baseline")
+	require.NoError(t, baseline.AutoMigrate(&baselineOrder{}, &baselineOrderProduct{}, &baselineOrderStatus{}))
+	order := &baselineOrder{
+		TotalAmount: 34.99,
+		CustomerId:  1,
+		Products:    []*baselineOrderProduct{{ProductId: 10, Price: 12.5, Quantity: 2}},
+		Status: []*baselineOrderStatus{
+			{CurrentStatus: 1, CreatedAt: time.Now().Add(-time.Minute)},
+			{CurrentStatus: 2, CreatedAt: time.Now()},
+		},
+	}
+	require.NoError(t, baseline.Create(order).Error)
+	// AND a new database
+	fresh := openTestSchema(t, "migrations_fresh")
+
+	// WHEN both are migrated
+	for _, db := range []*gorm.DB{baseline, fresh} {
+		migrator, err := NewMigrator(db, zap.NewNop())
+		require.NoError(t, err)
+		require.NoError(t, migrator.Up(context.Background()))
+		require.NoError(t, migrator.Check(context.Background()))
+	}
+
+	// THEN they should end up with the same schema
+	assert.Equal(t, schemaColumns(t, fresh, "migrations_fresh"), schemaColumns(t, baseline, "migrations_baseline"))
+	assert.Equal(t, schemaIndexes(t, fresh, "migrations_fresh"), schemaIndexes(t, baseline, "migrations_baseline"))
+	// AND the existing order should have been converted and backfilled
+	var migrated struct {
+		TotalAmount   int64
+		GuestName     *string
+		CurrentStatus uint
+	}
+	require.NoError(t, baseline.Raw(`SELECT total_amount, guest_name, current_status FROM "order" WHERE id = ?`, order.ID).
+		Scan(&migrated).Error)
+	assert.Equal(t, int64(3499), migrated.TotalAmount)
+	assert.Nil(t, migrated.GuestName)
+	assert.Equal(t, uint(2), migrated.CurrentStatus)
+	var price int64
+	require.NoError(t, baseline.Raw(`SELECT price FROM order_product WHERE order_id = ?`, order.ID).Scan(&price).Error)
+	assert.Equal(t, int64(1250), price)
+}
+
+// Scenario: Roll back to the baseline
+func Test_Migrations_DownToBaseline_ShouldRestoreBaselineSchema(t *testing.T) {
+	// GIVEN a database created by AutoMigrate, with an order, then migrated
+	db := openTestSchema(t, "migrations_down")
+	require.NoError(t, db.AutoMigrate(&baselineOrder{}, &baselineOrderProduct{}, &baselineOrderStatus{}))
+	baselineColumns := schemaColumns(t, db, "migrations_down")
+	order := &baselineOrder{
+		TotalAmount: 34.99,
+		CustomerId:  1,
+		Products:    []*baselineOrderProduct{{ProductId: 10, Price: 12.5, Quantity: 2}},
+	}
+	require.NoError(t, db.Create(order).Error)
+	migrator, err := NewMigrator(db, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(context.Background()))
+
+	// WHEN it is rolled back to the baseline
+	require.NoError(t, migrator.Goto(context.Background(), 1))
+
+	// THEN the money should be decimal again, with its value
+	var money struct {
+		TotalAmount float64
+		Price       float64
+	}
+	require.NoError(t, db.Raw(`SELECT total_amount, price
+		FROM "order" JOIN order_product ON order_product.order_id = "order".id WHERE "order".id = ?`, order.ID).
+		Scan(&money).Error)
+	assert.Equal(t, 34.99, money.TotalAmount)
+	assert.Equal(t, 12.5, money.Price)
+	// AND the columns should be the baseline ones again
+	assert.Equal(t, baselineColumns, schemaColumns(t, db, "migrations_down"))
+}
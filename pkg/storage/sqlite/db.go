@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	orderEntities "github.com/viniciuscluna/tc-fiap-50/internal/order/domain/entities"
+	"github.com/viniciuscluna/tc-fiap-50/internal/shared/config"
+	"github.com/viniciuscluna/tc-fiap-50/pkg/storage/gormdb"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// NewSQLiteDB opens the SQLite database at SQLITE_PATH, to run the service
+// without Postgres. The versioned migrations are written for Postgres, so the
+// schema is created from the entities instead.
+func NewSQLiteDB(cfg *config.Config, logger *zap.Logger, registerer prometheus.Registerer) *gorm.DB {
+	db, err := gormdb.Open(sqlite.Open(cfg.SQLitePath))
+	if err != nil {
+		logger.Fatal("Failed to open SQLite database", zap.Error(err), zap.String("path", cfg.SQLitePath))
+	}
+
+	// SQLite allows a single writer, and each connection to :memory: would
+	// get a database of its own
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatal("Failed to open SQLite database", zap.Error(err), zap.String("path", cfg.SQLitePath))
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := gormdb.Instrument(db, registerer); err != nil {
+		logger.Fatal("Failed to instrument database", zap.Error(err))
+	}
+	if err := db.AutoMigrate(
+		&orderEntities.OrderEntity{},
+		&orderEntities.OrderProductEntity{},
+		&orderEntities.OrderStatusEntity{},
+		&orderEntities.IdempotencyKeyEntity{}); err != nil {
+		logger.Fatal("Failed to migrate database", zap.Error(err))
+	}
+	if err := db.Exec(backfillCurrentStatus).Error; err != nil {
+		logger.Fatal("Failed to migrate database", zap.Error(err))
+	}
+	return db
+}
+
+// backfillCurrentStatus copies the latest status entry onto the orders created
+// before order.current_status existed, like the Postgres migration 0003.
+const backfillCurrentStatus = `
+UPDATE "order"
+SET current_status = (
+        SELECT current_status FROM order_status WHERE order_status.order_id = "order".id ORDER BY id DESC LIMIT 1),
+    status_updated_at = (
+        SELECT created_at FROM order_status WHERE order_status.order_id = "order".id ORDER BY id DESC LIMIT 1)
+WHERE current_status = 0
+  AND EXISTS (SELECT 1 FROM order_status WHERE order_status.order_id = "order".id)`